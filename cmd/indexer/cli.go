@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/javaBin/talks-indexer/internal/ports"
+)
+
+const usage = `Usage:
+  indexer                  start the HTTP server
+  indexer reindex [flags]  run a reindex once and print its report as JSON
+  indexer export [flags]   write a snapshot of an index as NDJSON
+  indexer import [flags]   restore a snapshot into a fresh index and print the result as JSON
+
+`
+
+// runCommand runs a one-off command given on the command line instead of the HTTP
+// server and returns the process exit code. Logs go to stderr so the report written
+// to stdout can be piped.
+func runCommand(indexer ports.Indexer, snapshots ports.Snapshotter, args []string, stdout, stderr io.Writer) int {
+	switch args[0] {
+	case "reindex":
+		return runReindex(indexer, args[1:], stdout, stderr)
+	case "export":
+		return runExport(snapshots, args[1:], stdout, stderr)
+	case "import":
+		return runImport(snapshots, args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		flags, _ := reindexFlags(stderr)
+		flags.Usage()
+		return 2
+	}
+}
+
+// reindexOptions holds the parsed flags of the reindex command
+type reindexOptions struct {
+	dryRun     bool
+	force      bool
+	conference string
+	talk       string
+}
+
+// reindexFlags returns the flag set of the reindex command
+func reindexFlags(output io.Writer) (*flag.FlagSet, *reindexOptions) {
+	opts := &reindexOptions{}
+	flags := flag.NewFlagSet("reindex", flag.ContinueOnError)
+	flags.SetOutput(output)
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "show what would change without writing to the indexes")
+	flags.BoolVar(&opts.force, "force", false, "publish even if an index would shrink beyond the safety threshold")
+	flags.StringVar(&opts.conference, "conference", "", "reindex only the conference with this slug")
+	flags.StringVar(&opts.talk, "talk", "", "reindex only the talk with this ID")
+	flags.Usage = func() {
+		fmt.Fprint(output, usage+"Reindex flags:\n")
+		flags.PrintDefaults()
+	}
+	return flags, opts
+}
+
+// runReindex runs a full, conference or talk reindex and writes the report to stdout
+func runReindex(indexer ports.Indexer, args []string, stdout, stderr io.Writer) int {
+	flags, opts := reindexFlags(stderr)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if opts.conference != "" && opts.talk != "" {
+		fmt.Fprintln(stderr, "-conference and -talk cannot be combined")
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	reindexOpts := domain.ReindexOptions{DryRun: opts.dryRun, Force: opts.force}
+
+	var report *domain.ReindexReport
+	var err error
+	switch {
+	case opts.conference != "":
+		report, err = indexer.ReindexConference(ctx, opts.conference, reindexOpts)
+	case opts.talk != "":
+		report, err = indexer.ReindexTalk(ctx, opts.talk, reindexOpts)
+	default:
+		report, err = indexer.ReindexAll(ctx, reindexOpts)
+	}
+
+	if report != nil {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if encodeErr := encoder.Encode(report); encodeErr != nil {
+			fmt.Fprintf(stderr, "failed to write report: %v\n", encodeErr)
+			return 1
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "reindex failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// snapshotFlags returns the flag set of the export or import command. The file flag
+// is -out for export and -in for import; "-" means stdout or stdin.
+func snapshotFlags(command, fileFlag, fileUsage string, output io.Writer) (*flag.FlagSet, *string, *string) {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(output)
+	index := flags.String("index", "", "alias of the index, e.g. javazone_public (required)")
+	file := flags.String(fileFlag, "-", fileUsage)
+	flags.Usage = func() {
+		fmt.Fprint(output, usage+strings.ToUpper(command[:1])+command[1:]+" flags:\n")
+		flags.PrintDefaults()
+	}
+	return flags, index, file
+}
+
+// runExport writes a snapshot of an index to a file or stdout
+func runExport(snapshots ports.Snapshotter, args []string, stdout, stderr io.Writer) int {
+	flags, index, out := snapshotFlags("export", "out", "file to write the snapshot to, - for stdout", stderr)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *index == "" {
+		fmt.Fprintln(stderr, "-index is required")
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	w := stdout
+	if *out != "-" {
+		file, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(stderr, "export failed: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		w = file
+	}
+
+	header, err := snapshots.ExportSnapshot(ctx, *index, w)
+	if err != nil {
+		fmt.Fprintf(stderr, "export failed: %v\n", err)
+		if *out != "-" {
+			os.Remove(*out)
+		}
+		return 1
+	}
+
+	if *out != "-" {
+		fmt.Fprintf(stderr, "exported %d documents from %s to %s\n", header.Documents, header.SourceIndex, *out)
+	}
+	return 0
+}
+
+// runImport restores a snapshot from a file or stdin and writes the result to stdout
+func runImport(snapshots ports.Snapshotter, args []string, stdout, stderr io.Writer) int {
+	flags, index, in := snapshotFlags("import", "in", "file to read the snapshot from, - for stdin", stderr)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *index == "" {
+		fmt.Fprintln(stderr, "-index is required")
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var r io.Reader = os.Stdin
+	if *in != "-" {
+		file, err := os.Open(*in)
+		if err != nil {
+			fmt.Fprintf(stderr, "import failed: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		r = file
+	}
+
+	result, err := snapshots.RestoreSnapshot(ctx, *index, r)
+
+	if result != nil {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if encodeErr := encoder.Encode(result); encodeErr != nil {
+			fmt.Fprintf(stderr, "failed to write result: %v\n", encodeErr)
+			return 1
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "import failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -19,20 +20,28 @@ import (
 	"github.com/javaBin/talks-indexer/internal/adapters/web/handlers"
 	"github.com/javaBin/talks-indexer/internal/app"
 	"github.com/javaBin/talks-indexer/internal/config"
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/javaBin/talks-indexer/internal/ports"
 )
 
 func main() {
 	// Load configuration first to determine logging mode
 	cfg := config.MustLoad()
 
+	// Commands print their output to stdout, so their logs go to stderr
+	logOutput := os.Stdout
+	if len(os.Args) > 1 {
+		logOutput = os.Stderr
+	}
+
 	// Configure logging based on mode
 	var logger *slog.Logger
 	if cfg.Mode.IsDevelopment() {
-		logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		logger = slog.New(slog.NewTextHandler(logOutput, &slog.HandlerOptions{
 			Level: slog.LevelDebug,
 		}))
 	} else {
-		logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		logger = slog.New(slog.NewJSONHandler(logOutput, &slog.HandlerOptions{
 			Level: slog.LevelInfo,
 		}))
 	}
@@ -41,19 +50,75 @@ func main() {
 	logger.Info("configuration loaded",
 		"mode", cfg.Mode,
 		"port", cfg.Port,
+		"talkSource", cfg.TalkSource,
 		"moresleepURL", cfg.MoresleepURL,
 		"elasticsearchURL", cfg.ElasticsearchURL,
 		"privateIndex", cfg.PrivateIndex,
 		"publicIndex", cfg.PublicIndex,
+		"indexRetention", cfg.IndexRetention,
+		"stateIndex", cfg.StateIndex,
+		"lockTTL", cfg.LockTTL,
+		"fetchConcurrency", cfg.FetchConcurrency,
+		"fetchTimeout", cfg.FetchTimeout,
+		"conferenceCacheTTL", cfg.ConferenceCacheTTL,
+		"moresleepTimeout", cfg.MoresleepTimeout,
+		"moresleepMaxRetries", cfg.MoresleepMaxRetries,
+		"moresleepBreakerThreshold", cfg.MoresleepBreakerThreshold,
+		"bulkMaxDocs", cfg.BulkMaxDocs,
+		"bulkMaxBytes", cfg.BulkMaxBytes,
+		"bulkConcurrency", cfg.BulkConcurrency,
+		"indexShrinkThreshold", cfg.IndexShrinkThreshold,
+		"conferenceShrinkThreshold", cfg.ConferenceShrinkThreshold,
+		"mappingDriftPolicy", cfg.MappingDriftPolicy,
+		"reindexSchedule", cfg.ReindexSchedule,
+		"syncSchedule", cfg.SyncSchedule,
 	)
 
-	// Initialize moresleep client
-	moresleepClient := moresleep.New(
-		cfg.MoresleepURL,
-		cfg.MoresleepUser,
-		cfg.MoresleepPassword,
+	// Talks come from the live moresleep API or from a frozen dataset on disk
+	var (
+		talkSource  ports.TalkSource
+		conferences ports.ConferenceCatalog
+		breakers    []ports.CircuitBreaker
 	)
-	logger.Info("moresleep client initialized")
+	switch cfg.TalkSource {
+	case config.TalkSourceMoresleep:
+		moresleepClient := moresleep.New(
+			cfg.MoresleepURL,
+			cfg.MoresleepUser,
+			cfg.MoresleepPassword,
+		)
+		moresleepClient.SetTimeout(cfg.MoresleepTimeout)
+		moresleepClient.SetRetryOptions(moresleep.RetryOptions{
+			MaxRetries: cfg.MoresleepMaxRetries,
+			Backoff:    cfg.MoresleepRetryBackoff,
+			MaxBackoff: cfg.MoresleepMaxBackoff,
+		})
+		moresleepClient.SetBreakerOptions(moresleep.BreakerOptions{
+			FailureThreshold: cfg.MoresleepBreakerThreshold,
+			Cooldown:         cfg.MoresleepBreakerCooldown,
+		})
+		moresleepClient.Catalog().SetTTL(cfg.ConferenceCacheTTL)
+		logger.Info("moresleep client initialized")
+		talkSource = moresleepClient
+		conferences = moresleepClient.Catalog()
+		breakers = append(breakers, moresleepClient)
+	case config.TalkSourceFile:
+		if cfg.TalkSourceDir == "" {
+			logger.Error("TALK_SOURCE_DIR is required when TALK_SOURCE is file")
+			os.Exit(1)
+		}
+		fileSource, err := moresleep.NewFileSource(cfg.TalkSourceDir)
+		if err != nil {
+			logger.Error("failed to create file talk source", "error", err)
+			os.Exit(1)
+		}
+		talkSource = fileSource
+		conferences = fileSource
+		logger.Info("file talk source initialized", "dir", cfg.TalkSourceDir)
+	default:
+		logger.Error("invalid talk source, expected moresleep or file", "talkSource", cfg.TalkSource)
+		os.Exit(1)
+	}
 
 	// Initialize elasticsearch client
 	esClient, err := elasticsearch.New(
@@ -65,36 +130,119 @@ func main() {
 		logger.Error("failed to create elasticsearch client", "error", err)
 		os.Exit(1)
 	}
+	esClient.SetBulkOptions(elasticsearch.BulkOptions{
+		MaxDocs:      cfg.BulkMaxDocs,
+		MaxBytes:     cfg.BulkMaxBytes,
+		Concurrency:  cfg.BulkConcurrency,
+		MaxRetries:   cfg.BulkMaxRetries,
+		RetryBackoff: cfg.BulkRetryBackoff,
+	})
 	logger.Info("elasticsearch client initialized")
 
 	// Create indexer service
 	indexerService := app.NewIndexerService(
-		moresleepClient,
+		talkSource,
 		esClient,
 		cfg.PrivateIndex,
 		cfg.PublicIndex,
 		elasticsearch.TalkPrivateIndexMapping,
 		elasticsearch.TalkPublicIndexMapping,
 	)
+	indexerService.SetIndexRetention(cfg.IndexRetention)
+	indexerService.SetStateIndex(cfg.StateIndex)
+	indexerService.SetSpeakerIndexes(
+		cfg.SpeakersPrivateIndex,
+		cfg.SpeakersPublicIndex,
+		elasticsearch.SpeakerPrivateIndexMapping,
+		elasticsearch.SpeakerPublicIndexMapping,
+	)
+	indexerService.SetLockTTL(cfg.LockTTL)
+	indexerService.SetFetchConcurrency(cfg.FetchConcurrency)
+	indexerService.SetFetchTimeout(cfg.FetchTimeout)
+	indexerService.SetShrinkThresholds(cfg.IndexShrinkThreshold, cfg.ConferenceShrinkThreshold)
+	driftPolicy, err := domain.ParseMappingDriftPolicy(cfg.MappingDriftPolicy)
+	if err != nil {
+		logger.Error("invalid mapping drift policy", "error", err)
+		os.Exit(1)
+	}
+	indexerService.SetMappingDriftPolicy(driftPolicy)
 	logger.Info("indexer service initialized")
 
+	// A command on the command line runs once and exits instead of starting the server
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(indexerService, indexerService, os.Args[1:], os.Stdout, os.Stderr))
+	}
+
+	// Background jobs run detached from HTTP requests and their write timeout
+	jobManager := app.NewJobManager(indexerService)
+
+	// Indexes created with an older mapping are reported, refused or rebuilt in the background
+	drifts, err := indexerService.CheckMappings(context.Background())
+	switch {
+	case errors.Is(err, domain.ErrMappingDrift):
+		logger.Error("index mappings are out of date", "error", err)
+		os.Exit(1)
+	case err != nil:
+		logger.Warn("failed to check index mappings", "error", err)
+	case len(drifts) > 0 && driftPolicy == domain.MappingDriftRebuild:
+		if _, err := jobManager.Submit(domain.JobRequest{Type: domain.JobReindexAll}); err != nil {
+			logger.Error("failed to submit mapping rebuild", "error", err)
+		}
+	}
+
+	// Recurring reindex and sync runs are submitted as background jobs
+	scheduler := app.NewScheduler(jobManager)
+	scheduler.SetJitter(cfg.ScheduleJitter)
+	if cfg.ReindexSchedule != "" {
+		if err := scheduler.Add("reindex-all", domain.JobReindexAll, cfg.ReindexSchedule); err != nil {
+			logger.Error("invalid reindex schedule", "error", err)
+			os.Exit(1)
+		}
+	}
+	if cfg.SyncSchedule != "" {
+		if err := scheduler.Add("sync", domain.JobSync, cfg.SyncSchedule); err != nil {
+			logger.Error("invalid sync schedule", "error", err)
+			os.Exit(1)
+		}
+	}
+	scheduler.Start()
+
 	// Create HTTP server
 	mux := http.NewServeMux()
 
 	// Health check is always available
-	apiHandler := api.NewHandler(indexerService)
+	apiHandler := api.NewHandler(indexerService, jobManager)
+	apiHandler.SetScheduler(scheduler)
+	apiHandler.SetCircuitBreakers(breakers...)
 	api.RegisterHealthRoutes(mux, apiHandler)
 
+	// Search of the public index is always available, it only exposes approved talks
+	searchService := app.NewSearchService(esClient, cfg.PrivateIndex, cfg.PublicIndex)
+	api.RegisterSearchRoutes(mux, api.NewSearchHandler(searchService))
+
 	// API routes only available in development mode
 	if cfg.Mode.IsDevelopment() {
 		api.RegisterAPIRoutes(mux, apiHandler)
+		api.RegisterJobRoutes(mux, api.NewJobHandler(jobManager))
 		logger.Info("API routes enabled (development mode)")
 	} else {
 		logger.Info("API routes disabled (production mode)")
 	}
 
+	// moresleep change notifications are signed, so the webhook is available in every mode
+	if cfg.WebhookSecret != "" {
+		webhookHandler := api.NewWebhookHandler(jobManager, conferences, cfg.WebhookSecret)
+		webhookHandler.SetDedupeWindow(cfg.WebhookDedupeWindow)
+		api.RegisterWebhookRoutes(mux, webhookHandler)
+		logger.Info("moresleep webhook enabled", "dedupeWindow", cfg.WebhookDedupeWindow)
+	} else {
+		logger.Info("moresleep webhook disabled (WEBHOOK_SECRET not set)")
+	}
+
 	// Web admin dashboard
-	webHandler := handlers.NewHandler(indexerService, moresleepClient)
+	webHandler := handlers.NewHandler(indexerService, jobManager, conferences)
+	webHandler.SetScheduler(scheduler)
+	webHandler.SetSearcher(searchService)
 
 	// Set up authentication in production mode
 	if !cfg.Mode.IsDevelopment() && cfg.IsOIDCConfigured() {
@@ -163,5 +311,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := scheduler.Stop(ctx); err != nil {
+		logger.Error("scheduler shutdown error", "error", err)
+		os.Exit(1)
+	}
+
+	if err := jobManager.Stop(ctx); err != nil {
+		logger.Error("job manager shutdown error", "error", err)
+		os.Exit(1)
+	}
+
 	logger.Info("server stopped")
 }
@@ -4,12 +4,27 @@ import "github.com/javaBin/talks-indexer/internal/ports"
 
 // Handler holds the HTTP handler dependencies
 type Handler struct {
-	indexer ports.Indexer
+	indexer   ports.Indexer
+	jobs      *JobHandler
+	scheduler ports.Scheduler
+	breakers  []ports.CircuitBreaker
 }
 
-// NewHandler creates a new HTTP handler with the provided indexer service
-func NewHandler(indexer ports.Indexer) *Handler {
+// NewHandler creates a new HTTP handler with the provided indexer service.
+// Reindex and sync requests are queued as background jobs on the job manager.
+func NewHandler(indexer ports.Indexer, jobs ports.JobManager) *Handler {
 	return &Handler{
 		indexer: indexer,
+		jobs:    NewJobHandler(jobs),
 	}
 }
+
+// SetScheduler sets the scheduler whose runs are reported on the health endpoint
+func (h *Handler) SetScheduler(scheduler ports.Scheduler) {
+	h.scheduler = scheduler
+}
+
+// SetCircuitBreakers sets the circuit breakers whose state is reported on the health endpoint
+func (h *Handler) SetCircuitBreakers(breakers ...ports.CircuitBreaker) {
+	h.breakers = breakers
+}
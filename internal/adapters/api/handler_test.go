@@ -4,48 +4,68 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/javaBin/talks-indexer/internal/domain"
 	"github.com/stretchr/testify/assert"
 )
 
 // mockIndexer is a mock implementation of the Indexer interface for testing
 type mockIndexer struct {
-	reindexAllFunc        func(ctx context.Context) error
-	reindexConferenceFunc func(ctx context.Context, slug string) error
-	reindexTalkFunc       func(ctx context.Context, talkID string) error
+	reindexAllFunc        func(ctx context.Context, opts domain.ReindexOptions) (*domain.ReindexReport, error)
+	reindexConferenceFunc func(ctx context.Context, slug string, opts domain.ReindexOptions) (*domain.ReindexReport, error)
+	reindexTalkFunc       func(ctx context.Context, talkID string, opts domain.ReindexOptions) (*domain.ReindexReport, error)
+	syncChangesFunc       func(ctx context.Context, opts domain.ReindexOptions) (*domain.ReindexReport, error)
+	unmappedFieldsFunc    func(ctx context.Context) (*domain.UnmappedFieldReport, error)
 }
 
-func (m *mockIndexer) ReindexAll(ctx context.Context) error {
+func (m *mockIndexer) ReindexAll(ctx context.Context, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
 	if m.reindexAllFunc != nil {
-		return m.reindexAllFunc(ctx)
+		return m.reindexAllFunc(ctx, opts)
 	}
-	return nil
+	return domain.NewReindexReport("reindex-all", "", time.Now()), nil
 }
 
-func (m *mockIndexer) ReindexConference(ctx context.Context, slug string) error {
+func (m *mockIndexer) ReindexConference(ctx context.Context, slug string, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
 	if m.reindexConferenceFunc != nil {
-		return m.reindexConferenceFunc(ctx, slug)
+		return m.reindexConferenceFunc(ctx, slug, opts)
 	}
-	return nil
+	return domain.NewReindexReport("reindex-conference", slug, time.Now()), nil
 }
 
-func (m *mockIndexer) ReindexTalk(ctx context.Context, talkID string) error {
+func (m *mockIndexer) ReindexTalk(ctx context.Context, talkID string, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
 	if m.reindexTalkFunc != nil {
-		return m.reindexTalkFunc(ctx, talkID)
+		return m.reindexTalkFunc(ctx, talkID, opts)
 	}
-	return nil
+	return domain.NewReindexReport("reindex-talk", talkID, time.Now()), nil
+}
+
+func (m *mockIndexer) SyncChanges(ctx context.Context, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
+	if m.syncChangesFunc != nil {
+		return m.syncChangesFunc(ctx, opts)
+	}
+	report := domain.NewReindexReport("sync", "", time.Now())
+	report.Sync = &domain.SyncResult{}
+	return report, nil
+}
+
+func (m *mockIndexer) UnmappedFields(ctx context.Context) (*domain.UnmappedFieldReport, error) {
+	if m.unmappedFieldsFunc != nil {
+		return m.unmappedFieldsFunc(ctx)
+	}
+	return &domain.UnmappedFieldReport{}, nil
 }
 
 func TestNewHandler(t *testing.T) {
 	indexer := &mockIndexer{}
-	handler := NewHandler(indexer)
+	handler := NewHandler(indexer, &mockJobManager{})
 
 	assert.NotNil(t, handler)
 	assert.Equal(t, indexer, handler.indexer)
 }
 
 func TestNewHandler_WithNilIndexer(t *testing.T) {
-	handler := NewHandler(nil)
+	handler := NewHandler(nil, &mockJobManager{})
 
 	assert.NotNil(t, handler)
 	assert.Nil(t, handler.indexer)
@@ -53,7 +73,7 @@ func TestNewHandler_WithNilIndexer(t *testing.T) {
 
 func TestMockIndexer_ReindexAll_Default(t *testing.T) {
 	indexer := &mockIndexer{}
-	err := indexer.ReindexAll(context.Background())
+	_, err := indexer.ReindexAll(context.Background(), domain.ReindexOptions{})
 
 	assert.NoError(t, err)
 }
@@ -61,18 +81,18 @@ func TestMockIndexer_ReindexAll_Default(t *testing.T) {
 func TestMockIndexer_ReindexAll_WithError(t *testing.T) {
 	expectedError := errors.New("reindex error")
 	indexer := &mockIndexer{
-		reindexAllFunc: func(ctx context.Context) error {
-			return expectedError
+		reindexAllFunc: func(ctx context.Context, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
+			return nil, expectedError
 		},
 	}
 
-	err := indexer.ReindexAll(context.Background())
+	_, err := indexer.ReindexAll(context.Background(), domain.ReindexOptions{})
 	assert.Equal(t, expectedError, err)
 }
 
 func TestMockIndexer_ReindexConference_Default(t *testing.T) {
 	indexer := &mockIndexer{}
-	err := indexer.ReindexConference(context.Background(), "test-slug")
+	_, err := indexer.ReindexConference(context.Background(), "test-slug", domain.ReindexOptions{})
 
 	assert.NoError(t, err)
 }
@@ -80,11 +100,11 @@ func TestMockIndexer_ReindexConference_Default(t *testing.T) {
 func TestMockIndexer_ReindexConference_WithError(t *testing.T) {
 	expectedError := errors.New("conference reindex error")
 	indexer := &mockIndexer{
-		reindexConferenceFunc: func(ctx context.Context, slug string) error {
-			return expectedError
+		reindexConferenceFunc: func(ctx context.Context, slug string, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
+			return nil, expectedError
 		},
 	}
 
-	err := indexer.ReindexConference(context.Background(), "test-slug")
+	_, err := indexer.ReindexConference(context.Background(), "test-slug", domain.ReindexOptions{})
 	assert.Equal(t, expectedError, err)
 }
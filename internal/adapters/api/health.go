@@ -4,18 +4,36 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
 )
 
-// HealthResponse represents the health check response
+// HealthResponse represents the health check response.
+// Schedules lists the last and next run of each scheduled job, if any are configured.
+// CircuitBreakers lists the state of the breakers guarding external dependencies.
 type HealthResponse struct {
-	Status string `json:"status"`
+	Status          string                  `json:"status"`
+	Schedules       []domain.ScheduleStatus `json:"schedules,omitempty"`
+	CircuitBreakers []domain.BreakerStatus  `json:"circuitBreakers,omitempty"`
 }
 
-// HandleHealth handles the health check endpoint
+// HandleHealth handles the health check endpoint. The status is "degraded" while a
+// circuit breaker is not closed; the response code stays 200 since the service itself
+// is running and restarting it would not help the dependency recover.
 func (h *Handler) HandleHealth(w http.ResponseWriter, r *http.Request) {
 	response := HealthResponse{
 		Status: "ok",
 	}
+	if h.scheduler != nil {
+		response.Schedules = h.scheduler.Status()
+	}
+	for _, breaker := range h.breakers {
+		status := breaker.BreakerStatus()
+		if status.State != domain.BreakerClosed {
+			response.Status = "degraded"
+		}
+		response.CircuitBreakers = append(response.CircuitBreakers, status)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
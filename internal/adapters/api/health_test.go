@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/javaBin/talks-indexer/internal/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -13,7 +15,7 @@ import (
 func TestHandleHealth(t *testing.T) {
 	// Create handler with mock indexer
 	indexer := &mockIndexer{}
-	handler := NewHandler(indexer)
+	handler := NewHandler(indexer, &mockJobManager{})
 
 	// Create request
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
@@ -36,7 +38,7 @@ func TestHandleHealth(t *testing.T) {
 
 func TestHandleHealth_ContentType(t *testing.T) {
 	indexer := &mockIndexer{}
-	handler := NewHandler(indexer)
+	handler := NewHandler(indexer, &mockJobManager{})
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	w := httptest.NewRecorder()
@@ -49,7 +51,7 @@ func TestHandleHealth_ContentType(t *testing.T) {
 
 func TestHandleHealth_StatusCode(t *testing.T) {
 	indexer := &mockIndexer{}
-	handler := NewHandler(indexer)
+	handler := NewHandler(indexer, &mockJobManager{})
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	w := httptest.NewRecorder()
@@ -58,3 +60,78 @@ func TestHandleHealth_StatusCode(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+// mockScheduler is a mock implementation of ports.Scheduler
+type mockScheduler struct {
+	schedules []domain.ScheduleStatus
+}
+
+func (m *mockScheduler) Status() []domain.ScheduleStatus {
+	return m.schedules
+}
+
+func TestHandleHealth_Schedules(t *testing.T) {
+	lastRun := time.Date(2024, 9, 4, 3, 0, 12, 0, time.UTC)
+	nextRun := time.Date(2024, 9, 5, 3, 0, 7, 0, time.UTC)
+
+	handler := NewHandler(&mockIndexer{}, &mockJobManager{})
+	handler.SetScheduler(&mockScheduler{schedules: []domain.ScheduleStatus{
+		{Name: "reindex-all", Expression: "0 3 * * *", JobType: domain.JobReindexAll, LastRun: &lastRun, NextRun: &nextRun, LastJobID: "abc123"},
+	}})
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleHealth(w, req)
+
+	var response HealthResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+
+	assert.Equal(t, "ok", response.Status)
+	require.Len(t, response.Schedules, 1)
+	assert.Equal(t, "reindex-all", response.Schedules[0].Name)
+	assert.Equal(t, lastRun, *response.Schedules[0].LastRun)
+	assert.Equal(t, nextRun, *response.Schedules[0].NextRun)
+}
+
+// mockCircuitBreaker is a mock implementation of ports.CircuitBreaker
+type mockCircuitBreaker struct {
+	status domain.BreakerStatus
+}
+
+func (m *mockCircuitBreaker) BreakerStatus() domain.BreakerStatus {
+	return m.status
+}
+
+func TestHandleHealth_CircuitBreakers(t *testing.T) {
+	tests := []struct {
+		name   string
+		state  domain.BreakerState
+		status string
+	}{
+		{name: "closed", state: domain.BreakerClosed, status: "ok"},
+		{name: "open", state: domain.BreakerOpen, status: "degraded"},
+		{name: "half open", state: domain.BreakerHalfOpen, status: "degraded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(&mockIndexer{}, &mockJobManager{})
+			handler.SetCircuitBreakers(&mockCircuitBreaker{status: domain.BreakerStatus{Name: "moresleep", State: tt.state}})
+
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleHealth(w, req)
+
+			var response HealthResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.status, response.Status)
+			require.Len(t, response.CircuitBreakers, 1)
+			assert.Equal(t, "moresleep", response.CircuitBreakers[0].Name)
+			assert.Equal(t, tt.state, response.CircuitBreakers[0].State)
+		})
+	}
+}
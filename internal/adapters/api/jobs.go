@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/javaBin/talks-indexer/internal/ports"
+)
+
+// JobHandler holds the HTTP handler dependencies for background jobs
+type JobHandler struct {
+	jobs ports.JobManager
+}
+
+// NewJobHandler creates a new HTTP handler for submitting and polling jobs
+func NewJobHandler(jobs ports.JobManager) *JobHandler {
+	return &JobHandler{
+		jobs: jobs,
+	}
+}
+
+// JobResponse represents the response for a single job
+type JobResponse struct {
+	Status  string      `json:"status"`
+	Message string      `json:"message,omitempty"`
+	Job     *domain.Job `json:"job,omitempty"`
+}
+
+// JobListResponse represents the response for listing jobs
+type JobListResponse struct {
+	Status string       `json:"status"`
+	Jobs   []domain.Job `json:"jobs"`
+}
+
+// HandleSubmitJob queues a new background job and returns it immediately
+func (h *JobHandler) HandleSubmitJob(w http.ResponseWriter, r *http.Request) {
+	var req domain.JobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid job request", err)
+		return
+	}
+
+	h.submit(w, req)
+}
+
+// submit queues a job and answers with 202 Accepted and the location to poll it at
+func (h *JobHandler) submit(w http.ResponseWriter, req domain.JobRequest) {
+	job, err := h.jobs.Submit(req)
+	if err != nil {
+		slog.Error("failed to submit job", "type", req.Type, "target", req.Target, "error", err)
+		h.writeError(w, jobErrorStatus(err), "failed to submit job", err)
+		return
+	}
+
+	slog.Info("job submitted", "jobID", job.ID, "type", job.Type, "target", job.Target)
+
+	w.Header().Set("Location", "/api/jobs/"+job.ID)
+	h.writeJSON(w, http.StatusAccepted, JobResponse{
+		Status:  "accepted",
+		Message: "job queued",
+		Job:     job,
+	})
+}
+
+// HandleListJobs returns all known jobs, newest first
+func (h *JobHandler) HandleListJobs(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, JobListResponse{
+		Status: "success",
+		Jobs:   h.jobs.List(),
+	})
+}
+
+// HandleGetJob returns the current state of a job.
+// A job that succeeded but skipped conferences or documents gets 207 Multi-Status.
+func (h *JobHandler) HandleGetJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.jobs.Get(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, jobErrorStatus(err), "failed to get job", err)
+		return
+	}
+
+	if job.State == domain.JobSucceeded && job.Report != nil && job.Report.IsPartial() {
+		h.writeJSON(w, http.StatusMultiStatus, JobResponse{
+			Status:  "partial",
+			Message: fmt.Sprintf("job completed partially: %d conferences skipped, %d documents failed, %d warnings", len(job.Report.Skipped), len(job.Report.BulkFailures), len(job.Report.Warnings)),
+			Job:     job,
+		})
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, JobResponse{
+		Status: "success",
+		Job:    job,
+	})
+}
+
+// HandleCancelJob cancels a queued or running job
+func (h *JobHandler) HandleCancelJob(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	job, err := h.jobs.Cancel(id)
+	if err != nil {
+		h.writeError(w, jobErrorStatus(err), "failed to cancel job", err)
+		return
+	}
+
+	slog.Info("job cancellation requested", "jobID", id)
+
+	h.writeJSON(w, http.StatusOK, JobResponse{
+		Status:  "success",
+		Message: "job cancellation requested",
+		Job:     job,
+	})
+}
+
+// jobErrorStatus maps job manager errors to HTTP status codes
+func jobErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidJobRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrJobFinished):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrJobQueueFull):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeJSON writes a JSON response with the given status code
+func (h *JobHandler) writeJSON(w http.ResponseWriter, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("failed to encode job response", "error", err)
+	}
+}
+
+// writeError writes an error JSON response with the given status code
+func (h *JobHandler) writeError(w http.ResponseWriter, status int, message string, err error) {
+	response := JobResponse{
+		Status:  "error",
+		Message: message,
+	}
+	if err != nil {
+		response.Message = message + ": " + err.Error()
+	}
+
+	h.writeJSON(w, status, response)
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockJobManager is a mock implementation of the JobManager interface for testing
+type mockJobManager struct {
+	submitFunc func(req domain.JobRequest) (*domain.Job, error)
+	getFunc    func(id string) (*domain.Job, error)
+	listFunc   func() []domain.Job
+	cancelFunc func(id string) (*domain.Job, error)
+}
+
+func (m *mockJobManager) Submit(req domain.JobRequest) (*domain.Job, error) {
+	if m.submitFunc != nil {
+		return m.submitFunc(req)
+	}
+	return &domain.Job{ID: "job-1", Type: req.Type, Target: req.Target, State: domain.JobQueued}, nil
+}
+
+func (m *mockJobManager) Get(id string) (*domain.Job, error) {
+	if m.getFunc != nil {
+		return m.getFunc(id)
+	}
+	return &domain.Job{ID: id, State: domain.JobRunning}, nil
+}
+
+func (m *mockJobManager) List() []domain.Job {
+	if m.listFunc != nil {
+		return m.listFunc()
+	}
+	return []domain.Job{}
+}
+
+func (m *mockJobManager) Cancel(id string) (*domain.Job, error) {
+	if m.cancelFunc != nil {
+		return m.cancelFunc(id)
+	}
+	return &domain.Job{ID: id, State: domain.JobCancelled}, nil
+}
+
+func newJobMux(jobs *mockJobManager) *http.ServeMux {
+	mux := http.NewServeMux()
+	RegisterJobRoutes(mux, NewJobHandler(jobs))
+	return mux
+}
+
+func TestHandleSubmitJob_Success(t *testing.T) {
+	var submitted domain.JobRequest
+	mux := newJobMux(&mockJobManager{
+		submitFunc: func(req domain.JobRequest) (*domain.Job, error) {
+			submitted = req
+			return &domain.Job{ID: "abc123", Type: req.Type, Target: req.Target, State: domain.JobQueued}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/jobs", strings.NewReader(`{"type":"reindex-conference","target":"javazone2024"}`))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "/api/jobs/abc123", w.Header().Get("Location"))
+	assert.Equal(t, domain.JobRequest{Type: domain.JobReindexConference, Target: "javazone2024"}, submitted)
+
+	var response JobResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "accepted", response.Status)
+	require.NotNil(t, response.Job)
+	assert.Equal(t, "abc123", response.Job.ID)
+	assert.Equal(t, domain.JobQueued, response.Job.State)
+}
+
+func TestHandleSubmitJob_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "malformed body", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid request", body: `{"type":"bogus"}`, err: domain.ErrInvalidJobRequest, expectedStatus: http.StatusBadRequest},
+		{name: "queue full", body: `{"type":"sync"}`, err: domain.ErrJobQueueFull, expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := newJobMux(&mockJobManager{
+				submitFunc: func(req domain.JobRequest) (*domain.Job, error) {
+					return nil, tt.err
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/jobs", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response JobResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, "error", response.Status)
+		})
+	}
+}
+
+func TestHandleGetJob(t *testing.T) {
+	mux := newJobMux(&mockJobManager{
+		getFunc: func(id string) (*domain.Job, error) {
+			if id != "abc123" {
+				return nil, domain.ErrJobNotFound
+			}
+			return &domain.Job{
+				ID:    id,
+				State: domain.JobRunning,
+				Progress: []domain.ConferenceProgress{
+					{ConferenceID: "conf-1", State: domain.ProgressDone, Talks: 12},
+				},
+			}, nil
+		},
+	})
+
+	t.Run("found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/jobs/abc123", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response JobResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		require.NotNil(t, response.Job)
+		assert.Equal(t, domain.JobRunning, response.Job.State)
+		require.Len(t, response.Job.Progress, 1)
+		assert.Equal(t, 12, response.Job.Progress[0].Talks)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/jobs/unknown", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestHandleGetJob_Finished(t *testing.T) {
+	clean := domain.NewReindexReport("reindex-all", "", time.Now())
+	partial := domain.NewReindexReport("reindex-all", "", time.Now())
+	partial.Skip(domain.Conference{ID: "conf-2"}, errors.New("moresleep timeout"))
+	partial.AddBulkResult(&domain.BulkResult{Failures: []domain.BulkFailure{{Index: "javazone_private", DocumentID: "talk-7", Status: 400}}})
+
+	tests := []struct {
+		name           string
+		report         *domain.ReindexReport
+		expectedStatus int
+		expectedState  string
+		expectPartial  bool
+	}{
+		{name: "clean", report: clean, expectedStatus: http.StatusOK, expectedState: "success"},
+		{name: "partial", report: partial, expectedStatus: http.StatusMultiStatus, expectedState: "partial", expectPartial: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := newJobMux(&mockJobManager{
+				getFunc: func(id string) (*domain.Job, error) {
+					return &domain.Job{ID: id, Type: domain.JobReindexAll, State: domain.JobSucceeded, Report: tt.report}, nil
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/jobs/abc123", nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response struct {
+				Status string `json:"status"`
+				Job    struct {
+					State  domain.JobState `json:"state"`
+					Report struct {
+						Partial bool `json:"partial"`
+					} `json:"report"`
+				} `json:"job"`
+			}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, tt.expectedState, response.Status)
+			assert.Equal(t, domain.JobSucceeded, response.Job.State)
+			assert.Equal(t, tt.expectPartial, response.Job.Report.Partial)
+		})
+	}
+}
+
+func TestHandleListJobs(t *testing.T) {
+	mux := newJobMux(&mockJobManager{
+		listFunc: func() []domain.Job {
+			return []domain.Job{
+				{ID: "job-2", State: domain.JobRunning},
+				{ID: "job-1", State: domain.JobSucceeded},
+			}
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/jobs", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response JobListResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	require.Len(t, response.Jobs, 2)
+	assert.Equal(t, "job-2", response.Jobs[0].ID)
+}
+
+func TestHandleCancelJob(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "cancelled", expectedStatus: http.StatusOK},
+		{name: "not found", err: domain.ErrJobNotFound, expectedStatus: http.StatusNotFound},
+		{name: "already finished", err: domain.ErrJobFinished, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := newJobMux(&mockJobManager{
+				cancelFunc: func(id string) (*domain.Job, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &domain.Job{ID: id, State: domain.JobCancelled}, nil
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/jobs/abc123/cancel", nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// UnmappedFieldsResponse represents the response of the unmapped fields endpoint
+type UnmappedFieldsResponse struct {
+	Status  string                      `json:"status"`
+	Message string                      `json:"message,omitempty"`
+	Result  *domain.UnmappedFieldReport `json:"result,omitempty"`
+}
+
+// HandleUnmappedFields lists the fields of the source data that have no explicit
+// mapping in the private and public talk indexes
+func (h *Handler) HandleUnmappedFields(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	slog.Info("inspecting unmapped fields")
+
+	result, err := h.indexer.UnmappedFields(ctx)
+	if err != nil {
+		slog.Error("failed to inspect unmapped fields", "error", err)
+		h.writeUnmappedFieldsResponse(w, http.StatusInternalServerError, UnmappedFieldsResponse{
+			Status:  "error",
+			Message: "failed to inspect unmapped fields: " + err.Error(),
+		})
+		return
+	}
+
+	h.writeUnmappedFieldsResponse(w, http.StatusOK, UnmappedFieldsResponse{
+		Status: "success",
+		Result: result,
+	})
+}
+
+// writeUnmappedFieldsResponse writes an unmapped fields JSON response with the given status code
+func (h *Handler) writeUnmappedFieldsResponse(w http.ResponseWriter, status int, response UnmappedFieldsResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("failed to encode response", "error", err)
+	}
+}
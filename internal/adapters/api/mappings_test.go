@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleUnmappedFields_Success(t *testing.T) {
+	indexer := &mockIndexer{
+		unmappedFieldsFunc: func(ctx context.Context) (*domain.UnmappedFieldReport, error) {
+			return &domain.UnmappedFieldReport{
+				Talks:   3,
+				Private: []domain.UnmappedField{{Path: "data.sponsor", Types: []string{"string"}, Talks: 2}},
+				Public:  []domain.UnmappedField{},
+			}, nil
+		},
+	}
+	handler := NewHandler(indexer, &mockJobManager{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/mappings/unmapped", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleUnmappedFields(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response UnmappedFieldsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "success", response.Status)
+	require.NotNil(t, response.Result)
+	assert.Equal(t, 3, response.Result.Talks)
+	assert.Equal(t, []domain.UnmappedField{{Path: "data.sponsor", Types: []string{"string"}, Talks: 2}}, response.Result.Private)
+}
+
+func TestHandleUnmappedFields_Error(t *testing.T) {
+	indexer := &mockIndexer{
+		unmappedFieldsFunc: func(ctx context.Context) (*domain.UnmappedFieldReport, error) {
+			return nil, errors.New("moresleep unavailable")
+		},
+	}
+	handler := NewHandler(indexer, &mockJobManager{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/mappings/unmapped", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleUnmappedFields(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var response UnmappedFieldsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "error", response.Status)
+	assert.Contains(t, response.Message, "moresleep unavailable")
+	assert.Nil(t, response.Result)
+}
@@ -1,122 +1,75 @@
 package api
 
 import (
-	"encoding/json"
-	"log/slog"
+	"fmt"
 	"net/http"
-)
+	"strconv"
 
-// ReindexResponse represents the response for reindex operations
-type ReindexResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message,omitempty"`
-}
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
 
-// HandleReindexAll handles the full reindex endpoint
+// HandleReindexAll queues a full reindex as a background job and returns 202 Accepted
+// with the job, which can be polled at the Location header.
+// With ?dryRun=true the job leaves the index untouched and its report holds the diff instead.
+// A reindex refused by the shrink guard can be repeated with ?force=true.
 func (h *Handler) HandleReindexAll(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	slog.Info("starting full reindex")
-
-	err := h.indexer.ReindexAll(ctx)
-	if err != nil {
-		slog.Error("failed to reindex all conferences", "error", err)
-		h.writeErrorResponse(w, "failed to reindex all conferences", err)
+	opts, ok := h.reindexOptions(w, r)
+	if !ok {
 		return
 	}
 
-	response := ReindexResponse{
-		Status:  "success",
-		Message: "successfully reindexed all conferences",
-	}
-
-	h.writeSuccessResponse(w, response)
-	slog.Info("full reindex completed successfully")
+	h.jobs.submit(w, domain.JobRequest{Type: domain.JobReindexAll, DryRun: opts.DryRun, Force: opts.Force})
 }
 
-// HandleReindexConference handles the reindex endpoint for a specific conference
+// HandleReindexConference queues a reindex of a specific conference as a background job
 func (h *Handler) HandleReindexConference(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
 	// Extract slug from path using Go 1.22+ path parameter feature
 	slug := r.PathValue("slug")
 	if slug == "" {
-		h.writeErrorResponse(w, "conference slug is required", nil)
+		h.jobs.writeError(w, http.StatusBadRequest, "conference slug is required", nil)
 		return
 	}
 
-	slog.Info("starting conference reindex", "slug", slug)
-
-	err := h.indexer.ReindexConference(ctx, slug)
-	if err != nil {
-		slog.Error("failed to reindex conference", "slug", slug, "error", err)
-		h.writeErrorResponse(w, "failed to reindex conference", err)
+	opts, ok := h.reindexOptions(w, r)
+	if !ok {
 		return
 	}
 
-	response := ReindexResponse{
-		Status:  "success",
-		Message: "successfully reindexed conference: " + slug,
-	}
-
-	h.writeSuccessResponse(w, response)
-	slog.Info("conference reindex completed successfully", "slug", slug)
+	h.jobs.submit(w, domain.JobRequest{Type: domain.JobReindexConference, Target: slug, DryRun: opts.DryRun, Force: opts.Force})
 }
 
-// HandleReindexTalk handles the reindex endpoint for a specific talk
+// HandleReindexTalk queues a reindex of a specific talk as a background job.
+// Talk reindexes are not guarded against shrinking, so ?force=true is rejected.
 func (h *Handler) HandleReindexTalk(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
 	// Extract talk ID from path using Go 1.22+ path parameter feature
 	talkID := r.PathValue("talkId")
 	if talkID == "" {
-		h.writeErrorResponse(w, "talk ID is required", nil)
+		h.jobs.writeError(w, http.StatusBadRequest, "talk ID is required", nil)
 		return
 	}
 
-	slog.Info("starting talk reindex", "talkID", talkID)
-
-	err := h.indexer.ReindexTalk(ctx, talkID)
-	if err != nil {
-		slog.Error("failed to reindex talk", "talkID", talkID, "error", err)
-		h.writeErrorResponse(w, "failed to reindex talk", err)
+	opts, ok := h.reindexOptions(w, r)
+	if !ok {
 		return
 	}
 
-	response := ReindexResponse{
-		Status:  "success",
-		Message: "successfully reindexed talk: " + talkID,
-	}
-
-	h.writeSuccessResponse(w, response)
-	slog.Info("talk reindex completed successfully", "talkID", talkID)
-}
-
-// writeSuccessResponse writes a successful JSON response
-func (h *Handler) writeSuccessResponse(w http.ResponseWriter, response ReindexResponse) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		slog.Error("failed to encode success response", "error", err)
-	}
+	h.jobs.submit(w, domain.JobRequest{Type: domain.JobReindexTalk, Target: talkID, DryRun: opts.DryRun, Force: opts.Force})
 }
 
-// writeErrorResponse writes an error JSON response
-func (h *Handler) writeErrorResponse(w http.ResponseWriter, message string, err error) {
-	response := ReindexResponse{
-		Status:  "error",
-		Message: message,
-	}
-
-	if err != nil {
-		response.Message = message + ": " + err.Error()
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError)
-
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		slog.Error("failed to encode error response", "error", err)
+// reindexOptions reads the dryRun and force options from the query string.
+// An invalid value is answered with 400 Bad Request and ok is false.
+func (h *Handler) reindexOptions(w http.ResponseWriter, r *http.Request) (opts domain.ReindexOptions, ok bool) {
+	for name, target := range map[string]*bool{"dryRun": &opts.DryRun, "force": &opts.Force} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			h.jobs.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s value: %s", name, value), nil)
+			return opts, false
+		}
+		*target = parsed
 	}
+	return opts, true
 }
@@ -1,224 +1,207 @@
 package api
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/javaBin/talks-indexer/internal/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestHandleReindexAll_Success(t *testing.T) {
-	// Create handler with mock indexer
-	indexer := &mockIndexer{
-		reindexAllFunc: func(ctx context.Context) error {
-			return nil
+// recordingJobManager returns a job manager mock that records submitted requests
+func recordingJobManager(submitted *[]domain.JobRequest) *mockJobManager {
+	return &mockJobManager{
+		submitFunc: func(req domain.JobRequest) (*domain.Job, error) {
+			*submitted = append(*submitted, req)
+			return &domain.Job{ID: "job-42", Type: req.Type, Target: req.Target, DryRun: req.DryRun, Force: req.Force, State: domain.JobQueued}, nil
 		},
 	}
-	handler := NewHandler(indexer)
+}
+
+func TestHandleReindexAll_QueuesJob(t *testing.T) {
+	var submitted []domain.JobRequest
+	handler := NewHandler(&mockIndexer{}, recordingJobManager(&submitted))
 
-	// Create request
 	req := httptest.NewRequest(http.MethodPost, "/api/reindex", nil)
 	w := httptest.NewRecorder()
 
-	// Call handler
 	handler.HandleReindexAll(w, req)
 
-	// Assert response
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusAccepted, w.Code)
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "/api/jobs/job-42", w.Header().Get("Location"))
+	assert.Equal(t, []domain.JobRequest{{Type: domain.JobReindexAll}}, submitted)
+
+	var response JobResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "accepted", response.Status)
+	require.NotNil(t, response.Job)
+	assert.Equal(t, "job-42", response.Job.ID)
+	assert.Equal(t, domain.JobQueued, response.Job.State)
+}
 
-	// Parse response body
-	var response ReindexResponse
-	err := json.NewDecoder(w.Body).Decode(&response)
-	require.NoError(t, err)
+func TestHandleReindexAll_Options(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  domain.JobRequest
+	}{
+		{name: "dry run", query: "?dryRun=true", want: domain.JobRequest{Type: domain.JobReindexAll, DryRun: true}},
+		{name: "force", query: "?force=true", want: domain.JobRequest{Type: domain.JobReindexAll, Force: true}},
+		{name: "explicitly off", query: "?dryRun=false&force=0", want: domain.JobRequest{Type: domain.JobReindexAll}},
+	}
 
-	assert.Equal(t, "success", response.Status)
-	assert.Contains(t, response.Message, "successfully reindexed all conferences")
-}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var submitted []domain.JobRequest
+			handler := NewHandler(&mockIndexer{}, recordingJobManager(&submitted))
 
-func TestHandleReindexAll_Error(t *testing.T) {
-	expectedError := errors.New("indexing failed")
+			req := httptest.NewRequest(http.MethodPost, "/api/reindex"+tt.query, nil)
+			w := httptest.NewRecorder()
 
-	// Create handler with mock indexer that returns an error
-	indexer := &mockIndexer{
-		reindexAllFunc: func(ctx context.Context) error {
-			return expectedError
-		},
+			handler.HandleReindexAll(w, req)
+
+			assert.Equal(t, http.StatusAccepted, w.Code)
+			assert.Equal(t, []domain.JobRequest{tt.want}, submitted)
+		})
 	}
-	handler := NewHandler(indexer)
+}
 
-	// Create request
-	req := httptest.NewRequest(http.MethodPost, "/api/reindex", nil)
+func TestHandleReindexAll_InvalidDryRun(t *testing.T) {
+	var submitted []domain.JobRequest
+	handler := NewHandler(&mockIndexer{}, recordingJobManager(&submitted))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/reindex?dryRun=maybe", nil)
 	w := httptest.NewRecorder()
 
-	// Call handler
 	handler.HandleReindexAll(w, req)
 
-	// Assert response
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
-
-	// Parse response body
-	var response ReindexResponse
-	err := json.NewDecoder(w.Body).Decode(&response)
-	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, submitted)
 
+	var response JobResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
 	assert.Equal(t, "error", response.Status)
-	assert.Contains(t, response.Message, "failed to reindex all conferences")
-	assert.Contains(t, response.Message, expectedError.Error())
+	assert.Contains(t, response.Message, "invalid dryRun value: maybe")
 }
 
-func TestHandleReindexConference_Success(t *testing.T) {
-	var capturedSlug string
+func TestHandleReindexAll_SubmitError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "queue full", err: domain.ErrJobQueueFull, expectedStatus: http.StatusServiceUnavailable},
+		{name: "manager stopped", err: errors.New("job manager is stopped"), expectedStatus: http.StatusInternalServerError},
+	}
 
-	// Create handler with mock indexer
-	indexer := &mockIndexer{
-		reindexConferenceFunc: func(ctx context.Context, slug string) error {
-			capturedSlug = slug
-			return nil
-		},
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(&mockIndexer{}, &mockJobManager{
+				submitFunc: func(req domain.JobRequest) (*domain.Job, error) {
+					return nil, tt.err
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/reindex", nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleReindexAll(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Empty(t, w.Header().Get("Location"))
+
+			var response JobResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, "error", response.Status)
+			assert.Contains(t, response.Message, tt.err.Error())
+		})
 	}
-	handler := NewHandler(indexer)
+}
 
-	// Create request with slug path parameter
-	req := httptest.NewRequest(http.MethodPost, "/api/reindex/javazone-2024", nil)
+func TestHandleReindexConference_QueuesJob(t *testing.T) {
+	var submitted []domain.JobRequest
+	handler := NewHandler(&mockIndexer{}, recordingJobManager(&submitted))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/reindex/conference/javazone-2024?force=true", nil)
 	req.SetPathValue("slug", "javazone-2024")
 	w := httptest.NewRecorder()
 
-	// Call handler
 	handler.HandleReindexConference(w, req)
 
-	// Assert response
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
-
-	// Verify the slug was passed to the indexer
-	assert.Equal(t, "javazone-2024", capturedSlug)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "/api/jobs/job-42", w.Header().Get("Location"))
+	assert.Equal(t, []domain.JobRequest{{Type: domain.JobReindexConference, Target: "javazone-2024", Force: true}}, submitted)
 
-	// Parse response body
-	var response ReindexResponse
-	err := json.NewDecoder(w.Body).Decode(&response)
-	require.NoError(t, err)
-
-	assert.Equal(t, "success", response.Status)
-	assert.Contains(t, response.Message, "successfully reindexed conference")
-	assert.Contains(t, response.Message, "javazone-2024")
+	var response JobResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	require.NotNil(t, response.Job)
+	assert.Equal(t, "javazone-2024", response.Job.Target)
 }
 
 func TestHandleReindexConference_MissingSlug(t *testing.T) {
-	indexer := &mockIndexer{}
-	handler := NewHandler(indexer)
+	var submitted []domain.JobRequest
+	handler := NewHandler(&mockIndexer{}, recordingJobManager(&submitted))
 
 	// Create request without slug
 	req := httptest.NewRequest(http.MethodPost, "/api/reindex/", nil)
 	w := httptest.NewRecorder()
 
-	// Call handler
 	handler.HandleReindexConference(w, req)
 
-	// Assert response
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-
-	// Parse response body
-	var response ReindexResponse
-	err := json.NewDecoder(w.Body).Decode(&response)
-	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, submitted)
 
+	var response JobResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
 	assert.Equal(t, "error", response.Status)
 	assert.Contains(t, response.Message, "conference slug is required")
 }
 
-func TestHandleReindexConference_Error(t *testing.T) {
-	expectedError := errors.New("conference not found")
-
-	// Create handler with mock indexer that returns an error
-	indexer := &mockIndexer{
-		reindexConferenceFunc: func(ctx context.Context, slug string) error {
-			return expectedError
-		},
-	}
-	handler := NewHandler(indexer)
+func TestHandleReindexTalk_QueuesJob(t *testing.T) {
+	var submitted []domain.JobRequest
+	handler := NewHandler(&mockIndexer{}, recordingJobManager(&submitted))
 
-	// Create request with slug
-	req := httptest.NewRequest(http.MethodPost, "/api/reindex/invalid-conf", nil)
-	req.SetPathValue("slug", "invalid-conf")
+	req := httptest.NewRequest(http.MethodPost, "/api/reindex/talk/talk-1?dryRun=true", nil)
+	req.SetPathValue("talkId", "talk-1")
 	w := httptest.NewRecorder()
 
-	// Call handler
-	handler.HandleReindexConference(w, req)
+	handler.HandleReindexTalk(w, req)
 
-	// Assert response
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
-
-	// Parse response body
-	var response ReindexResponse
-	err := json.NewDecoder(w.Body).Decode(&response)
-	require.NoError(t, err)
-
-	assert.Equal(t, "error", response.Status)
-	assert.Contains(t, response.Message, "failed to reindex conference")
-	assert.Contains(t, response.Message, expectedError.Error())
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "/api/jobs/job-42", w.Header().Get("Location"))
+	assert.Equal(t, []domain.JobRequest{{Type: domain.JobReindexTalk, Target: "talk-1", DryRun: true}}, submitted)
 }
 
-func TestWriteSuccessResponse(t *testing.T) {
-	handler := NewHandler(&mockIndexer{})
-	w := httptest.NewRecorder()
-
-	response := ReindexResponse{
-		Status:  "success",
-		Message: "test message",
-	}
-
-	handler.writeSuccessResponse(w, response)
-
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
-
-	var decoded ReindexResponse
-	err := json.NewDecoder(w.Body).Decode(&decoded)
-	require.NoError(t, err)
-
-	assert.Equal(t, "success", decoded.Status)
-	assert.Equal(t, "test message", decoded.Message)
-}
+func TestHandleReindexTalk_InvalidRequest(t *testing.T) {
+	handler := NewHandler(&mockIndexer{}, &mockJobManager{
+		submitFunc: func(req domain.JobRequest) (*domain.Job, error) {
+			return nil, domain.ErrInvalidJobRequest
+		},
+	})
 
-func TestWriteErrorResponse(t *testing.T) {
-	handler := NewHandler(&mockIndexer{})
+	req := httptest.NewRequest(http.MethodPost, "/api/reindex/talk/talk-1?force=true", nil)
+	req.SetPathValue("talkId", "talk-1")
 	w := httptest.NewRecorder()
 
-	testError := errors.New("test error")
-	handler.writeErrorResponse(w, "operation failed", testError)
-
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
-
-	var response ReindexResponse
-	err := json.NewDecoder(w.Body).Decode(&response)
-	require.NoError(t, err)
+	handler.HandleReindexTalk(w, req)
 
-	assert.Equal(t, "error", response.Status)
-	assert.Contains(t, response.Message, "operation failed")
-	assert.Contains(t, response.Message, "test error")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestWriteErrorResponse_NoError(t *testing.T) {
-	handler := NewHandler(&mockIndexer{})
-	w := httptest.NewRecorder()
-
-	handler.writeErrorResponse(w, "operation failed", nil)
+func TestHandleReindexTalk_MissingTalkID(t *testing.T) {
+	var submitted []domain.JobRequest
+	handler := NewHandler(&mockIndexer{}, recordingJobManager(&submitted))
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	req := httptest.NewRequest(http.MethodPost, "/api/reindex/talk/", nil)
+	w := httptest.NewRecorder()
 
-	var response ReindexResponse
-	err := json.NewDecoder(w.Body).Decode(&response)
-	require.NoError(t, err)
+	handler.HandleReindexTalk(w, req)
 
-	assert.Equal(t, "error", response.Status)
-	assert.Equal(t, "operation failed", response.Message)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, submitted)
 }
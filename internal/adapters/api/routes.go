@@ -9,12 +9,40 @@ func RegisterHealthRoutes(mux *http.ServeMux, h *Handler) {
 	mux.HandleFunc("GET /health", h.HandleHealth)
 }
 
+// RegisterSearchRoutes registers the public search endpoints (always available).
+// It only reads the public index, which holds approved talks without private data.
+func RegisterSearchRoutes(mux *http.ServeMux, h *SearchHandler) {
+	mux.HandleFunc("GET /api/search", h.HandleSearch)
+	mux.HandleFunc("GET /api/search/facets", h.HandleFacets)
+	mux.HandleFunc("GET /api/suggest", h.HandleSuggest)
+}
+
 // RegisterAPIRoutes registers API routes (development mode only)
 func RegisterAPIRoutes(mux *http.ServeMux, h *Handler) {
 	// Reindex endpoints
 	mux.HandleFunc("POST /api/reindex", h.HandleReindexAll)
 	mux.HandleFunc("POST /api/reindex/conference/{slug}", h.HandleReindexConference)
 	mux.HandleFunc("POST /api/reindex/talk/{talkId}", h.HandleReindexTalk)
+
+	// Incremental sync endpoint
+	mux.HandleFunc("POST /api/sync", h.HandleSync)
+
+	// Source fields without an explicit index mapping
+	mux.HandleFunc("GET /api/mappings/unmapped", h.HandleUnmappedFields)
+}
+
+// RegisterJobRoutes registers the background job endpoints (development mode only)
+func RegisterJobRoutes(mux *http.ServeMux, h *JobHandler) {
+	mux.HandleFunc("POST /api/jobs", h.HandleSubmitJob)
+	mux.HandleFunc("GET /api/jobs", h.HandleListJobs)
+	mux.HandleFunc("GET /api/jobs/{id}", h.HandleGetJob)
+	mux.HandleFunc("POST /api/jobs/{id}/cancel", h.HandleCancelJob)
+}
+
+// RegisterWebhookRoutes registers the moresleep webhook endpoint. It is available in
+// every mode when a webhook secret is configured, since deliveries must be signed.
+func RegisterWebhookRoutes(mux *http.ServeMux, h *WebhookHandler) {
+	mux.HandleFunc("POST /hooks/moresleep", h.HandleMoresleepWebhook)
 }
 
 // RegisterRoutes registers all HTTP routes with the provided mux
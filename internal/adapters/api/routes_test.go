@@ -1,18 +1,18 @@
 package api
 
 import (
-	"context"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/javaBin/talks-indexer/internal/domain"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestRegisterRoutes(t *testing.T) {
 	indexer := &mockIndexer{}
-	handler := NewHandler(indexer)
+	handler := NewHandler(indexer, &mockJobManager{})
 	mux := http.NewServeMux()
 
 	RegisterRoutes(mux, handler)
@@ -34,18 +34,30 @@ func TestRegisterRoutes(t *testing.T) {
 			name:           "POST /api/reindex",
 			method:         http.MethodPost,
 			path:           "/api/reindex",
-			expectedStatus: http.StatusOK,
+			expectedStatus: http.StatusAccepted,
 		},
 		{
 			name:           "POST /api/reindex/conference/{slug}",
 			method:         http.MethodPost,
 			path:           "/api/reindex/conference/test-conf",
-			expectedStatus: http.StatusOK,
+			expectedStatus: http.StatusAccepted,
 		},
 		{
 			name:           "POST /api/reindex/talk/{talkId}",
 			method:         http.MethodPost,
 			path:           "/api/reindex/talk/test-talk-id",
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "POST /api/sync",
+			method:         http.MethodPost,
+			path:           "/api/sync",
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "GET /api/mappings/unmapped",
+			method:         http.MethodGet,
+			path:           "/api/mappings/unmapped",
 			expectedStatus: http.StatusOK,
 		},
 	}
@@ -69,7 +81,7 @@ func TestRegisterRoutes(t *testing.T) {
 
 func TestRegisterRoutes_MethodNotAllowed(t *testing.T) {
 	indexer := &mockIndexer{}
-	handler := NewHandler(indexer)
+	handler := NewHandler(indexer, &mockJobManager{})
 	mux := http.NewServeMux()
 
 	RegisterRoutes(mux, handler)
@@ -106,7 +118,7 @@ func TestRegisterRoutes_MethodNotAllowed(t *testing.T) {
 
 func TestRegisterRoutes_NotFound(t *testing.T) {
 	indexer := &mockIndexer{}
-	handler := NewHandler(indexer)
+	handler := NewHandler(indexer, &mockJobManager{})
 	mux := http.NewServeMux()
 
 	RegisterRoutes(mux, handler)
@@ -120,24 +132,8 @@ func TestRegisterRoutes_NotFound(t *testing.T) {
 }
 
 func TestRegisterRoutes_Integration(t *testing.T) {
-	// Create a mock indexer that tracks calls
-	var reindexAllCalled bool
-	var reindexConferenceCalled bool
-	var reindexConferenceSlug string
-
-	indexer := &mockIndexer{
-		reindexAllFunc: func(ctx context.Context) error {
-			reindexAllCalled = true
-			return nil
-		},
-		reindexConferenceFunc: func(ctx context.Context, slug string) error {
-			reindexConferenceCalled = true
-			reindexConferenceSlug = slug
-			return nil
-		},
-	}
-
-	handler := NewHandler(indexer)
+	var submitted []domain.JobRequest
+	handler := NewHandler(&mockIndexer{}, recordingJobManager(&submitted))
 	mux := http.NewServeMux()
 	RegisterRoutes(mux, handler)
 
@@ -146,15 +142,16 @@ func TestRegisterRoutes_Integration(t *testing.T) {
 	w := httptest.NewRecorder()
 	mux.ServeHTTP(w, req)
 
-	assert.True(t, reindexAllCalled)
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusAccepted, w.Code)
 
 	// Test reindex conference
 	req = httptest.NewRequest(http.MethodPost, "/api/reindex/conference/javazone-2024", nil)
 	w = httptest.NewRecorder()
 	mux.ServeHTTP(w, req)
 
-	assert.True(t, reindexConferenceCalled)
-	assert.Equal(t, "javazone-2024", reindexConferenceSlug)
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, []domain.JobRequest{
+		{Type: domain.JobReindexAll},
+		{Type: domain.JobReindexConference, Target: "javazone-2024"},
+	}, submitted)
 }
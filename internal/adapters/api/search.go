@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/javaBin/talks-indexer/internal/ports"
+)
+
+// SearchHandler holds the HTTP handler dependencies for searching talks
+type SearchHandler struct {
+	searcher ports.Searcher
+}
+
+// NewSearchHandler creates a new HTTP handler for searching the public talks index
+func NewSearchHandler(searcher ports.Searcher) *SearchHandler {
+	return &SearchHandler{
+		searcher: searcher,
+	}
+}
+
+// SearchResponse represents the response for a search
+type SearchResponse struct {
+	Status  string               `json:"status"`
+	Message string               `json:"message,omitempty"`
+	Result  *domain.SearchResult `json:"result,omitempty"`
+}
+
+// FacetsResponse represents the response for the facets of a search
+type FacetsResponse struct {
+	Status  string              `json:"status"`
+	Message string              `json:"message,omitempty"`
+	Result  *domain.FacetResult `json:"result,omitempty"`
+}
+
+// SuggestResponse represents the response for type-ahead suggestions
+type SuggestResponse struct {
+	Status  string              `json:"status"`
+	Message string              `json:"message,omitempty"`
+	Result  *domain.Suggestions `json:"result,omitempty"`
+}
+
+// HandleSearch searches the public index.
+// See domain.ParseSearchQuery for the supported query parameters.
+func (h *SearchHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	query, err := domain.ParseSearchQuery(r.URL.Query())
+	if err != nil {
+		h.writeJSON(w, http.StatusBadRequest, SearchResponse{Status: "error", Message: "search failed: " + err.Error()})
+		return
+	}
+
+	result, err := h.searcher.SearchPublic(r.Context(), query)
+	if err != nil {
+		slog.Error("failed to search public index", "error", err)
+		h.writeJSON(w, searchErrorStatus(err), SearchResponse{Status: "error", Message: "search failed: " + err.Error()})
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, SearchResponse{Status: "success", Result: result})
+}
+
+// HandleFacets counts the talks in the public index matching a search per facet value.
+// It takes the same query parameters as HandleSearch, except for page and size.
+func (h *SearchHandler) HandleFacets(w http.ResponseWriter, r *http.Request) {
+	query, err := domain.ParseSearchQuery(r.URL.Query())
+	if err != nil {
+		h.writeJSON(w, http.StatusBadRequest, FacetsResponse{Status: "error", Message: "facets failed: " + err.Error()})
+		return
+	}
+
+	result, err := h.searcher.FacetsPublic(r.Context(), query)
+	if err != nil {
+		slog.Error("failed to aggregate facets of public index", "error", err)
+		h.writeJSON(w, searchErrorStatus(err), FacetsResponse{Status: "error", Message: "facets failed: " + err.Error()})
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, FacetsResponse{Status: "success", Result: result})
+}
+
+// HandleSuggest returns type-ahead suggestions from the public index, grouped into
+// talk titles, speaker names and keywords.
+// It takes q, the repeatable conference filter and size (per group).
+func (h *SearchHandler) HandleSuggest(w http.ResponseWriter, r *http.Request) {
+	query, err := domain.ParseSuggestQuery(r.URL.Query())
+	if err != nil {
+		h.writeJSON(w, http.StatusBadRequest, SuggestResponse{Status: "error", Message: "suggest failed: " + err.Error()})
+		return
+	}
+
+	result, err := h.searcher.SuggestPublic(r.Context(), query)
+	if err != nil {
+		if !errors.Is(err, domain.ErrInvalidSearch) {
+			slog.Error("failed to suggest from public index", "error", err)
+		}
+		h.writeJSON(w, searchErrorStatus(err), SuggestResponse{Status: "error", Message: "suggest failed: " + err.Error()})
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, SuggestResponse{Status: "success", Result: result})
+}
+
+// searchErrorStatus maps search errors to HTTP status codes
+func searchErrorStatus(err error) int {
+	if errors.Is(err, domain.ErrInvalidSearch) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// writeJSON writes a JSON response with the given status code
+func (h *SearchHandler) writeJSON(w http.ResponseWriter, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("failed to encode search response", "error", err)
+	}
+}
@@ -0,0 +1,256 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSearcher is a mock implementation of the Searcher interface for testing
+type mockSearcher struct {
+	searchPublicFunc  func(ctx context.Context, query domain.SearchQuery) (*domain.SearchResult, error)
+	searchPrivateFunc func(ctx context.Context, query domain.SearchQuery) (*domain.SearchResult, error)
+	facetsPublicFunc  func(ctx context.Context, query domain.SearchQuery) (*domain.FacetResult, error)
+	facetsPrivateFunc func(ctx context.Context, query domain.SearchQuery) (*domain.FacetResult, error)
+	suggestPublicFunc func(ctx context.Context, query domain.SuggestQuery) (*domain.Suggestions, error)
+}
+
+func (m *mockSearcher) SearchPublic(ctx context.Context, query domain.SearchQuery) (*domain.SearchResult, error) {
+	if m.searchPublicFunc != nil {
+		return m.searchPublicFunc(ctx, query)
+	}
+	return &domain.SearchResult{Hits: []domain.SearchHit{}}, nil
+}
+
+func (m *mockSearcher) SearchPrivate(ctx context.Context, query domain.SearchQuery) (*domain.SearchResult, error) {
+	if m.searchPrivateFunc != nil {
+		return m.searchPrivateFunc(ctx, query)
+	}
+	return &domain.SearchResult{Hits: []domain.SearchHit{}}, nil
+}
+
+func (m *mockSearcher) FacetsPublic(ctx context.Context, query domain.SearchQuery) (*domain.FacetResult, error) {
+	if m.facetsPublicFunc != nil {
+		return m.facetsPublicFunc(ctx, query)
+	}
+	return &domain.FacetResult{Facets: []domain.Facet{}}, nil
+}
+
+func (m *mockSearcher) FacetsPrivate(ctx context.Context, query domain.SearchQuery) (*domain.FacetResult, error) {
+	if m.facetsPrivateFunc != nil {
+		return m.facetsPrivateFunc(ctx, query)
+	}
+	return &domain.FacetResult{Facets: []domain.Facet{}}, nil
+}
+
+func (m *mockSearcher) SuggestPublic(ctx context.Context, query domain.SuggestQuery) (*domain.Suggestions, error) {
+	if m.suggestPublicFunc != nil {
+		return m.suggestPublicFunc(ctx, query)
+	}
+	return &domain.Suggestions{}, nil
+}
+
+func newSearchMux(searcher *mockSearcher) *http.ServeMux {
+	mux := http.NewServeMux()
+	RegisterSearchRoutes(mux, NewSearchHandler(searcher))
+	return mux
+}
+
+func TestHandleSearch_Success(t *testing.T) {
+	var received domain.SearchQuery
+	mux := newSearchMux(&mockSearcher{
+		searchPublicFunc: func(ctx context.Context, query domain.SearchQuery) (*domain.SearchResult, error) {
+			received = query
+			return &domain.SearchResult{
+				Total: 1,
+				Page:  2,
+				Size:  10,
+				Hits:  []domain.SearchHit{{ID: "talk-1", Score: 2.5, Talk: domain.Talk{ID: "talk-1"}}},
+			}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q=kotlin&conference=javazone2024&language=en,no&level=beginner&keyword=jvm&page=2&size=10", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, domain.SearchQuery{
+		Text: "kotlin",
+		Filters: domain.SearchFilters{
+			ConferenceSlugs: []string{"javazone2024"},
+			Languages:       []string{"en", "no"},
+			Levels:          []string{"beginner"},
+			Keywords:        []string{"jvm"},
+		},
+		Page: 2,
+		Size: 10,
+	}, received)
+
+	var response SearchResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "success", response.Status)
+	require.NotNil(t, response.Result)
+	assert.Equal(t, 1, response.Result.Total)
+	assert.Equal(t, "talk-1", response.Result.Hits[0].ID)
+}
+
+func TestHandleSearch_InvalidQuery(t *testing.T) {
+	called := false
+	mux := newSearchMux(&mockSearcher{
+		searchPublicFunc: func(ctx context.Context, query domain.SearchQuery) (*domain.SearchResult, error) {
+			called = true
+			return nil, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?page=first", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.False(t, called)
+}
+
+func TestHandleSearch_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{"out of range", domain.ErrInvalidSearch, http.StatusBadRequest},
+		{"search index failure", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := newSearchMux(&mockSearcher{
+				searchPublicFunc: func(ctx context.Context, query domain.SearchQuery) (*domain.SearchResult, error) {
+					return nil, tt.err
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/search?q=go", nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response SearchResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, "error", response.Status)
+			assert.Nil(t, response.Result)
+		})
+	}
+}
+
+func TestHandleFacets(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var received domain.SearchQuery
+		mux := newSearchMux(&mockSearcher{
+			facetsPublicFunc: func(ctx context.Context, query domain.SearchQuery) (*domain.FacetResult, error) {
+				received = query
+				return &domain.FacetResult{
+					Total: 5,
+					Facets: []domain.Facet{
+						{Name: domain.FacetLength, Buckets: []domain.FacetBucket{{Value: "45", Count: 4}, {Value: "20", Count: 1}}},
+					},
+				}, nil
+			},
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/search/facets?q=java&length=45&format=workshop,presentation", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "java", received.Text)
+		assert.Equal(t, []string{"45"}, received.Filters.Lengths)
+		assert.Equal(t, []string{"workshop", "presentation"}, received.Filters.Formats)
+
+		var response FacetsResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, "success", response.Status)
+		require.NotNil(t, response.Result)
+		assert.Equal(t, 5, response.Result.Total)
+		assert.Equal(t, domain.FacetLength, response.Result.Facets[0].Name)
+		assert.Equal(t, 4, response.Result.Facets[0].Buckets[0].Count)
+	})
+
+	t.Run("search index failure", func(t *testing.T) {
+		mux := newSearchMux(&mockSearcher{
+			facetsPublicFunc: func(ctx context.Context, query domain.SearchQuery) (*domain.FacetResult, error) {
+				return nil, errors.New("connection refused")
+			},
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/search/facets", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response FacetsResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, "error", response.Status)
+	})
+}
+
+func TestHandleSuggest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var received domain.SuggestQuery
+		mux := newSearchMux(&mockSearcher{
+			suggestPublicFunc: func(ctx context.Context, query domain.SuggestQuery) (*domain.Suggestions, error) {
+				received = query
+				return &domain.Suggestions{
+					Titles:   []domain.Suggestion{{Text: "Modern Kotlin", TalkID: "talk-1"}},
+					Speakers: []domain.Suggestion{{Text: "Kari Kotlinsen"}},
+					Keywords: []domain.Suggestion{{Text: "kotlin"}},
+				}, nil
+			},
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/suggest?q=kot&conference=javazone2024&size=3", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, domain.SuggestQuery{Text: "kot", ConferenceSlugs: []string{"javazone2024"}, Size: 3}, received)
+
+		var response SuggestResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, "success", response.Status)
+		require.NotNil(t, response.Result)
+		assert.Equal(t, "talk-1", response.Result.Titles[0].TalkID)
+		assert.Equal(t, "Kari Kotlinsen", response.Result.Speakers[0].Text)
+		assert.Equal(t, "kotlin", response.Result.Keywords[0].Text)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		mux := newSearchMux(&mockSearcher{
+			suggestPublicFunc: func(ctx context.Context, query domain.SuggestQuery) (*domain.Suggestions, error) {
+				return nil, domain.ErrInvalidSearch
+			},
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/suggest", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
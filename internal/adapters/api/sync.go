@@ -0,0 +1,19 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// HandleSync queues an incremental sync as a background job and returns 202 Accepted
+// with the job, which can be polled at the Location header.
+// A sync refused by the shrink guard can be repeated with ?force=true.
+func (h *Handler) HandleSync(w http.ResponseWriter, r *http.Request) {
+	opts, ok := h.reindexOptions(w, r)
+	if !ok {
+		return
+	}
+
+	h.jobs.submit(w, domain.JobRequest{Type: domain.JobSync, DryRun: opts.DryRun, Force: opts.Force})
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSync_QueuesJob(t *testing.T) {
+	var submitted []domain.JobRequest
+	handler := NewHandler(&mockIndexer{}, recordingJobManager(&submitted))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sync", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleSync(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "/api/jobs/job-42", w.Header().Get("Location"))
+	assert.Equal(t, []domain.JobRequest{{Type: domain.JobSync}}, submitted)
+
+	var response JobResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "accepted", response.Status)
+	require.NotNil(t, response.Job)
+	assert.Equal(t, domain.JobSync, response.Job.Type)
+}
+
+func TestHandleSync_Force(t *testing.T) {
+	var submitted []domain.JobRequest
+	handler := NewHandler(&mockIndexer{}, recordingJobManager(&submitted))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sync?force=true", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleSync(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, []domain.JobRequest{{Type: domain.JobSync, Force: true}}, submitted)
+}
+
+func TestHandleSync_QueueFull(t *testing.T) {
+	handler := NewHandler(&mockIndexer{}, &mockJobManager{
+		submitFunc: func(req domain.JobRequest) (*domain.Job, error) {
+			return nil, domain.ErrJobQueueFull
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sync", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleSync(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var response JobResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "error", response.Status)
+	assert.Contains(t, response.Message, "failed to submit job")
+}
@@ -0,0 +1,291 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/javaBin/talks-indexer/internal/ports"
+)
+
+const (
+	// SignatureHeader holds the hex HMAC-SHA256 of the request body, prefixed with "sha256="
+	SignatureHeader = "X-Moresleep-Signature"
+
+	// DeliveryHeader holds an ID that stays the same when a delivery is retried
+	DeliveryHeader = "X-Moresleep-Delivery"
+
+	// DefaultDedupeWindow is how long delivery IDs are remembered unless SetDedupeWindow is called
+	DefaultDedupeWindow = 10 * time.Minute
+
+	// maxWebhookBody is the largest webhook payload accepted
+	maxWebhookBody = 1 << 20
+)
+
+// errConferenceLookup marks events that could not be handled because the conference list
+// could not be fetched; unlike invalid events they may succeed when delivered again
+var errConferenceLookup = errors.New("failed to look up conference")
+
+// Webhook event types sent by moresleep
+const (
+	EventSessionChanged    = "session-changed"
+	EventSessionDeleted    = "session-deleted"
+	EventConferenceChanged = "conference-changed"
+)
+
+// WebhookEvent is a change notification from moresleep.
+// Session events name the session; conference events name the conference by slug or ID.
+type WebhookEvent struct {
+	ID             string `json:"id,omitempty"`
+	Type           string `json:"type"`
+	SessionID      string `json:"sessionId,omitempty"`
+	ConferenceID   string `json:"conferenceId,omitempty"`
+	ConferenceSlug string `json:"conferenceSlug,omitempty"`
+}
+
+// WebhookHandler receives change notifications from moresleep and queues targeted
+// reindex jobs for them
+type WebhookHandler struct {
+	jobs        ports.JobManager
+	conferences ports.ConferenceCatalog
+	secret      []byte
+	deliveries  *deliveryLog
+}
+
+// NewWebhookHandler creates a webhook handler that accepts payloads signed with secret
+func NewWebhookHandler(jobs ports.JobManager, conferences ports.ConferenceCatalog, secret string) *WebhookHandler {
+	return &WebhookHandler{
+		jobs:        jobs,
+		conferences: conferences,
+		secret:      []byte(secret),
+		deliveries:  newDeliveryLog(DefaultDedupeWindow),
+	}
+}
+
+// SetDedupeWindow sets how long a delivery ID is remembered so retried deliveries are ignored
+func (h *WebhookHandler) SetDedupeWindow(window time.Duration) {
+	h.deliveries = newDeliveryLog(window)
+}
+
+// HandleMoresleepWebhook verifies the signature of a moresleep notification and queues
+// a reindex of the talk or conference it names. Deliveries already seen within the
+// dedupe window and unknown event types are acknowledged without queueing anything.
+func (h *WebhookHandler) HandleMoresleepWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		status := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		h.writeError(w, status, "failed to read webhook", err)
+		return
+	}
+
+	if !h.validSignature(r.Header.Get(SignatureHeader), body) {
+		slog.Warn("rejected webhook with invalid signature", "remoteAddr", r.RemoteAddr)
+		h.writeError(w, http.StatusUnauthorized, "invalid signature", nil)
+		return
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid webhook payload", err)
+		return
+	}
+
+	deliveryID := deliveryKey(r.Header.Get(DeliveryHeader), event, body)
+	if !h.deliveries.reserve(deliveryID, time.Now()) {
+		slog.Info("ignoring duplicate webhook delivery", "delivery", deliveryID, "type", event.Type)
+		h.writeJSON(w, http.StatusOK, JobResponse{Status: "duplicate", Message: "delivery already received"})
+		return
+	}
+
+	req, err := h.jobRequest(r, event)
+	if err != nil {
+		h.deliveries.release(deliveryID)
+		if errors.Is(err, errConferenceLookup) {
+			h.writeError(w, http.StatusServiceUnavailable, "failed to handle webhook event", err)
+			return
+		}
+		h.writeError(w, http.StatusBadRequest, "invalid webhook event", err)
+		return
+	}
+	if req == nil {
+		slog.Info("ignoring webhook event", "delivery", deliveryID, "type", event.Type)
+		h.writeJSON(w, http.StatusOK, JobResponse{Status: "ignored", Message: "unknown event type " + event.Type})
+		return
+	}
+
+	job, err := h.jobs.Submit(*req)
+	if err != nil {
+		// Let the sender retry the delivery, e.g. once the queue has room again
+		h.deliveries.release(deliveryID)
+		slog.Error("failed to queue webhook job", "delivery", deliveryID, "type", req.Type, "target", req.Target, "error", err)
+		h.writeError(w, jobErrorStatus(err), "failed to submit job", err)
+		return
+	}
+
+	slog.Info("webhook job queued",
+		"delivery", deliveryID,
+		"event", event.Type,
+		"jobID", job.ID,
+		"type", job.Type,
+		"target", job.Target,
+	)
+
+	h.writeJSON(w, http.StatusAccepted, JobResponse{
+		Status:  "accepted",
+		Message: "job queued",
+		Job:     job,
+	})
+}
+
+// jobRequest returns the job for an event, or nil for event types that are not handled.
+// A conference change also drops the cached conference list, since its name or slug
+// may be what changed.
+func (h *WebhookHandler) jobRequest(r *http.Request, event WebhookEvent) (*domain.JobRequest, error) {
+	switch event.Type {
+	case EventSessionChanged, EventSessionDeleted:
+		// Reindexing a talk that no longer exists removes it from both indexes
+		if event.SessionID == "" {
+			return nil, fmt.Errorf("%s event without sessionId", event.Type)
+		}
+		return &domain.JobRequest{Type: domain.JobReindexTalk, Target: event.SessionID}, nil
+
+	case EventConferenceChanged:
+		h.conferences.Invalidate()
+		slug, err := h.conferenceSlug(r, event)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.JobRequest{Type: domain.JobReindexConference, Target: slug}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// conferenceSlug returns the slug of the conference an event names, looking it up by ID if needed
+func (h *WebhookHandler) conferenceSlug(r *http.Request, event WebhookEvent) (string, error) {
+	if event.ConferenceSlug != "" {
+		return event.ConferenceSlug, nil
+	}
+	if event.ConferenceID == "" {
+		return "", fmt.Errorf("%s event without conferenceSlug or conferenceId", event.Type)
+	}
+
+	conferences, err := h.conferences.GetConferences(r.Context())
+	if err != nil {
+		return "", fmt.Errorf("%w %s: %w", errConferenceLookup, event.ConferenceID, err)
+	}
+	for _, conference := range conferences {
+		if conference.ID == event.ConferenceID {
+			return conference.Slug, nil
+		}
+	}
+	return "", fmt.Errorf("unknown conference %s", event.ConferenceID)
+}
+
+// validSignature reports whether header holds the HMAC-SHA256 of body with the shared secret
+func (h *WebhookHandler) validSignature(header string, body []byte) bool {
+	hexSignature, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	signature, err := hex.DecodeString(hexSignature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write(body)
+	return hmac.Equal(signature, mac.Sum(nil))
+}
+
+// deliveryKey identifies a delivery by the delivery header, the event ID or, if moresleep
+// sent neither, the payload itself
+func deliveryKey(header string, event WebhookEvent, body []byte) string {
+	if header != "" {
+		return header
+	}
+	if event.ID != "" {
+		return event.ID
+	}
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// writeJSON writes a JSON response with the given status code
+func (h *WebhookHandler) writeJSON(w http.ResponseWriter, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("failed to encode webhook response", "error", err)
+	}
+}
+
+// writeError writes an error JSON response with the given status code
+func (h *WebhookHandler) writeError(w http.ResponseWriter, status int, message string, err error) {
+	response := JobResponse{
+		Status:  "error",
+		Message: message,
+	}
+	if err != nil {
+		response.Message = message + ": " + err.Error()
+	}
+
+	h.writeJSON(w, status, response)
+}
+
+// deliveryLog remembers recently received delivery IDs
+type deliveryLog struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// newDeliveryLog creates a log that forgets deliveries after window
+func newDeliveryLog(window time.Duration) *deliveryLog {
+	return &deliveryLog{
+		window: window,
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// reserve records a delivery and returns false if it was already received within the window
+func (l *deliveryLog) reserve(id string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for seenID, at := range l.seen {
+		if now.Sub(at) >= l.window {
+			delete(l.seen, seenID)
+		}
+	}
+
+	if _, ok := l.seen[id]; ok {
+		return false
+	}
+	l.seen[id] = now
+	return true
+}
+
+// release forgets a delivery that could not be handled, so a retry of it is accepted
+func (l *deliveryLog) release(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.seen, id)
+}
@@ -0,0 +1,329 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "webhook-secret"
+
+// mockConferenceCatalog is a mock implementation of the ConferenceCatalog interface for testing
+type mockConferenceCatalog struct {
+	getConferencesFunc func(ctx context.Context) ([]domain.Conference, error)
+	invalidated        int
+}
+
+func (m *mockConferenceCatalog) GetConferences(ctx context.Context) ([]domain.Conference, error) {
+	if m.getConferencesFunc != nil {
+		return m.getConferencesFunc(ctx)
+	}
+	return []domain.Conference{{ID: "conf-1", Name: "JavaZone 2024", Slug: "javazone2024"}}, nil
+}
+
+func (m *mockConferenceCatalog) Invalidate() {
+	m.invalidated++
+}
+
+func newWebhookMux(jobs *mockJobManager, conferences *mockConferenceCatalog) *http.ServeMux {
+	mux := http.NewServeMux()
+	RegisterWebhookRoutes(mux, NewWebhookHandler(jobs, conferences, testWebhookSecret))
+	return mux
+}
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func webhookRequest(body, delivery string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/hooks/moresleep", strings.NewReader(body))
+	req.Header.Set(SignatureHeader, sign(testWebhookSecret, body))
+	if delivery != "" {
+		req.Header.Set(DeliveryHeader, delivery)
+	}
+	return req
+}
+
+func TestHandleMoresleepWebhook_Events(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected domain.JobRequest
+	}{
+		{
+			name:     "session changed",
+			body:     `{"type":"session-changed","sessionId":"talk-1","conferenceId":"conf-1"}`,
+			expected: domain.JobRequest{Type: domain.JobReindexTalk, Target: "talk-1"},
+		},
+		{
+			name:     "session deleted",
+			body:     `{"type":"session-deleted","sessionId":"talk-2"}`,
+			expected: domain.JobRequest{Type: domain.JobReindexTalk, Target: "talk-2"},
+		},
+		{
+			name:     "conference changed with slug",
+			body:     `{"type":"conference-changed","conferenceSlug":"javazone2025"}`,
+			expected: domain.JobRequest{Type: domain.JobReindexConference, Target: "javazone2025"},
+		},
+		{
+			name:     "conference changed with ID",
+			body:     `{"type":"conference-changed","conferenceId":"conf-1"}`,
+			expected: domain.JobRequest{Type: domain.JobReindexConference, Target: "javazone2024"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var submitted domain.JobRequest
+			mux := newWebhookMux(&mockJobManager{
+				submitFunc: func(req domain.JobRequest) (*domain.Job, error) {
+					submitted = req
+					return &domain.Job{ID: "job-1", Type: req.Type, Target: req.Target, State: domain.JobQueued}, nil
+				},
+			}, &mockConferenceCatalog{})
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, webhookRequest(tt.body, ""))
+
+			assert.Equal(t, http.StatusAccepted, w.Code)
+			assert.Equal(t, tt.expected, submitted)
+
+			var response JobResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, "accepted", response.Status)
+			require.NotNil(t, response.Job)
+			assert.Equal(t, "job-1", response.Job.ID)
+		})
+	}
+}
+
+func TestHandleMoresleepWebhook_ConferenceChangedInvalidatesCatalog(t *testing.T) {
+	conferences := &mockConferenceCatalog{}
+	mux := newWebhookMux(&mockJobManager{}, conferences)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, webhookRequest(`{"type":"conference-changed","conferenceSlug":"javazone2024"}`, ""))
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, 1, conferences.invalidated)
+}
+
+func TestHandleMoresleepWebhook_InvalidSignature(t *testing.T) {
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{name: "missing", signature: ""},
+		{name: "wrong secret", signature: sign("other-secret", `{"type":"session-changed","sessionId":"talk-1"}`)},
+		{name: "not hex", signature: "sha256=zz"},
+		{name: "no prefix", signature: strings.TrimPrefix(sign(testWebhookSecret, `{"type":"session-changed","sessionId":"talk-1"}`), "sha256=")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submitted := false
+			mux := newWebhookMux(&mockJobManager{
+				submitFunc: func(req domain.JobRequest) (*domain.Job, error) {
+					submitted = true
+					return &domain.Job{ID: "job-1"}, nil
+				},
+			}, &mockConferenceCatalog{})
+
+			req := httptest.NewRequest(http.MethodPost, "/hooks/moresleep", strings.NewReader(`{"type":"session-changed","sessionId":"talk-1"}`))
+			if tt.signature != "" {
+				req.Header.Set(SignatureHeader, tt.signature)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.False(t, submitted)
+		})
+	}
+}
+
+func TestHandleMoresleepWebhook_TamperedBody(t *testing.T) {
+	mux := newWebhookMux(&mockJobManager{}, &mockConferenceCatalog{})
+
+	req := httptest.NewRequest(http.MethodPost, "/hooks/moresleep", strings.NewReader(`{"type":"session-changed","sessionId":"talk-2"}`))
+	req.Header.Set(SignatureHeader, sign(testWebhookSecret, `{"type":"session-changed","sessionId":"talk-1"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestHandleMoresleepWebhook_Duplicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		first     string
+		second    string
+		delivery1 string
+		delivery2 string
+	}{
+		{
+			name:      "same delivery header",
+			first:     `{"type":"session-changed","sessionId":"talk-1"}`,
+			second:    `{"type":"session-changed","sessionId":"talk-1"}`,
+			delivery1: "delivery-1",
+			delivery2: "delivery-1",
+		},
+		{
+			name:   "same event ID",
+			first:  `{"id":"event-1","type":"session-changed","sessionId":"talk-1"}`,
+			second: `{"id":"event-1","type":"session-changed","sessionId":"talk-1"}`,
+		},
+		{
+			name:   "same payload",
+			first:  `{"type":"session-changed","sessionId":"talk-1"}`,
+			second: `{"type":"session-changed","sessionId":"talk-1"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submissions := 0
+			mux := newWebhookMux(&mockJobManager{
+				submitFunc: func(req domain.JobRequest) (*domain.Job, error) {
+					submissions++
+					return &domain.Job{ID: "job-1", Type: req.Type, Target: req.Target}, nil
+				},
+			}, &mockConferenceCatalog{})
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, webhookRequest(tt.first, tt.delivery1))
+			assert.Equal(t, http.StatusAccepted, w.Code)
+
+			w = httptest.NewRecorder()
+			mux.ServeHTTP(w, webhookRequest(tt.second, tt.delivery2))
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response JobResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, "duplicate", response.Status)
+			assert.Equal(t, 1, submissions)
+		})
+	}
+}
+
+func TestHandleMoresleepWebhook_DistinctDeliveries(t *testing.T) {
+	submissions := 0
+	mux := newWebhookMux(&mockJobManager{
+		submitFunc: func(req domain.JobRequest) (*domain.Job, error) {
+			submissions++
+			return &domain.Job{ID: "job-1", Type: req.Type, Target: req.Target}, nil
+		},
+	}, &mockConferenceCatalog{})
+
+	body := `{"type":"session-changed","sessionId":"talk-1"}`
+	for _, delivery := range []string{"delivery-1", "delivery-2"} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, webhookRequest(body, delivery))
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	}
+	assert.Equal(t, 2, submissions)
+}
+
+func TestHandleMoresleepWebhook_FailedDeliveryCanBeRetried(t *testing.T) {
+	queueFull := true
+	mux := newWebhookMux(&mockJobManager{
+		submitFunc: func(req domain.JobRequest) (*domain.Job, error) {
+			if queueFull {
+				return nil, domain.ErrJobQueueFull
+			}
+			return &domain.Job{ID: "job-1", Type: req.Type, Target: req.Target}, nil
+		},
+	}, &mockConferenceCatalog{})
+
+	body := `{"type":"session-changed","sessionId":"talk-1"}`
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, webhookRequest(body, "delivery-1"))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	queueFull = false
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, webhookRequest(body, "delivery-1"))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+}
+
+func TestHandleMoresleepWebhook_InvalidEvents(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "invalid JSON", body: `{"type":`},
+		{name: "session event without session", body: `{"type":"session-changed"}`},
+		{name: "conference event without conference", body: `{"type":"conference-changed"}`},
+		{name: "unknown conference", body: `{"type":"conference-changed","conferenceId":"conf-9"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submitted := false
+			mux := newWebhookMux(&mockJobManager{
+				submitFunc: func(req domain.JobRequest) (*domain.Job, error) {
+					submitted = true
+					return &domain.Job{ID: "job-1"}, nil
+				},
+			}, &mockConferenceCatalog{})
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, webhookRequest(tt.body, ""))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.False(t, submitted)
+
+			var response JobResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, "error", response.Status)
+		})
+	}
+}
+
+func TestHandleMoresleepWebhook_ConferenceLookupFails(t *testing.T) {
+	mux := newWebhookMux(&mockJobManager{}, &mockConferenceCatalog{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return nil, errors.New("moresleep unavailable")
+		},
+	})
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, webhookRequest(`{"type":"conference-changed","conferenceId":"conf-1"}`, ""))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "moresleep unavailable")
+}
+
+func TestHandleMoresleepWebhook_UnknownEventType(t *testing.T) {
+	submitted := false
+	mux := newWebhookMux(&mockJobManager{
+		submitFunc: func(req domain.JobRequest) (*domain.Job, error) {
+			submitted = true
+			return &domain.Job{ID: "job-1"}, nil
+		},
+	}, &mockConferenceCatalog{})
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, webhookRequest(`{"type":"speaker-changed","speakerId":"s-1"}`, ""))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, submitted)
+
+	var response JobResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "ignored", response.Status)
+}
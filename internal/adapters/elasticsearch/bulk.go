@@ -0,0 +1,412 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// BulkOptions controls how BulkIndex splits documents into requests and retries rejections
+type BulkOptions struct {
+	// MaxDocs is the largest number of documents sent in one bulk request
+	MaxDocs int
+
+	// MaxBytes is the largest payload of one bulk request. It should stay well below
+	// the cluster's http.max_content_length. A single document larger than this is
+	// still sent on its own.
+	MaxBytes int
+
+	// Concurrency is the number of bulk requests sent in parallel
+	Concurrency int
+
+	// MaxRetries is how many times documents rejected with 429 Too Many Requests are retried
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry; it doubles on every further retry
+	RetryBackoff time.Duration
+}
+
+// DefaultBulkOptions returns the bulk settings used unless SetBulkOptions is called
+func DefaultBulkOptions() BulkOptions {
+	return BulkOptions{
+		MaxDocs:      500,
+		MaxBytes:     5 << 20,
+		Concurrency:  1,
+		MaxRetries:   3,
+		RetryBackoff: 500 * time.Millisecond,
+	}
+}
+
+// SetBulkOptions sets how BulkIndex splits and retries requests.
+// Counts below one fall back to the defaults; negative retries are treated as zero.
+func (c *Client) SetBulkOptions(opts BulkOptions) {
+	defaults := DefaultBulkOptions()
+	if opts.MaxDocs < 1 {
+		opts.MaxDocs = defaults.MaxDocs
+	}
+	if opts.MaxBytes < 1 {
+		opts.MaxBytes = defaults.MaxBytes
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	c.bulk = opts
+}
+
+// bulkItem is the encoded action and source lines of one document
+type bulkItem struct {
+	id   string
+	body []byte
+}
+
+// versionConflictType is the error type of a document rejected because the indexed copy
+// has a higher external version
+const versionConflictType = "version_conflict_engine_exception"
+
+// bulkResponseItem is the outcome of one action in a bulk response
+type bulkResponseItem struct {
+	ID     string `json:"_id"`
+	Status int    `json:"status"`
+	Error  struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+// BulkIndex indexes multiple talks into the specified index using the Bulk API.
+// Each talk is indexed with its ID as the document ID. Talks are split into
+// requests by document count and payload size, optionally sent in parallel, and
+// documents rejected with 429 are retried with exponential backoff.
+// Requests do not refresh the index; callers refresh once when their run is done.
+// Talks with a lastUpdated timestamp are indexed with it as an external version, so a
+// talk is never replaced by an older copy when runs overlap. Such documents are counted
+// as stale in the result instead of as failures.
+func (c *Client) BulkIndex(ctx context.Context, indexName string, talks []domain.Talk) (*domain.BulkResult, error) {
+	if len(talks) == 0 {
+		c.logger.Info("no talks to index", "index", indexName)
+		return &domain.BulkResult{}, nil
+	}
+
+	items := make([]bulkItem, 0, len(talks))
+	for _, talk := range talks {
+		item, err := encodeBulkItem(indexName, talk.ID, talkVersion(talk), newIndexedTalk(talk))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode talk %s: %w", talk.ID, err)
+		}
+		items = append(items, item)
+	}
+
+	return c.bulkIndex(ctx, indexName, "talks", items)
+}
+
+// BulkIndexSpeakers indexes speaker profiles into the specified index, with the speaker
+// ID as the document ID. Requests are split, sent and retried like BulkIndex.
+func (c *Client) BulkIndexSpeakers(ctx context.Context, indexName string, speakers []domain.SpeakerProfile) (*domain.BulkResult, error) {
+	if len(speakers) == 0 {
+		c.logger.Info("no speakers to index", "index", indexName)
+		return &domain.BulkResult{}, nil
+	}
+
+	items := make([]bulkItem, 0, len(speakers))
+	for _, speaker := range speakers {
+		item, err := encodeBulkItem(indexName, speaker.ID, 0, speaker)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode speaker %s: %w", speaker.ID, err)
+		}
+		items = append(items, item)
+	}
+
+	return c.bulkIndex(ctx, indexName, "speakers", items)
+}
+
+// bulkIndex sends encoded documents in batches and merges the results of all batches.
+// kind names the documents in log messages.
+func (c *Client) bulkIndex(ctx context.Context, indexName, kind string, items []bulkItem) (*domain.BulkResult, error) {
+	batches := c.splitBatches(items)
+	results := make([]*domain.BulkResult, len(batches))
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(c.bulk.Concurrency, len(batches)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result, err := c.sendBatch(ctx, indexName, batches[i])
+				if err != nil {
+					cancel(err)
+					continue
+				}
+				results[i] = result
+			}
+		}()
+	}
+
+dispatch:
+	for i := range batches {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
+	// Merge in batch order so failures are listed in the order the documents were given
+	result := &domain.BulkResult{}
+	for _, batchResult := range results {
+		result.Indexed += batchResult.Indexed
+		result.Stale += batchResult.Stale
+		result.Failures = append(result.Failures, batchResult.Failures...)
+	}
+
+	if len(result.Failures) > 0 {
+		c.logger.Warn("bulk index rejected documents",
+			"index", indexName,
+			"indexed", result.Indexed,
+			"stale", result.Stale,
+			"failed", len(result.Failures),
+			"requests", len(batches),
+		)
+		return result, nil
+	}
+
+	c.logger.Info("bulk indexed "+kind, "index", indexName, "count", result.Indexed, "stale", result.Stale, "requests", len(batches))
+	return result, nil
+}
+
+// talkVersion returns the external version of a talk: its lastUpdated time in
+// milliseconds, or zero if it has none
+func talkVersion(talk domain.Talk) int64 {
+	if talk.LastUpdated == nil {
+		return 0
+	}
+	return talk.LastUpdated.UnixMilli()
+}
+
+// encodeBulkItem encodes the index action and source of a document as two NDJSON lines.
+// A positive version is sent as an external_gte version, so the document only replaces
+// an indexed copy with the same or a lower version.
+func encodeBulkItem(indexName, id string, version int64, doc interface{}) (bulkItem, error) {
+	action := map[string]interface{}{
+		"_index": indexName,
+		"_id":    id,
+	}
+	if version > 0 {
+		action["version"] = version
+		action["version_type"] = "external_gte"
+	}
+	metaJSON, err := json.Marshal(map[string]interface{}{"index": action})
+	if err != nil {
+		return bulkItem{}, fmt.Errorf("failed to marshal bulk metadata: %w", err)
+	}
+
+	docJSON, err := json.Marshal(doc)
+	if err != nil {
+		return bulkItem{}, fmt.Errorf("failed to marshal document: %w", err)
+	}
+
+	body := make([]byte, 0, len(metaJSON)+len(docJSON)+2)
+	body = append(body, metaJSON...)
+	body = append(body, '\n')
+	body = append(body, docJSON...)
+	body = append(body, '\n')
+
+	return bulkItem{id: id, body: body}, nil
+}
+
+// splitBatches groups items into batches within the document count and byte limits
+func (c *Client) splitBatches(items []bulkItem) [][]bulkItem {
+	var batches [][]bulkItem
+	var batch []bulkItem
+	size := 0
+
+	for _, item := range items {
+		if len(batch) > 0 && (len(batch) >= c.bulk.MaxDocs || size+len(item.body) > c.bulk.MaxBytes) {
+			batches = append(batches, batch)
+			batch = nil
+			size = 0
+		}
+		batch = append(batch, item)
+		size += len(item.body)
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+// sendBatch sends one batch, retrying the documents rejected with 429 until they are
+// accepted or the retries run out. Documents that are still rejected after the last
+// retry are reported as failures along with documents rejected for other reasons.
+// Documents rejected because a newer version is indexed are counted as stale.
+func (c *Client) sendBatch(ctx context.Context, indexName string, batch []bulkItem) (*domain.BulkResult, error) {
+	result := &domain.BulkResult{}
+	pending := batch
+
+	for attempt := 0; ; attempt++ {
+		responses, err := c.sendBulkRequest(ctx, pending)
+		if err != nil {
+			return nil, err
+		}
+
+		var throttled []bulkItem
+		var throttledResponses []bulkResponseItem
+		for i, response := range responses {
+			switch {
+			case response.Status == http.StatusTooManyRequests:
+				throttled = append(throttled, pending[i])
+				throttledResponses = append(throttledResponses, response)
+			case response.Status == http.StatusConflict && response.Error.Type == versionConflictType:
+				result.Stale++
+			case response.Status >= 400:
+				result.Failures = append(result.Failures, bulkFailure(indexName, pending[i].id, response))
+			default:
+				result.Indexed++
+			}
+		}
+
+		if len(throttled) == 0 {
+			return result, nil
+		}
+
+		if attempt >= c.bulk.MaxRetries {
+			for i, item := range throttled {
+				result.Failures = append(result.Failures, bulkFailure(indexName, item.id, throttledResponses[i]))
+			}
+			return result, nil
+		}
+
+		delay := c.bulk.RetryBackoff << attempt
+		c.logger.Warn("bulk request throttled, retrying",
+			"index", indexName,
+			"documents", len(throttled),
+			"attempt", attempt+1,
+			"delay", delay,
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		pending = throttled
+	}
+}
+
+// sendBulkRequest sends the items in one bulk request and returns the outcome of each
+// item in request order. A request rejected as a whole with 429 is reported as every
+// item being rejected with 429, so it is retried like individually throttled items.
+func (c *Client) sendBulkRequest(ctx context.Context, items []bulkItem) ([]bulkResponseItem, error) {
+	var buf bytes.Buffer
+	for _, item := range items {
+		buf.Write(item.body)
+	}
+
+	req := esapi.BulkRequest{
+		Body: bytes.NewReader(buf.Bytes()),
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute bulk request: %w", err)
+	}
+	defer res.Body.Close()
+
+	// A single document too large for the cluster is a rejected document rather than a failed run
+	tooLarge := res.StatusCode == http.StatusRequestEntityTooLarge && len(items) == 1
+	if res.StatusCode == http.StatusTooManyRequests || tooLarge {
+		body, _ := io.ReadAll(res.Body)
+		responses := make([]bulkResponseItem, len(items))
+		for i, item := range items {
+			responses[i].ID = item.id
+			responses[i].Status = res.StatusCode
+			responses[i].Error.Type = http.StatusText(res.StatusCode)
+			responses[i].Error.Reason = string(body)
+		}
+		return responses, nil
+	}
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("bulk index error: %s - %s", res.Status(), string(body))
+	}
+
+	var bulkResponse struct {
+		Items []map[string]bulkResponseItem `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&bulkResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse bulk response: %w", err)
+	}
+
+	if len(bulkResponse.Items) != len(items) {
+		return nil, fmt.Errorf("bulk response has %d items for %d documents", len(bulkResponse.Items), len(items))
+	}
+
+	responses := make([]bulkResponseItem, len(items))
+	for i, item := range bulkResponse.Items {
+		for _, details := range item {
+			responses[i] = details
+		}
+	}
+	return responses, nil
+}
+
+// bulkFailure converts a rejected bulk response item into a domain failure
+func bulkFailure(indexName, id string, response bulkResponseItem) domain.BulkFailure {
+	return domain.BulkFailure{
+		Index:      indexName,
+		DocumentID: id,
+		Status:     response.Status,
+		Type:       response.Error.Type,
+		Reason:     response.Error.Reason,
+	}
+}
+
+// Refresh makes all operations performed on the indexes since the last refresh
+// visible to search. Missing indexes are ignored.
+func (c *Client) Refresh(ctx context.Context, indexNames ...string) error {
+	if len(indexNames) == 0 {
+		return nil
+	}
+
+	ignoreUnavailable := true
+	req := esapi.IndicesRefreshRequest{
+		Index:             indexNames,
+		IgnoreUnavailable: &ignoreUnavailable,
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return fmt.Errorf("failed to refresh %v: %w", indexNames, err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("refresh error: %s - %s", res.Status(), string(body))
+	}
+
+	c.logger.Debug("refreshed indexes", "indexes", indexNames)
+	return nil
+}
@@ -0,0 +1,346 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bulkRequestIDs returns the document IDs of the index actions in a bulk request body
+func bulkRequestIDs(t *testing.T, body []byte) []string {
+	t.Helper()
+
+	var ids []string
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 1<<20), 1<<20)
+	for line := 0; scanner.Scan(); line++ {
+		if line%2 != 0 {
+			continue
+		}
+		var action map[string]map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &action))
+		ids = append(ids, action["index"]["_id"].(string))
+	}
+	return ids
+}
+
+// writeBulkResponse answers a bulk request with the given status per document ID, defaulting to 201
+func writeBulkResponse(w http.ResponseWriter, ids []string, statuses map[string]int) {
+	items := make([]map[string]interface{}, len(ids))
+	for i, id := range ids {
+		status, ok := statuses[id]
+		if !ok {
+			status = http.StatusCreated
+		}
+		details := map[string]interface{}{"_id": id, "status": status}
+		if status >= 400 {
+			details["error"] = map[string]interface{}{"type": "es_rejected_execution_exception", "reason": "rejected"}
+		}
+		items[i] = map[string]interface{}{"index": details}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"errors": len(statuses) > 0, "items": items})
+}
+
+func TestClient_BulkIndex_Batches(t *testing.T) {
+	t.Run("splits by document count", func(t *testing.T) {
+		var mu sync.Mutex
+		var requests [][]string
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			ids := bulkRequestIDs(t, body)
+
+			mu.Lock()
+			requests = append(requests, ids)
+			mu.Unlock()
+
+			assert.Empty(t, r.URL.Query().Get("refresh"), "bulk requests should not refresh")
+			writeBulkResponse(w, ids, nil)
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+		client.SetBulkOptions(BulkOptions{MaxDocs: 2, MaxBytes: 1 << 20})
+
+		result, err := client.BulkIndex(context.Background(), "test-index", createTestTalks(5))
+		require.NoError(t, err)
+
+		assert.Equal(t, &domain.BulkResult{Indexed: 5}, result)
+		assert.Equal(t, [][]string{{"talk-1", "talk-2"}, {"talk-3", "talk-4"}, {"talk-5"}}, requests)
+	})
+
+	t.Run("splits by payload size", func(t *testing.T) {
+		var requests [][]string
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			ids := bulkRequestIDs(t, body)
+			requests = append(requests, ids)
+			writeBulkResponse(w, ids, nil)
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		talks := createTestTalks(3)
+		item, err := encodeBulkItem("test-index", talks[0].ID, talkVersion(talks[0]), newIndexedTalk(talks[0]))
+		require.NoError(t, err)
+
+		// Room for two documents but not three
+		client.SetBulkOptions(BulkOptions{MaxDocs: 100, MaxBytes: len(item.body)*2 + len(item.body)/2})
+
+		result, err := client.BulkIndex(context.Background(), "test-index", talks)
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, result.Indexed)
+		assert.Equal(t, [][]string{{"talk-1", "talk-2"}, {"talk-3"}}, requests)
+	})
+
+	t.Run("parallel batches keep failures in talk order", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			ids := bulkRequestIDs(t, body)
+			writeBulkResponse(w, ids, map[string]int{"talk-2": 400, "talk-5": 400})
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+		client.SetBulkOptions(BulkOptions{MaxDocs: 1, MaxBytes: 1 << 20, Concurrency: 4})
+
+		result, err := client.BulkIndex(context.Background(), "test-index", createTestTalks(6))
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, result.Indexed)
+		require.Len(t, result.Failures, 2)
+		assert.Equal(t, "talk-2", result.Failures[0].DocumentID)
+		assert.Equal(t, "talk-5", result.Failures[1].DocumentID)
+	})
+
+	t.Run("failed request fails the run", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"server error"}`))
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+		client.SetBulkOptions(BulkOptions{MaxDocs: 1, MaxBytes: 1 << 20, Concurrency: 2})
+
+		_, err = client.BulkIndex(context.Background(), "test-index", createTestTalks(4))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bulk index error")
+	})
+}
+
+func TestClient_BulkIndex_Retry(t *testing.T) {
+	fastRetry := BulkOptions{MaxDocs: 100, MaxBytes: 1 << 20, MaxRetries: 3, RetryBackoff: time.Millisecond}
+
+	t.Run("retries only throttled documents", func(t *testing.T) {
+		var requests [][]string
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			ids := bulkRequestIDs(t, body)
+			requests = append(requests, ids)
+
+			// talk-2 is throttled on the first attempt only
+			statuses := map[string]int{}
+			if len(requests) == 1 {
+				statuses["talk-2"] = http.StatusTooManyRequests
+			}
+			writeBulkResponse(w, ids, statuses)
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+		client.SetBulkOptions(fastRetry)
+
+		result, err := client.BulkIndex(context.Background(), "test-index", createTestTalks(3))
+		require.NoError(t, err)
+
+		assert.Equal(t, &domain.BulkResult{Indexed: 3}, result)
+		assert.Equal(t, [][]string{{"talk-1", "talk-2", "talk-3"}, {"talk-2"}}, requests)
+	})
+
+	t.Run("retries a throttled request", func(t *testing.T) {
+		attempts := 0
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			attempts++
+			if attempts < 3 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(`{"error":"circuit_breaking_exception"}`))
+				return
+			}
+			writeBulkResponse(w, bulkRequestIDs(t, body), nil)
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+		client.SetBulkOptions(fastRetry)
+
+		result, err := client.BulkIndex(context.Background(), "test-index", createTestTalks(2))
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, &domain.BulkResult{Indexed: 2}, result)
+	})
+
+	t.Run("documents still throttled after the last retry are failures", func(t *testing.T) {
+		attempts := 0
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			attempts++
+			writeBulkResponse(w, bulkRequestIDs(t, body), map[string]int{"talk-1": http.StatusTooManyRequests})
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+		client.SetBulkOptions(fastRetry)
+
+		result, err := client.BulkIndex(context.Background(), "test-index", createTestTalks(2))
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, attempts)
+		assert.Equal(t, 1, result.Indexed)
+		assert.Equal(t, []domain.BulkFailure{{
+			Index:      "test-index",
+			DocumentID: "talk-1",
+			Status:     http.StatusTooManyRequests,
+			Type:       "es_rejected_execution_exception",
+			Reason:     "rejected",
+		}}, result.Failures)
+	})
+
+	t.Run("cancellation stops retrying", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+		client.SetBulkOptions(BulkOptions{MaxDocs: 100, MaxBytes: 1 << 20, MaxRetries: 3, RetryBackoff: time.Hour})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err = client.BulkIndex(ctx, "test-index", createTestTalks(1))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestClient_Refresh(t *testing.T) {
+	var path string
+	server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"_shards":{"total":2,"successful":2,"failed":0}}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "", "")
+	require.NoError(t, err)
+
+	require.NoError(t, client.Refresh(context.Background(), "javazone_private", "javazone_public"))
+	assert.Equal(t, "/javazone_private,javazone_public/_refresh", path)
+}
+
+func TestClient_BulkIndexSpeakers(t *testing.T) {
+	var ids []string
+	server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ids = bulkRequestIDs(t, body)
+		writeBulkResponse(w, ids, nil)
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "", "")
+	require.NoError(t, err)
+
+	result, err := client.BulkIndexSpeakers(context.Background(), "speakers", []domain.SpeakerProfile{
+		{ID: "sp-1", Name: "Ada"},
+		{ID: "sp-2", Name: "Grace"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &domain.BulkResult{Indexed: 2}, result)
+	assert.Equal(t, []string{"sp-1", "sp-2"}, ids)
+}
+
+func TestClient_BulkIndex_ExternalVersion(t *testing.T) {
+	t.Run("talks are versioned by lastUpdated", func(t *testing.T) {
+		var actions []map[string]interface{}
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			scanner := bufio.NewScanner(bytes.NewReader(body))
+			scanner.Buffer(make([]byte, 1<<20), 1<<20)
+			for line := 0; scanner.Scan(); line++ {
+				if line%2 == 0 {
+					var action map[string]map[string]interface{}
+					require.NoError(t, json.Unmarshal(scanner.Bytes(), &action))
+					actions = append(actions, action["index"])
+				}
+			}
+			writeBulkResponse(w, bulkRequestIDs(t, body), nil)
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		lastUpdated := time.Date(2024, 9, 4, 12, 30, 0, 0, time.UTC)
+		talks := []domain.Talk{
+			{ID: "talk-1", LastUpdated: &lastUpdated},
+			{ID: "talk-2"},
+		}
+
+		_, err = client.BulkIndex(context.Background(), "test-index", talks)
+		require.NoError(t, err)
+
+		require.Len(t, actions, 2)
+		assert.Equal(t, float64(lastUpdated.UnixMilli()), actions[0]["version"])
+		assert.Equal(t, "external_gte", actions[0]["version_type"])
+		assert.NotContains(t, actions[1], "version")
+		assert.NotContains(t, actions[1], "version_type")
+	})
+
+	t.Run("version conflicts are counted as stale", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"errors":true,"items":[
+				{"index":{"_id":"talk-1","status":201}},
+				{"index":{"_id":"talk-2","status":409,"error":{"type":"version_conflict_engine_exception","reason":"current version [2] is higher than the provided version [1]"}}},
+				{"index":{"_id":"talk-3","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}
+			]}`))
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.BulkIndex(context.Background(), "test-index", createTestTalks(3))
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, result.Indexed)
+		assert.Equal(t, 1, result.Stale)
+		require.Len(t, result.Failures, 1)
+		assert.Equal(t, "talk-3", result.Failures[0].DocumentID)
+	})
+}
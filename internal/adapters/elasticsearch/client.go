@@ -8,16 +8,17 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/elastic/go-elasticsearch/v9"
 	"github.com/elastic/go-elasticsearch/v9/esapi"
-	"github.com/javaBin/talks-indexer/internal/domain"
 )
 
 // Client implements the SearchIndex interface for Elasticsearch operations.
 type Client struct {
 	es     *elasticsearch.Client
+	bulk   BulkOptions
 	logger *slog.Logger
 }
 
@@ -57,101 +58,11 @@ func New(elasticsearchURL, username, password string) (*Client, error) {
 
 	return &Client{
 		es:     es,
+		bulk:   DefaultBulkOptions(),
 		logger: logger,
 	}, nil
 }
 
-// BulkIndex indexes multiple talks into the specified index using the Bulk API.
-// Each talk is indexed with its ID as the document ID.
-func (c *Client) BulkIndex(ctx context.Context, indexName string, talks []domain.Talk) error {
-	if len(talks) == 0 {
-		c.logger.Info("no talks to index", "index", indexName)
-		return nil
-	}
-
-	var buf bytes.Buffer
-
-	// Build bulk request body
-	for _, talk := range talks {
-		// Action metadata
-		meta := map[string]interface{}{
-			"index": map[string]interface{}{
-				"_index": indexName,
-				"_id":    talk.ID,
-			},
-		}
-		metaJSON, err := json.Marshal(meta)
-		if err != nil {
-			return fmt.Errorf("failed to marshal bulk metadata for talk %s: %w", talk.ID, err)
-		}
-
-		// Document body
-		docJSON, err := json.Marshal(talk)
-		if err != nil {
-			return fmt.Errorf("failed to marshal talk %s: %w", talk.ID, err)
-		}
-
-		// Write to buffer (each line must be newline-delimited)
-		buf.Write(metaJSON)
-		buf.WriteByte('\n')
-		buf.Write(docJSON)
-		buf.WriteByte('\n')
-	}
-
-	// Execute bulk request
-	req := esapi.BulkRequest{
-		Body:    bytes.NewReader(buf.Bytes()),
-		Refresh: "true", // Make documents immediately available for search
-	}
-
-	res, err := req.Do(ctx, c.es)
-	if err != nil {
-		return fmt.Errorf("failed to execute bulk request: %w", err)
-	}
-	defer res.Body.Close()
-
-	if res.IsError() {
-		body, _ := io.ReadAll(res.Body)
-		return fmt.Errorf("bulk index error: %s - %s", res.Status(), string(body))
-	}
-
-	// Parse response to check for errors
-	var bulkResponse struct {
-		Errors bool `json:"errors"`
-		Items  []map[string]struct {
-			ID     string `json:"_id"`
-			Status int    `json:"status"`
-			Error  struct {
-				Type   string `json:"type"`
-				Reason string `json:"reason"`
-			} `json:"error"`
-		} `json:"items"`
-	}
-
-	if err := json.NewDecoder(res.Body).Decode(&bulkResponse); err != nil {
-		return fmt.Errorf("failed to parse bulk response: %w", err)
-	}
-
-	if bulkResponse.Errors {
-		// Collect error details
-		var errorDetails []string
-		for _, item := range bulkResponse.Items {
-			for action, details := range item {
-				if details.Status >= 400 {
-					errorDetails = append(errorDetails, fmt.Sprintf(
-						"%s failed for doc %s (status %d): %s - %s",
-						action, details.ID, details.Status, details.Error.Type, details.Error.Reason,
-					))
-				}
-			}
-		}
-		return fmt.Errorf("bulk index had errors: %s", strings.Join(errorDetails, "; "))
-	}
-
-	c.logger.Info("bulk indexed talks", "index", indexName, "count", len(talks))
-	return nil
-}
-
 // DeleteIndex removes an index from Elasticsearch.
 func (c *Client) DeleteIndex(ctx context.Context, indexName string) error {
 	req := esapi.IndicesDeleteRequest{
@@ -180,10 +91,11 @@ func (c *Client) DeleteIndex(ctx context.Context, indexName string) error {
 }
 
 // CreateIndex creates a new index with the specified mapping.
+// A hash of the mapping is stored in the index's _meta so CheckMapping can detect drift.
 func (c *Client) CreateIndex(ctx context.Context, indexName string, mapping string) error {
 	req := esapi.IndicesCreateRequest{
 		Index: indexName,
-		Body:  strings.NewReader(mapping),
+		Body:  strings.NewReader(withMappingHash(mapping)),
 	}
 
 	res, err := req.Do(ctx, c.es)
@@ -225,3 +137,142 @@ func (c *Client) IndexExists(ctx context.Context, indexName string) (bool, error
 	body, _ := io.ReadAll(res.Body)
 	return false, fmt.Errorf("index exists check error: %s - %s", res.Status(), string(body))
 }
+
+// GetAliasTargets returns the names of the indexes an alias currently points to.
+// An empty slice is returned if the alias does not exist.
+func (c *Client) GetAliasTargets(ctx context.Context, alias string) ([]string, error) {
+	req := esapi.IndicesGetAliasRequest{
+		Name: []string{alias},
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alias %s: %w", alias, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return []string{}, nil
+	}
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("get alias error: %s - %s", res.Status(), string(body))
+	}
+
+	// Response is keyed by index name: {"index-a": {"aliases": {"alias": {}}}}
+	var aliasResponse map[string]json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&aliasResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse alias response: %w", err)
+	}
+
+	targets := make([]string, 0, len(aliasResponse))
+	for indexName := range aliasResponse {
+		targets = append(targets, indexName)
+	}
+	sort.Strings(targets)
+
+	return targets, nil
+}
+
+// SwapAlias atomically points an alias at the given index.
+// The alias is removed from every other index in the same request, so readers
+// never observe a moment where the alias is missing. If a concrete index exists
+// with the alias name (from before aliases were used), it is removed as part of
+// the same atomic operation.
+func (c *Client) SwapAlias(ctx context.Context, alias string, indexName string) error {
+	targets, err := c.GetAliasTargets(ctx, alias)
+	if err != nil {
+		return err
+	}
+
+	var actions []map[string]interface{}
+	for _, target := range targets {
+		if target == indexName {
+			continue
+		}
+		actions = append(actions, map[string]interface{}{
+			"remove": map[string]interface{}{"index": target, "alias": alias},
+		})
+	}
+
+	if len(targets) == 0 {
+		// No alias yet - a legacy concrete index may be occupying the name
+		exists, err := c.IndexExists(ctx, alias)
+		if err != nil {
+			return err
+		}
+		if exists {
+			c.logger.Info("replacing legacy index with alias", "index", alias)
+			actions = append(actions, map[string]interface{}{
+				"remove_index": map[string]interface{}{"index": alias},
+			})
+		}
+	}
+
+	actions = append(actions, map[string]interface{}{
+		"add": map[string]interface{}{"index": indexName, "alias": alias},
+	})
+
+	body, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alias actions: %w", err)
+	}
+
+	req := esapi.IndicesUpdateAliasesRequest{
+		Body: bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return fmt.Errorf("failed to swap alias %s: %w", alias, err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("swap alias error: %s - %s", res.Status(), string(body))
+	}
+
+	c.logger.Info("swapped alias", "alias", alias, "index", indexName, "previous", targets)
+	return nil
+}
+
+// ListIndices returns the names of all indexes matching the given pattern (e.g. "javazone_public_*").
+func (c *Client) ListIndices(ctx context.Context, pattern string) ([]string, error) {
+	req := esapi.CatIndicesRequest{
+		Index:  []string{pattern},
+		Format: "json",
+		H:      []string{"index"},
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indices %s: %w", pattern, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return []string{}, nil
+	}
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("list indices error: %s - %s", res.Status(), string(body))
+	}
+
+	var catResponse []struct {
+		Index string `json:"index"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&catResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse list indices response: %w", err)
+	}
+
+	indices := make([]string, 0, len(catResponse))
+	for _, entry := range catResponse {
+		indices = append(indices, entry.Index)
+	}
+	sort.Strings(indices)
+
+	return indices, nil
+}
@@ -244,8 +244,9 @@ func TestClient_BulkIndex(t *testing.T) {
 		require.NoError(t, err)
 
 		talks := createTestTalks(2)
-		err = client.BulkIndex(context.Background(), "test-index", talks)
+		result, err := client.BulkIndex(context.Background(), "test-index", talks)
 		assert.NoError(t, err)
+		assert.Equal(t, &domain.BulkResult{Indexed: 2}, result)
 
 		// Verify bulk request format
 		assert.Contains(t, receivedBody, `"_index":"test-index"`)
@@ -261,7 +262,7 @@ func TestClient_BulkIndex(t *testing.T) {
 		client, err := New(server.URL, "", "")
 		require.NoError(t, err)
 
-		err = client.BulkIndex(context.Background(), "test-index", []domain.Talk{})
+		_, err = client.BulkIndex(context.Background(), "test-index", []domain.Talk{})
 		assert.NoError(t, err) // Should not error for empty array
 	})
 
@@ -301,11 +302,18 @@ func TestClient_BulkIndex(t *testing.T) {
 		require.NoError(t, err)
 
 		talks := createTestTalks(2)
-		err = client.BulkIndex(context.Background(), "test-index", talks)
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "bulk index had errors")
-		assert.Contains(t, err.Error(), "mapper_parsing_exception")
-		assert.Contains(t, err.Error(), "talk-2")
+		result, err := client.BulkIndex(context.Background(), "test-index", talks)
+		require.NoError(t, err)
+
+		// Rejected documents are reported as failures rather than failing the whole request
+		assert.Equal(t, 1, result.Indexed)
+		assert.Equal(t, []domain.BulkFailure{{
+			Index:      "test-index",
+			DocumentID: "talk-2",
+			Status:     400,
+			Type:       "mapper_parsing_exception",
+			Reason:     "failed to parse field",
+		}}, result.Failures)
 	})
 
 	t.Run("http error response", func(t *testing.T) {
@@ -321,7 +329,7 @@ func TestClient_BulkIndex(t *testing.T) {
 		require.NoError(t, err)
 
 		talks := createTestTalks(1)
-		err = client.BulkIndex(context.Background(), "test-index", talks)
+		_, err = client.BulkIndex(context.Background(), "test-index", talks)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "bulk index error")
 	})
@@ -350,7 +358,7 @@ func TestClient_BulkIndexFormat(t *testing.T) {
 		require.NoError(t, err)
 
 		talks := createTestTalks(1)
-		err = client.BulkIndex(context.Background(), "test-index", talks)
+		_, err = client.BulkIndex(context.Background(), "test-index", talks)
 		require.NoError(t, err)
 
 		// Bulk API format: action_and_meta_data\n + optional_source\n
@@ -376,6 +384,136 @@ func TestClient_BulkIndexFormat(t *testing.T) {
 	})
 }
 
+func TestClient_GetAliasTargets(t *testing.T) {
+	t.Run("alias exists", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" && r.URL.Path == "/_alias/javazone_public" {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"javazone_public_20240904123000000":{"aliases":{"javazone_public":{}}}}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		targets, err := client.GetAliasTargets(context.Background(), "javazone_public")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"javazone_public_20240904123000000"}, targets)
+	})
+
+	t.Run("alias does not exist", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"alias [javazone_public] missing","status":404}`))
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		targets, err := client.GetAliasTargets(context.Background(), "javazone_public")
+		require.NoError(t, err)
+		assert.Empty(t, targets)
+	})
+}
+
+func TestClient_SwapAlias(t *testing.T) {
+	t.Run("moves alias from previous generation", func(t *testing.T) {
+		var actions string
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "GET" && r.URL.Path == "/_alias/javazone_public":
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"javazone_public_1":{"aliases":{"javazone_public":{}}}}`))
+			case r.Method == "POST" && r.URL.Path == "/_aliases":
+				bodyBytes, _ := io.ReadAll(r.Body)
+				actions = string(bodyBytes)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"acknowledged":true}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		err = client.SwapAlias(context.Background(), "javazone_public", "javazone_public_2")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"actions":[
+			{"remove":{"index":"javazone_public_1","alias":"javazone_public"}},
+			{"add":{"index":"javazone_public_2","alias":"javazone_public"}}
+		]}`, actions)
+	})
+
+	t.Run("replaces legacy concrete index", func(t *testing.T) {
+		var actions string
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "GET" && r.URL.Path == "/_alias/javazone_public":
+				w.WriteHeader(http.StatusNotFound)
+			case r.Method == "HEAD" && r.URL.Path == "/javazone_public":
+				w.WriteHeader(http.StatusOK)
+			case r.Method == "POST" && r.URL.Path == "/_aliases":
+				bodyBytes, _ := io.ReadAll(r.Body)
+				actions = string(bodyBytes)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"acknowledged":true}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		err = client.SwapAlias(context.Background(), "javazone_public", "javazone_public_2")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"actions":[
+			{"remove_index":{"index":"javazone_public"}},
+			{"add":{"index":"javazone_public_2","alias":"javazone_public"}}
+		]}`, actions)
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "GET":
+				w.WriteHeader(http.StatusNotFound)
+			case r.Method == "HEAD":
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"index_not_found_exception"}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		err = client.SwapAlias(context.Background(), "javazone_public", "javazone_public_2")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "swap alias error")
+	})
+}
+
+func TestClient_ListIndices(t *testing.T) {
+	server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && r.URL.Path == "/_cat/indices/javazone_public_*" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"index":"javazone_public_2"},{"index":"javazone_public_1"}]`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "", "")
+	require.NoError(t, err)
+
+	indices, err := client.ListIndices(context.Background(), "javazone_public_*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"javazone_public_1", "javazone_public_2"}, indices)
+}
+
 // Helper function to create a mock Elasticsearch server
 func createMockESServer(handler http.HandlerFunc) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
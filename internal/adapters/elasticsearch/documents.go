@@ -0,0 +1,465 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// scanPageSize is the number of hits fetched per page when scanning an index
+const scanPageSize = 1000
+
+// searchHit is a single hit from a search response
+type searchHit struct {
+	ID     string          `json:"_id"`
+	Source json.RawMessage `json:"_source"`
+	Sort   []interface{}   `json:"sort"`
+}
+
+// scan runs the query against the index and calls fn for every matching hit.
+// Results are paged with search_after on the talk id, so the whole index can be
+// walked without the 10,000 hit limit. A missing index yields no hits.
+func (c *Client) scan(ctx context.Context, indexName string, query map[string]interface{}, sourceFields []string, fn func(hit searchHit) error) error {
+	var searchAfter []interface{}
+
+	for {
+		body := map[string]interface{}{
+			"query": query,
+			"size":  scanPageSize,
+			"sort":  []interface{}{map[string]interface{}{"id": "asc"}},
+		}
+		if sourceFields != nil {
+			body["_source"] = sourceFields
+		}
+		if searchAfter != nil {
+			body["search_after"] = searchAfter
+		}
+
+		bodyJSON, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal search request: %w", err)
+		}
+
+		req := esapi.SearchRequest{
+			Index: []string{indexName},
+			Body:  bytes.NewReader(bodyJSON),
+		}
+
+		res, err := req.Do(ctx, c.es)
+		if err != nil {
+			return fmt.Errorf("failed to search index %s: %w", indexName, err)
+		}
+
+		if res.StatusCode == http.StatusNotFound {
+			res.Body.Close()
+			return nil
+		}
+
+		if res.IsError() {
+			errBody, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			return fmt.Errorf("search error: %s - %s", res.Status(), string(errBody))
+		}
+
+		var searchResponse struct {
+			Hits struct {
+				Hits []searchHit `json:"hits"`
+			} `json:"hits"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&searchResponse)
+		res.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to parse search response: %w", err)
+		}
+
+		hits := searchResponse.Hits.Hits
+		for _, hit := range hits {
+			if err := fn(hit); err != nil {
+				return err
+			}
+		}
+
+		if len(hits) < scanPageSize {
+			return nil
+		}
+		searchAfter = hits[len(hits)-1].Sort
+	}
+}
+
+// conferenceQuery returns a query matching all talks in a conference
+func conferenceQuery(conferenceID string) map[string]interface{} {
+	return map[string]interface{}{
+		"term": map[string]interface{}{"conferenceId": conferenceID},
+	}
+}
+
+// GetLastUpdated returns the lastUpdated timestamp of every talk indexed for a conference,
+// keyed by talk ID. Talks indexed without a timestamp map to the zero time.
+func (c *Client) GetLastUpdated(ctx context.Context, indexName string, conferenceID string) (map[string]time.Time, error) {
+	result := make(map[string]time.Time)
+
+	err := c.scan(ctx, indexName, conferenceQuery(conferenceID), []string{"lastUpdated"}, func(hit searchHit) error {
+		var doc struct {
+			LastUpdated *time.Time `json:"lastUpdated"`
+		}
+		if err := json.Unmarshal(hit.Source, &doc); err != nil {
+			return fmt.Errorf("failed to parse document %s: %w", hit.ID, err)
+		}
+
+		if doc.LastUpdated != nil {
+			result[hit.ID] = *doc.LastUpdated
+		} else {
+			result[hit.ID] = time.Time{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ScanDocuments returns the source of every document in the index keyed by ID.
+// If conferenceID is not empty only talks in that conference are returned.
+// The completion inputs added when indexing are left out, so the sources match the
+// talks that were indexed. A missing index yields an empty result.
+func (c *Client) ScanDocuments(ctx context.Context, indexName string, conferenceID string) (map[string]map[string]interface{}, error) {
+	query := map[string]interface{}{"match_all": map[string]interface{}{}}
+	if conferenceID != "" {
+		query = conferenceQuery(conferenceID)
+	}
+
+	result := make(map[string]map[string]interface{})
+
+	err := c.scan(ctx, indexName, query, nil, func(hit searchHit) error {
+		var source map[string]interface{}
+		if err := json.Unmarshal(hit.Source, &source); err != nil {
+			return fmt.Errorf("failed to parse document %s: %w", hit.ID, err)
+		}
+		delete(source, suggestField)
+		result[hit.ID] = source
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindTalksBySpeakers returns every talk in the index with at least one of the given
+// speakers. A missing index yields no talks.
+func (c *Client) FindTalksBySpeakers(ctx context.Context, indexName string, speakerIDs []string) ([]domain.Talk, error) {
+	if len(speakerIDs) == 0 {
+		return nil, nil
+	}
+
+	query := map[string]interface{}{
+		"nested": map[string]interface{}{
+			"path": "speakers",
+			"query": map[string]interface{}{
+				"terms": map[string]interface{}{"speakers.id": speakerIDs},
+			},
+		},
+	}
+
+	var talks []domain.Talk
+	err := c.scan(ctx, indexName, query, nil, func(hit searchHit) error {
+		var talk domain.Talk
+		if err := json.Unmarshal(hit.Source, &talk); err != nil {
+			return fmt.Errorf("failed to parse talk %s: %w", hit.ID, err)
+		}
+		talks = append(talks, talk)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return talks, nil
+}
+
+// maxConferenceBuckets bounds the number of conferences counted in a single aggregation
+const maxConferenceBuckets = 10000
+
+// CountByConference returns the number of documents in the index per conference ID.
+// A missing index yields an empty result.
+func (c *Client) CountByConference(ctx context.Context, indexName string) (map[string]int, error) {
+	body := map[string]interface{}{
+		"size": 0,
+		"aggs": map[string]interface{}{
+			"conferences": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": "conferenceId",
+					"size":  maxConferenceBuckets,
+				},
+			},
+		},
+	}
+
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal count request: %w", err)
+	}
+
+	req := esapi.SearchRequest{
+		Index: []string{indexName},
+		Body:  bytes.NewReader(bodyJSON),
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents in %s: %w", indexName, err)
+	}
+	defer res.Body.Close()
+
+	result := make(map[string]int)
+
+	if res.StatusCode == http.StatusNotFound {
+		return result, nil
+	}
+
+	if res.IsError() {
+		errBody, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("count error: %s - %s", res.Status(), string(errBody))
+	}
+
+	var countResponse struct {
+		Aggregations struct {
+			Conferences struct {
+				Buckets []struct {
+					Key      string `json:"key"`
+					DocCount int    `json:"doc_count"`
+				} `json:"buckets"`
+			} `json:"conferences"`
+		} `json:"aggregations"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&countResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse count response: %w", err)
+	}
+
+	for _, bucket := range countResponse.Aggregations.Conferences.Buckets {
+		result[bucket.Key] = bucket.DocCount
+	}
+
+	return result, nil
+}
+
+// GetDocument fetches a single document by ID and decodes its source into v,
+// leaving out the completion inputs added when indexing talks. It returns false if either the index or the document does not exist.
+func (c *Client) GetDocument(ctx context.Context, indexName string, id string, v interface{}) (bool, error) {
+	req := esapi.GetRequest{
+		Index:          indexName,
+		DocumentID:     id,
+		SourceExcludes: []string{suggestField},
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return false, fmt.Errorf("failed to get document %s: %w", id, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return false, fmt.Errorf("get document error: %s - %s", res.Status(), string(body))
+	}
+
+	var getResponse struct {
+		Source json.RawMessage `json:"_source"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&getResponse); err != nil {
+		return false, fmt.Errorf("failed to parse get response: %w", err)
+	}
+
+	if err := json.Unmarshal(getResponse.Source, v); err != nil {
+		return false, fmt.Errorf("failed to decode document %s: %w", id, err)
+	}
+
+	return true, nil
+}
+
+// PutDocument creates or replaces a single document. The index is created with
+// dynamic mapping if it does not exist yet.
+func (c *Client) PutDocument(ctx context.Context, indexName string, id string, doc interface{}) error {
+	docJSON, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal document %s: %w", id, err)
+	}
+
+	req := esapi.IndexRequest{
+		Index:      indexName,
+		DocumentID: id,
+		Body:       bytes.NewReader(docJSON),
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return fmt.Errorf("failed to put document %s: %w", id, err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("put document error: %s - %s", res.Status(), string(body))
+	}
+
+	return nil
+}
+
+// DeleteDocuments deletes the documents with the given IDs using the Bulk API and
+// returns how many were deleted. Documents that do not exist are ignored.
+func (c *Client) DeleteDocuments(ctx context.Context, indexName string, ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var buf bytes.Buffer
+	for _, id := range ids {
+		meta := map[string]interface{}{
+			"delete": map[string]interface{}{
+				"_index": indexName,
+				"_id":    id,
+			},
+		}
+		metaJSON, err := json.Marshal(meta)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal bulk delete for doc %s: %w", id, err)
+		}
+		buf.Write(metaJSON)
+		buf.WriteByte('\n')
+	}
+
+	req := esapi.BulkRequest{
+		Body: bytes.NewReader(buf.Bytes()),
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute bulk delete: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return 0, fmt.Errorf("bulk delete error: %s - %s", res.Status(), string(body))
+	}
+
+	var bulkResponse struct {
+		Items []map[string]struct {
+			ID     string `json:"_id"`
+			Status int    `json:"status"`
+			Error  struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&bulkResponse); err != nil {
+		return 0, fmt.Errorf("failed to parse bulk delete response: %w", err)
+	}
+
+	deleted := 0
+	var errorDetails []string
+	for _, item := range bulkResponse.Items {
+		for _, details := range item {
+			switch {
+			case details.Status == http.StatusNotFound:
+				// The document is already gone, which is the desired state
+			case details.Status >= 400:
+				errorDetails = append(errorDetails, fmt.Sprintf(
+					"delete failed for doc %s (status %d): %s - %s",
+					details.ID, details.Status, details.Error.Type, details.Error.Reason,
+				))
+			default:
+				deleted++
+			}
+		}
+	}
+	if len(errorDetails) > 0 {
+		return 0, fmt.Errorf("bulk delete had errors: %s", strings.Join(errorDetails, "; "))
+	}
+
+	c.logger.Info("deleted documents", "index", indexName, "requested", len(ids), "deleted", deleted)
+	return deleted, nil
+}
+
+// DeleteConferenceTalksExcept deletes every talk of a conference except those with the
+// given IDs and returns how many were deleted. A missing index is treated as having
+// nothing to delete.
+func (c *Client) DeleteConferenceTalksExcept(ctx context.Context, indexName string, conferenceID string, keepIDs []string) (int, error) {
+	return c.deleteByQuery(ctx, indexName, staleTalksQuery(conferenceID, keepIDs))
+}
+
+// staleTalksQuery matches all talks of a conference except those with the given IDs
+func staleTalksQuery(conferenceID string, keepIDs []string) map[string]interface{} {
+	query := map[string]interface{}{
+		"filter": []interface{}{conferenceQuery(conferenceID)},
+	}
+	if len(keepIDs) > 0 {
+		query["must_not"] = []interface{}{
+			map[string]interface{}{"ids": map[string]interface{}{"values": keepIDs}},
+		}
+	}
+	return map[string]interface{}{"bool": query}
+}
+
+// deleteByQuery deletes all documents matching the query and returns how many were deleted.
+// A missing index is treated as having nothing to delete.
+func (c *Client) deleteByQuery(ctx context.Context, indexName string, query map[string]interface{}) (int, error) {
+	body, err := json.Marshal(map[string]interface{}{"query": query})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal delete query: %w", err)
+	}
+
+	req := esapi.DeleteByQueryRequest{
+		Index:     []string{indexName},
+		Body:      bytes.NewReader(body),
+		Conflicts: "proceed",
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete by query in %s: %w", indexName, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+
+	if res.IsError() {
+		errBody, _ := io.ReadAll(res.Body)
+		return 0, fmt.Errorf("delete by query error: %s - %s", res.Status(), string(errBody))
+	}
+
+	var deleteResponse struct {
+		Deleted  int               `json:"deleted"`
+		Failures []json.RawMessage `json:"failures"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&deleteResponse); err != nil {
+		return 0, fmt.Errorf("failed to parse delete by query response: %w", err)
+	}
+
+	if len(deleteResponse.Failures) > 0 {
+		return deleteResponse.Deleted, fmt.Errorf("delete by query had %d failures", len(deleteResponse.Failures))
+	}
+
+	if deleteResponse.Deleted > 0 {
+		c.logger.Info("deleted documents by query", "index", indexName, "count", deleteResponse.Deleted)
+	}
+	return deleteResponse.Deleted, nil
+}
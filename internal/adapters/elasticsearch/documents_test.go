@@ -0,0 +1,390 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetLastUpdated(t *testing.T) {
+	t.Run("returns timestamps keyed by talk id", func(t *testing.T) {
+		var searchBody map[string]interface{}
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/javazone_private/_search" {
+				bodyBytes, _ := io.ReadAll(r.Body)
+				json.Unmarshal(bodyBytes, &searchBody)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"hits":{"hits":[
+					{"_id":"talk-1","_source":{"lastUpdated":"2024-05-01T10:00:00Z"},"sort":["talk-1"]},
+					{"_id":"talk-2","_source":{},"sort":["talk-2"]}
+				]}}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.GetLastUpdated(context.Background(), "javazone_private", "conf-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]time.Time{
+			"talk-1": time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			"talk-2": {},
+		}, result)
+
+		// Verify the search was scoped to the conference and only fetched the timestamp
+		assert.Equal(t, map[string]interface{}{"term": map[string]interface{}{"conferenceId": "conf-1"}}, searchBody["query"])
+		assert.Equal(t, []interface{}{"lastUpdated"}, searchBody["_source"])
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"index_not_found_exception"}`))
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.GetLastUpdated(context.Background(), "javazone_private", "conf-1")
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func TestClient_ScanDocuments(t *testing.T) {
+	t.Run("returns sources without completion inputs scoped to a conference", func(t *testing.T) {
+		var searchBody map[string]interface{}
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/javazone_private/_search" {
+				bodyBytes, _ := io.ReadAll(r.Body)
+				json.Unmarshal(bodyBytes, &searchBody)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"hits":{"hits":[
+					{"_id":"talk-1","_source":{"id":"talk-1","data":{"title":"Go"},"suggest":{"title":{"input":["Go"]}}},"sort":["talk-1"]}
+				]}}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.ScanDocuments(context.Background(), "javazone_private", "conf-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]map[string]interface{}{
+			"talk-1": {"id": "talk-1", "data": map[string]interface{}{"title": "Go"}},
+		}, result)
+		assert.Equal(t, map[string]interface{}{"term": map[string]interface{}{"conferenceId": "conf-1"}}, searchBody["query"])
+		assert.NotContains(t, searchBody, "_source")
+	})
+
+	t.Run("whole index without conference", func(t *testing.T) {
+		var searchBody map[string]interface{}
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bodyBytes, _ := io.ReadAll(r.Body)
+			json.Unmarshal(bodyBytes, &searchBody)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"hits":{"hits":[]}}`))
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.ScanDocuments(context.Background(), "javazone_private", "")
+		require.NoError(t, err)
+
+		assert.Empty(t, result)
+		assert.Equal(t, map[string]interface{}{"match_all": map[string]interface{}{}}, searchBody["query"])
+	})
+}
+
+func TestClient_FindTalksBySpeakers(t *testing.T) {
+	t.Run("scans talks with any of the speakers", func(t *testing.T) {
+		var searchBody map[string]interface{}
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/javazone_public/_search" {
+				bodyBytes, _ := io.ReadAll(r.Body)
+				json.Unmarshal(bodyBytes, &searchBody)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"hits":{"hits":[
+					{"_id":"talk-1","_source":{"id":"talk-1","conferenceId":"conf-1","speakers":[{"id":"sp-1","name":"Ada"}]},"sort":["talk-1"]}
+				]}}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		talks, err := client.FindTalksBySpeakers(context.Background(), "javazone_public", []string{"sp-1", "sp-2"})
+		require.NoError(t, err)
+
+		require.Len(t, talks, 1)
+		assert.Equal(t, "talk-1", talks[0].ID)
+		assert.Equal(t, "sp-1", talks[0].Speakers[0].ID)
+		assert.Equal(t, map[string]interface{}{
+			"nested": map[string]interface{}{
+				"path": "speakers",
+				"query": map[string]interface{}{
+					"terms": map[string]interface{}{"speakers.id": []interface{}{"sp-1", "sp-2"}},
+				},
+			},
+		}, searchBody["query"])
+	})
+
+	t.Run("no speakers skips the request", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		talks, err := client.FindTalksBySpeakers(context.Background(), "javazone_public", nil)
+		require.NoError(t, err)
+		assert.Empty(t, talks)
+	})
+}
+
+func TestClient_CountByConference(t *testing.T) {
+	t.Run("returns counts keyed by conference id", func(t *testing.T) {
+		var searchBody map[string]interface{}
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/javazone_public/_search" {
+				bodyBytes, _ := io.ReadAll(r.Body)
+				json.Unmarshal(bodyBytes, &searchBody)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"hits":{"hits":[]},"aggregations":{"conferences":{"buckets":[
+					{"key":"conf-1","doc_count":120},
+					{"key":"conf-2","doc_count":95}
+				]}}}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.CountByConference(context.Background(), "javazone_public")
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]int{"conf-1": 120, "conf-2": 95}, result)
+		assert.Equal(t, float64(0), searchBody["size"])
+		assert.Contains(t, searchBody, "aggs")
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"index_not_found_exception"}`))
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.CountByConference(context.Background(), "javazone_public")
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func TestClient_GetDocument(t *testing.T) {
+	t.Run("document exists", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" && r.URL.Path == "/state/_doc/sync-conf-1" {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"_id":"sync-conf-1","found":true,"_source":{"talkCount":3}}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		var doc struct {
+			TalkCount int `json:"talkCount"`
+		}
+		found, err := client.GetDocument(context.Background(), "state", "sync-conf-1", &doc)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, 3, doc.TalkCount)
+	})
+
+	t.Run("document does not exist", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"found":false}`))
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		var doc map[string]interface{}
+		found, err := client.GetDocument(context.Background(), "state", "sync-conf-1", &doc)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+func TestClient_PutDocument(t *testing.T) {
+	var receivedBody string
+	server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" && r.URL.Path == "/state/_doc/sync-conf-1" {
+			bodyBytes, _ := io.ReadAll(r.Body)
+			receivedBody = string(bodyBytes)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"result":"created"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "", "")
+	require.NoError(t, err)
+
+	err = client.PutDocument(context.Background(), "state", "sync-conf-1", map[string]interface{}{"talkCount": 3})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"talkCount":3}`, receivedBody)
+}
+
+func TestClient_DeleteDocuments(t *testing.T) {
+	t.Run("missing documents are ignored", func(t *testing.T) {
+		var receivedBody string
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" && r.URL.Path == "/_bulk" {
+				bodyBytes, _ := io.ReadAll(r.Body)
+				receivedBody = string(bodyBytes)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"errors":false,"items":[
+					{"delete":{"_id":"talk-1","status":200,"result":"deleted"}},
+					{"delete":{"_id":"talk-2","status":404,"result":"not_found"}}
+				]}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		deleted, err := client.DeleteDocuments(context.Background(), "javazone_public", []string{"talk-1", "talk-2"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, deleted, "only existing documents are counted")
+		assert.Contains(t, receivedBody, `{"delete":{"_id":"talk-1","_index":"javazone_public"}}`)
+		assert.Contains(t, receivedBody, `{"delete":{"_id":"talk-2","_index":"javazone_public"}}`)
+	})
+
+	t.Run("item failure", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" && r.URL.Path == "/_bulk" {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"errors":true,"items":[
+					{"delete":{"_id":"talk-1","status":503,"error":{"type":"unavailable_shards_exception","reason":"primary shard is not active"}}}
+				]}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		_, err = client.DeleteDocuments(context.Background(), "javazone_public", []string{"talk-1"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unavailable_shards_exception")
+	})
+
+	t.Run("no ids", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		deleted, err := client.DeleteDocuments(context.Background(), "javazone_public", nil)
+		assert.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+}
+
+func TestClient_DeleteConferenceTalksExcept(t *testing.T) {
+	t.Run("returns deleted count", func(t *testing.T) {
+		var receivedBody string
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" && r.URL.Path == "/javazone_public/_delete_by_query" {
+				assert.Equal(t, "proceed", r.URL.Query().Get("conflicts"))
+				bodyBytes, _ := io.ReadAll(r.Body)
+				receivedBody = string(bodyBytes)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"deleted":2,"failures":[]}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		deleted, err := client.DeleteConferenceTalksExcept(context.Background(), "javazone_public", "conf-1", []string{"talk-1", "talk-2"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+		assert.JSONEq(t, `{"query":{"bool":{
+			"filter":[{"term":{"conferenceId":"conf-1"}}],
+			"must_not":[{"ids":{"values":["talk-1","talk-2"]}}]
+		}}}`, receivedBody)
+	})
+
+	t.Run("nothing to keep", func(t *testing.T) {
+		var receivedBody string
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" && r.URL.Path == "/javazone_public/_delete_by_query" {
+				bodyBytes, _ := io.ReadAll(r.Body)
+				receivedBody = string(bodyBytes)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"deleted":3,"failures":[]}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		deleted, err := client.DeleteConferenceTalksExcept(context.Background(), "javazone_public", "conf-1", nil)
+		require.NoError(t, err)
+		assert.Equal(t, 3, deleted)
+		assert.JSONEq(t, `{"query":{"bool":{"filter":[{"term":{"conferenceId":"conf-1"}}]}}}`, receivedBody)
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"index_not_found_exception"}`))
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		deleted, err := client.DeleteConferenceTalksExcept(context.Background(), "javazone_public", "conf-1", []string{"talk-1"})
+		require.NoError(t, err)
+		assert.Equal(t, 0, deleted)
+	})
+}
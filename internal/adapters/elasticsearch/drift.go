@@ -0,0 +1,138 @@
+package elasticsearch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// mappingHashKey is the _meta field holding the hash of the mapping an index was created with
+const mappingHashKey = "mappingHash"
+
+// mappingHashLength is the number of hex characters kept from the SHA-256 digest
+const mappingHashLength = 16
+
+// mappingHash returns a hash of an index definition's settings and mappings. The JSON is
+// decoded and re-encoded with sorted keys first, so formatting changes are not drift.
+// A hash stored in _meta is left out, so stamped and unstamped definitions hash the same.
+func mappingHash(mapping string) (string, error) {
+	var definition map[string]interface{}
+	if err := json.Unmarshal([]byte(mapping), &definition); err != nil {
+		return "", fmt.Errorf("failed to parse mapping: %w", err)
+	}
+
+	if mappings, ok := definition["mappings"].(map[string]interface{}); ok {
+		if meta, ok := mappings["_meta"].(map[string]interface{}); ok {
+			delete(meta, mappingHashKey)
+			if len(meta) == 0 {
+				delete(mappings, "_meta")
+			}
+		}
+	}
+
+	canonical, err := json.Marshal(definition)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode mapping: %w", err)
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])[:mappingHashLength], nil
+}
+
+// withMappingHash returns the index definition with its hash stored in mappings._meta.
+// Definitions that cannot be parsed are returned unchanged so Elasticsearch reports the error.
+func withMappingHash(mapping string) string {
+	hash, err := mappingHash(mapping)
+	if err != nil {
+		return mapping
+	}
+
+	var definition map[string]interface{}
+	if err := json.Unmarshal([]byte(mapping), &definition); err != nil {
+		return mapping
+	}
+
+	mappings, ok := definition["mappings"].(map[string]interface{})
+	if !ok {
+		mappings = map[string]interface{}{}
+		definition["mappings"] = mappings
+	}
+	meta, ok := mappings["_meta"].(map[string]interface{})
+	if !ok {
+		meta = map[string]interface{}{}
+		mappings["_meta"] = meta
+	}
+	meta[mappingHashKey] = hash
+
+	stamped, err := json.Marshal(definition)
+	if err != nil {
+		return mapping
+	}
+	return string(stamped)
+}
+
+// CheckMapping compares the mapping hash stored in the _meta of the index, or of every
+// index behind an alias, with the hash of the given mapping. It returns the first index
+// that differs, or nil if all match or the index does not exist.
+func (c *Client) CheckMapping(ctx context.Context, indexName string, mapping string) (*domain.MappingDrift, error) {
+	expected, err := mappingHash(mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	req := esapi.IndicesGetMappingRequest{
+		Index: []string{indexName},
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mapping of %s: %w", indexName, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("get mapping error: %s - %s", res.Status(), string(body))
+	}
+
+	// Response is keyed by index name: {"index-a": {"mappings": {"_meta": {...}}}}
+	var mappingResponse map[string]struct {
+		Mappings struct {
+			Meta map[string]interface{} `json:"_meta"`
+		} `json:"mappings"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&mappingResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping response: %w", err)
+	}
+
+	indices := make([]string, 0, len(mappingResponse))
+	for name := range mappingResponse {
+		indices = append(indices, name)
+	}
+	sort.Strings(indices)
+
+	for _, name := range indices {
+		actual, _ := mappingResponse[name].Mappings.Meta[mappingHashKey].(string)
+		if actual != expected {
+			return &domain.MappingDrift{
+				Index:    name,
+				Expected: expected,
+				Actual:   actual,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,136 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMappingHash(t *testing.T) {
+	hash, err := mappingHash(TalkPublicIndexMapping)
+	require.NoError(t, err)
+	assert.Len(t, hash, mappingHashLength)
+
+	t.Run("ignores formatting", func(t *testing.T) {
+		reformatted, err := mappingHash(`{"mappings":{"properties":{"id":{"type":"keyword"}}}}`)
+		require.NoError(t, err)
+		original, err := mappingHash("{\n  \"mappings\": {\"properties\": {\"id\": {\"type\": \"keyword\"}}}\n}")
+		require.NoError(t, err)
+		assert.Equal(t, original, reformatted)
+	})
+
+	t.Run("changes with the mapping", func(t *testing.T) {
+		private, err := mappingHash(TalkPrivateIndexMapping)
+		require.NoError(t, err)
+		assert.NotEqual(t, hash, private)
+	})
+
+	t.Run("stamped mapping hashes the same", func(t *testing.T) {
+		stamped, err := mappingHash(withMappingHash(TalkPublicIndexMapping))
+		require.NoError(t, err)
+		assert.Equal(t, hash, stamped)
+	})
+}
+
+func TestClient_CreateIndex_StoresMappingHash(t *testing.T) {
+	var body map[string]interface{}
+	server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		json.Unmarshal(bodyBytes, &body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"acknowledged":true}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "", "")
+	require.NoError(t, err)
+
+	require.NoError(t, client.CreateIndex(context.Background(), "test-index", TalkPublicIndexMapping))
+
+	hash, err := mappingHash(TalkPublicIndexMapping)
+	require.NoError(t, err)
+	mappings := body["mappings"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{mappingHashKey: hash}, mappings["_meta"])
+	assert.Contains(t, mappings, "properties")
+	assert.Contains(t, body, "settings")
+}
+
+func TestClient_CheckMapping(t *testing.T) {
+	hash, err := mappingHash(TalkPublicIndexMapping)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		wantErr  bool
+		want     string
+		actual   string
+	}{
+		{
+			name:     "matching hash",
+			status:   http.StatusOK,
+			response: `{"javazone_public_1":{"mappings":{"_meta":{"mappingHash":"` + hash + `"}}}}`,
+		},
+		{
+			name:     "different hash",
+			status:   http.StatusOK,
+			response: `{"javazone_public_1":{"mappings":{"_meta":{"mappingHash":"0123456789abcdef"}}}}`,
+			want:     "javazone_public_1",
+			actual:   "0123456789abcdef",
+		},
+		{
+			name:     "index created without a hash",
+			status:   http.StatusOK,
+			response: `{"javazone_public":{"mappings":{"properties":{}}}}`,
+			want:     "javazone_public",
+		},
+		{
+			name:     "missing index",
+			status:   http.StatusNotFound,
+			response: `{"error":{"type":"index_not_found_exception"}}`,
+		},
+		{
+			name:     "server error",
+			status:   http.StatusInternalServerError,
+			response: `{"error":"boom"}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/javazone_public/_mapping", r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client, err := New(server.URL, "", "")
+			require.NoError(t, err)
+
+			drift, err := client.CheckMapping(context.Background(), "javazone_public", TalkPublicIndexMapping)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.want == "" {
+				assert.Nil(t, drift)
+				return
+			}
+			require.NotNil(t, drift)
+			assert.Equal(t, tt.want, drift.Index)
+			assert.Equal(t, hash, drift.Expected)
+			assert.Equal(t, tt.actual, drift.Actual)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// facetBucketSize is the largest number of values returned per facet
+const facetBucketSize = 100
+
+// matchingAggregation is the aggregation counting the talks that match every filter
+const matchingAggregation = "matching"
+
+// Facets counts the talks matching the query's text and filters per value of each
+// facet field. Each facet applies every filter except its own, so selecting one
+// value of a facet does not hide its other values.
+// A missing index yields empty facets.
+func (c *Client) Facets(ctx context.Context, indexName string, query domain.SearchQuery) (*domain.FacetResult, error) {
+	filters := searchFilters(query.Filters)
+
+	aggs := map[string]interface{}{
+		matchingAggregation: map[string]interface{}{
+			"filter": boolQuery(nil, filterClauses(filters, "")),
+		},
+	}
+	for _, filter := range filters {
+		aggs[filter.facet] = map[string]interface{}{
+			"filter": boolQuery(nil, filterClauses(filters, filter.facet)),
+			"aggs": map[string]interface{}{
+				"values": map[string]interface{}{
+					"terms": map[string]interface{}{
+						"field": filter.field,
+						"size":  facetBucketSize,
+					},
+				},
+			},
+		}
+	}
+
+	body := map[string]interface{}{
+		"query": boolQuery(textClauses(query.Text), nil),
+		"size":  0,
+		"aggs":  aggs,
+	}
+
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal facets request: %w", err)
+	}
+
+	req := esapi.SearchRequest{
+		Index: []string{indexName},
+		Body:  bytes.NewReader(bodyJSON),
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate facets of index %s: %w", indexName, err)
+	}
+	defer res.Body.Close()
+
+	result := &domain.FacetResult{Facets: make([]domain.Facet, 0, len(filters))}
+
+	if res.StatusCode == http.StatusNotFound {
+		for _, filter := range filters {
+			result.Facets = append(result.Facets, domain.Facet{Name: filter.facet, Buckets: []domain.FacetBucket{}})
+		}
+		return result, nil
+	}
+
+	if res.IsError() {
+		errBody, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("facets error: %s - %s", res.Status(), string(errBody))
+	}
+
+	var facetsResponse struct {
+		Aggregations map[string]struct {
+			DocCount int `json:"doc_count"`
+			Values   struct {
+				Buckets []struct {
+					Key      string `json:"key"`
+					DocCount int    `json:"doc_count"`
+				} `json:"buckets"`
+			} `json:"values"`
+		} `json:"aggregations"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&facetsResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse facets response: %w", err)
+	}
+
+	result.Total = facetsResponse.Aggregations[matchingAggregation].DocCount
+	for _, filter := range filters {
+		facet := domain.Facet{Name: filter.facet, Buckets: []domain.FacetBucket{}}
+		for _, bucket := range facetsResponse.Aggregations[filter.facet].Values.Buckets {
+			facet.Buckets = append(facet.Buckets, domain.FacetBucket{Value: bucket.Key, Count: bucket.DocCount})
+		}
+		result.Facets = append(result.Facets, facet)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Facets(t *testing.T) {
+	t.Run("filters applied to other facets", func(t *testing.T) {
+		var searchBody map[string]interface{}
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/javazone_public/_search" {
+				bodyBytes, _ := io.ReadAll(r.Body)
+				json.Unmarshal(bodyBytes, &searchBody)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"hits":{"total":{"value":30},"hits":[]},"aggregations":{
+					"matching":{"doc_count":12},
+					"language":{"doc_count":30,"values":{"buckets":[{"key":"en","doc_count":20},{"key":"no","doc_count":10}]}},
+					"format":{"doc_count":12,"values":{"buckets":[{"key":"presentation","doc_count":9},{"key":"workshop","doc_count":3}]}}
+				}}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		query := domain.SearchQuery{
+			Text:    "kotlin",
+			Filters: domain.SearchFilters{Languages: []string{"no"}},
+		}
+		result, err := client.Facets(context.Background(), "javazone_public", query)
+		require.NoError(t, err)
+
+		assert.Equal(t, 12, result.Total)
+		require.Len(t, result.Facets, 7)
+		assert.Equal(t, domain.FacetConference, result.Facets[0].Name)
+		assert.Empty(t, result.Facets[0].Buckets)
+
+		facets := make(map[string][]domain.FacetBucket)
+		for _, facet := range result.Facets {
+			facets[facet.Name] = facet.Buckets
+		}
+		assert.Equal(t, []domain.FacetBucket{{Value: "en", Count: 20}, {Value: "no", Count: 10}}, facets[domain.FacetLanguage])
+		assert.Equal(t, []domain.FacetBucket{{Value: "presentation", Count: 9}, {Value: "workshop", Count: 3}}, facets[domain.FacetFormat])
+
+		assert.Equal(t, float64(0), searchBody["size"])
+		assert.Contains(t, searchBody["query"].(map[string]interface{})["bool"], "must")
+
+		languageFilter := map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"terms": map[string]interface{}{"data.language": []interface{}{"no"}}},
+				},
+			},
+		}
+		aggs := searchBody["aggs"].(map[string]interface{})
+		assert.Equal(t, languageFilter, aggs["matching"].(map[string]interface{})["filter"])
+
+		// The language facet ignores its own filter, the others apply it
+		language := aggs["language"].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"match_all": map[string]interface{}{}}, language["filter"])
+		assert.Equal(t, "data.language", language["aggs"].(map[string]interface{})["values"].(map[string]interface{})["terms"].(map[string]interface{})["field"])
+
+		keyword := aggs["keyword"].(map[string]interface{})
+		assert.Equal(t, languageFilter, keyword["filter"])
+		assert.Equal(t, "data.keywords.keyword", keyword["aggs"].(map[string]interface{})["values"].(map[string]interface{})["terms"].(map[string]interface{})["field"])
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"index_not_found_exception"}`))
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.Facets(context.Background(), "javazone_public", domain.SearchQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Total)
+		require.Len(t, result.Facets, 7)
+		for _, facet := range result.Facets {
+			assert.Empty(t, facet.Buckets)
+		}
+	})
+}
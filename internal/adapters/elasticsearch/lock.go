@@ -0,0 +1,129 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+)
+
+// lockRetryOnConflict is how often Elasticsearch re-runs the lock script when
+// two replicas update the lock document at the same time
+const lockRetryOnConflict = 3
+
+// acquireLockScript takes the lock if it is free, expired or already held by the owner.
+// Running it as a scripted upsert makes the check-and-set atomic per document.
+const acquireLockScript = `
+if (ctx._source.owner == null || ctx._source.owner == params.owner || ctx._source.expiresAt < params.now) {
+	if (ctx._source.owner != params.owner) {
+		ctx._source.acquiredAt = params.now;
+	}
+	ctx._source.owner = params.owner;
+	ctx._source.expiresAt = params.expiresAt;
+} else {
+	ctx.op = 'none';
+}`
+
+// releaseLockScript deletes the lock only if it is still held by the owner
+const releaseLockScript = `
+if (ctx._source.owner == params.owner) {
+	ctx.op = 'delete';
+} else {
+	ctx.op = 'none';
+}`
+
+// AcquireLock takes or renews a lock document in the given index. The lock expires
+// after ttl so that a crashed holder cannot block other replicas forever.
+func (c *Client) AcquireLock(ctx context.Context, indexName string, lockID string, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	body := map[string]interface{}{
+		"scripted_upsert": true,
+		"script": map[string]interface{}{
+			"lang":   "painless",
+			"source": acquireLockScript,
+			"params": map[string]interface{}{
+				"owner":     owner,
+				"now":       now.UnixMilli(),
+				"expiresAt": now.Add(ttl).UnixMilli(),
+			},
+		},
+		"upsert": map[string]interface{}{},
+	}
+
+	result, err := c.updateLock(ctx, indexName, lockID, body)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock %s: %w", lockID, err)
+	}
+
+	// A conflict that survived the retries means another replica won the race
+	if result == "" || result == "noop" {
+		return false, nil
+	}
+	return true, nil
+}
+
+// ReleaseLock deletes the lock document if it is still held by the owner.
+// A missing lock is treated as already released.
+func (c *Client) ReleaseLock(ctx context.Context, indexName string, lockID string, owner string) error {
+	body := map[string]interface{}{
+		"script": map[string]interface{}{
+			"lang":   "painless",
+			"source": releaseLockScript,
+			"params": map[string]interface{}{
+				"owner": owner,
+			},
+		},
+	}
+
+	if _, err := c.updateLock(ctx, indexName, lockID, body); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", lockID, err)
+	}
+	return nil
+}
+
+// updateLock runs a scripted update against a lock document and returns the update result.
+// An empty result means the document was missing or the update conflicted.
+func (c *Client) updateLock(ctx context.Context, indexName string, lockID string, body map[string]interface{}) (string, error) {
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal lock update: %w", err)
+	}
+
+	retries := lockRetryOnConflict
+	req := esapi.UpdateRequest{
+		Index:           indexName,
+		DocumentID:      lockID,
+		Body:            bytes.NewReader(bodyJSON),
+		RetryOnConflict: &retries,
+		Refresh:         "true",
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusConflict {
+		return "", nil
+	}
+
+	if res.IsError() {
+		errBody, _ := io.ReadAll(res.Body)
+		return "", fmt.Errorf("lock update error: %s - %s", res.Status(), string(errBody))
+	}
+
+	var updateResponse struct {
+		Result string `json:"result"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&updateResponse); err != nil {
+		return "", fmt.Errorf("failed to parse lock update response: %w", err)
+	}
+
+	return updateResponse.Result, nil
+}
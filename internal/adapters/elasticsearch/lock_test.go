@@ -0,0 +1,109 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_AcquireLock(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		response     string
+		wantAcquired bool
+		wantErr      bool
+	}{
+		{name: "lock created", status: http.StatusCreated, response: `{"result":"created"}`, wantAcquired: true},
+		{name: "lock renewed or taken over", status: http.StatusOK, response: `{"result":"updated"}`, wantAcquired: true},
+		{name: "lock held by another owner", status: http.StatusOK, response: `{"result":"noop"}`, wantAcquired: false},
+		{name: "conflict after retries", status: http.StatusConflict, response: `{"error":"version_conflict_engine_exception"}`, wantAcquired: false},
+		{name: "server error", status: http.StatusInternalServerError, response: `{"error":"boom"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]interface{}
+			var query string
+			server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "POST" && r.URL.Path == "/state/_update/lock-private-public" {
+					query = r.URL.RawQuery
+					bodyBytes, _ := io.ReadAll(r.Body)
+					json.Unmarshal(bodyBytes, &body)
+
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(tt.status)
+					w.Write([]byte(tt.response))
+				}
+			}))
+			defer server.Close()
+
+			client, err := New(server.URL, "", "")
+			require.NoError(t, err)
+
+			before := time.Now()
+			acquired, err := client.AcquireLock(context.Background(), "state", "lock-private-public", "host-1", time.Minute)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAcquired, acquired)
+
+			// The check-and-set runs as a scripted upsert so it is atomic in Elasticsearch
+			assert.Equal(t, true, body["scripted_upsert"])
+			assert.Contains(t, query, "retry_on_conflict=3")
+			script := body["script"].(map[string]interface{})
+			params := script["params"].(map[string]interface{})
+			assert.Equal(t, "host-1", params["owner"])
+			expiresAt := time.UnixMilli(int64(params["expiresAt"].(float64)))
+			assert.WithinDuration(t, before.Add(time.Minute), expiresAt, 5*time.Second)
+		})
+	}
+}
+
+func TestClient_ReleaseLock(t *testing.T) {
+	t.Run("releases own lock", func(t *testing.T) {
+		var body map[string]interface{}
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" && r.URL.Path == "/state/_update/lock-private-public" {
+				bodyBytes, _ := io.ReadAll(r.Body)
+				json.Unmarshal(bodyBytes, &body)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"result":"deleted"}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		err = client.ReleaseLock(context.Background(), "state", "lock-private-public", "host-1")
+		require.NoError(t, err)
+
+		script := body["script"].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"owner": "host-1"}, script["params"])
+		assert.Nil(t, body["upsert"])
+	})
+
+	t.Run("missing lock", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"document_missing_exception"}`))
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		err = client.ReleaseLock(context.Background(), "state", "lock-private-public", "host-1")
+		require.NoError(t, err)
+	})
+}
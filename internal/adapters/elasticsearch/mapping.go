@@ -3,19 +3,111 @@ package elasticsearch
 // TalkPrivateIndexMapping defines the Elasticsearch mapping for the private talks index.
 // This mapping includes all fields, including sensitive data like program committee
 // feedback, submitter emails, and internal notes.
+// The title, abstract and outline have "no" and "en" subfields analyzed for Norwegian
+// and English, which searches use for talks in that language.
+// The suggest fields hold the type-ahead inputs added to every document by BulkIndex.
+// Fields without an explicit mapping are mapped by dynamic templates instead of being
+// typed by their first value: objects are stored but not indexed, strings named like
+// a time or date are lenient dates, other strings are text with a keyword subfield,
+// and numbers and booleans are keywords, which accept any later scalar value.
 const TalkPrivateIndexMapping = `{
   "settings": {
     "number_of_shards": 1,
     "number_of_replicas": 1,
     "analysis": {
+      "filter": {
+        "norwegian_stop": {
+          "type": "stop",
+          "stopwords": "_norwegian_"
+        },
+        "norwegian_stemmer": {
+          "type": "stemmer",
+          "language": "light_norwegian"
+        },
+        "norwegian_decompounder": {
+          "type": "dictionary_decompounder",
+          "word_list": ["arkitektur", "data", "drift", "kode", "læring", "maskin", "plattform", "programmering", "sikkerhet", "system", "team", "test", "tjeneste", "utvikler", "utvikling"],
+          "min_subword_size": 4,
+          "only_longest_match": false
+        },
+        "english_stop": {
+          "type": "stop",
+          "stopwords": "_english_"
+        },
+        "english_stemmer": {
+          "type": "stemmer",
+          "language": "english"
+        },
+        "english_possessive_stemmer": {
+          "type": "stemmer",
+          "language": "possessive_english"
+        }
+      },
       "analyzer": {
         "default": {
           "type": "standard"
+        },
+        "norwegian_text": {
+          "type": "custom",
+          "tokenizer": "standard",
+          "filter": ["lowercase", "norwegian_decompounder", "norwegian_stop", "norwegian_stemmer"]
+        },
+        "english_text": {
+          "type": "custom",
+          "tokenizer": "standard",
+          "filter": ["english_possessive_stemmer", "lowercase", "english_stop", "english_stemmer"]
         }
       }
     }
   },
   "mappings": {
+    "date_detection": false,
+    "numeric_detection": false,
+    "dynamic_templates": [
+      {
+        "unknown_objects": {
+          "match_mapping_type": "object",
+          "mapping": {
+            "type": "object",
+            "enabled": false
+          }
+        }
+      },
+      {
+        "unknown_dates": {
+          "match_mapping_type": "string",
+          "match_pattern": "regex",
+          "match": "^.*(Time|Date|At)$",
+          "mapping": {
+            "type": "date",
+            "format": "strict_date_optional_time||epoch_millis",
+            "ignore_malformed": true
+          }
+        }
+      },
+      {
+        "unknown_strings": {
+          "match_mapping_type": "string",
+          "mapping": {
+            "type": "text",
+            "fields": {
+              "keyword": {
+                "type": "keyword",
+                "ignore_above": 256
+              }
+            }
+          }
+        }
+      },
+      {
+        "unknown_scalars": {
+          "match_mapping_type": ["long", "double", "boolean"],
+          "mapping": {
+            "type": "keyword"
+          }
+        }
+      }
+    ],
     "properties": {
       "id": {
         "type": "keyword"
@@ -50,14 +142,42 @@ const TalkPrivateIndexMapping = `{
               "keyword": {
                 "type": "keyword",
                 "ignore_above": 256
+              },
+              "no": {
+                "type": "text",
+                "analyzer": "norwegian_text"
+              },
+              "en": {
+                "type": "text",
+                "analyzer": "english_text"
               }
             }
           },
           "abstract": {
-            "type": "text"
+            "type": "text",
+            "fields": {
+              "no": {
+                "type": "text",
+                "analyzer": "norwegian_text"
+              },
+              "en": {
+                "type": "text",
+                "analyzer": "english_text"
+              }
+            }
           },
           "outline": {
-            "type": "text"
+            "type": "text",
+            "fields": {
+              "no": {
+                "type": "text",
+                "analyzer": "norwegian_text"
+              },
+              "en": {
+                "type": "text",
+                "analyzer": "english_text"
+              }
+            }
           },
           "intendedAudience": {
             "type": "text"
@@ -203,6 +323,43 @@ const TalkPrivateIndexMapping = `{
           }
         }
       },
+      "suggest": {
+        "properties": {
+          "title": {
+            "type": "completion",
+            "analyzer": "simple",
+            "contexts": [
+              {
+                "name": "conference",
+                "type": "category",
+                "path": "conferenceSlug"
+              }
+            ]
+          },
+          "speaker": {
+            "type": "completion",
+            "analyzer": "simple",
+            "contexts": [
+              {
+                "name": "conference",
+                "type": "category",
+                "path": "conferenceSlug"
+              }
+            ]
+          },
+          "keyword": {
+            "type": "completion",
+            "analyzer": "simple",
+            "contexts": [
+              {
+                "name": "conference",
+                "type": "category",
+                "path": "conferenceSlug"
+              }
+            ]
+          }
+        }
+      },
       "speakers": {
         "type": "nested",
         "properties": {
@@ -260,19 +417,106 @@ const TalkPrivateIndexMapping = `{
 // TalkPublicIndexMapping defines the Elasticsearch mapping for the public talks index.
 // This mapping excludes sensitive fields like program committee feedback,
 // submitter emails, internal notes, and other private data.
+// The title and abstract have "no" and "en" subfields; the suggest fields and dynamic
+// templates are the same as in the private mapping.
 const TalkPublicIndexMapping = `{
   "settings": {
     "number_of_shards": 1,
     "number_of_replicas": 1,
     "analysis": {
+      "filter": {
+        "norwegian_stop": {
+          "type": "stop",
+          "stopwords": "_norwegian_"
+        },
+        "norwegian_stemmer": {
+          "type": "stemmer",
+          "language": "light_norwegian"
+        },
+        "norwegian_decompounder": {
+          "type": "dictionary_decompounder",
+          "word_list": ["arkitektur", "data", "drift", "kode", "læring", "maskin", "plattform", "programmering", "sikkerhet", "system", "team", "test", "tjeneste", "utvikler", "utvikling"],
+          "min_subword_size": 4,
+          "only_longest_match": false
+        },
+        "english_stop": {
+          "type": "stop",
+          "stopwords": "_english_"
+        },
+        "english_stemmer": {
+          "type": "stemmer",
+          "language": "english"
+        },
+        "english_possessive_stemmer": {
+          "type": "stemmer",
+          "language": "possessive_english"
+        }
+      },
       "analyzer": {
         "default": {
           "type": "standard"
+        },
+        "norwegian_text": {
+          "type": "custom",
+          "tokenizer": "standard",
+          "filter": ["lowercase", "norwegian_decompounder", "norwegian_stop", "norwegian_stemmer"]
+        },
+        "english_text": {
+          "type": "custom",
+          "tokenizer": "standard",
+          "filter": ["english_possessive_stemmer", "lowercase", "english_stop", "english_stemmer"]
         }
       }
     }
   },
   "mappings": {
+    "date_detection": false,
+    "numeric_detection": false,
+    "dynamic_templates": [
+      {
+        "unknown_objects": {
+          "match_mapping_type": "object",
+          "mapping": {
+            "type": "object",
+            "enabled": false
+          }
+        }
+      },
+      {
+        "unknown_dates": {
+          "match_mapping_type": "string",
+          "match_pattern": "regex",
+          "match": "^.*(Time|Date|At)$",
+          "mapping": {
+            "type": "date",
+            "format": "strict_date_optional_time||epoch_millis",
+            "ignore_malformed": true
+          }
+        }
+      },
+      {
+        "unknown_strings": {
+          "match_mapping_type": "string",
+          "mapping": {
+            "type": "text",
+            "fields": {
+              "keyword": {
+                "type": "keyword",
+                "ignore_above": 256
+              }
+            }
+          }
+        }
+      },
+      {
+        "unknown_scalars": {
+          "match_mapping_type": ["long", "double", "boolean"],
+          "mapping": {
+            "type": "keyword"
+          }
+        }
+      }
+    ],
     "properties": {
       "id": {
         "type": "keyword"
@@ -307,11 +551,29 @@ const TalkPublicIndexMapping = `{
               "keyword": {
                 "type": "keyword",
                 "ignore_above": 256
+              },
+              "no": {
+                "type": "text",
+                "analyzer": "norwegian_text"
+              },
+              "en": {
+                "type": "text",
+                "analyzer": "english_text"
               }
             }
           },
           "abstract": {
-            "type": "text"
+            "type": "text",
+            "fields": {
+              "no": {
+                "type": "text",
+                "analyzer": "norwegian_text"
+              },
+              "en": {
+                "type": "text",
+                "analyzer": "english_text"
+              }
+            }
           },
           "intendedAudience": {
             "type": "text"
@@ -391,6 +653,43 @@ const TalkPublicIndexMapping = `{
           }
         }
       },
+      "suggest": {
+        "properties": {
+          "title": {
+            "type": "completion",
+            "analyzer": "simple",
+            "contexts": [
+              {
+                "name": "conference",
+                "type": "category",
+                "path": "conferenceSlug"
+              }
+            ]
+          },
+          "speaker": {
+            "type": "completion",
+            "analyzer": "simple",
+            "contexts": [
+              {
+                "name": "conference",
+                "type": "category",
+                "path": "conferenceSlug"
+              }
+            ]
+          },
+          "keyword": {
+            "type": "completion",
+            "analyzer": "simple",
+            "contexts": [
+              {
+                "name": "conference",
+                "type": "category",
+                "path": "conferenceSlug"
+              }
+            ]
+          }
+        }
+      },
       "speakers": {
         "type": "nested",
         "properties": {
@@ -432,3 +731,294 @@ const TalkPublicIndexMapping = `{
     }
   }
 }`
+
+// SpeakerPrivateIndexMapping defines the Elasticsearch mapping for the private speakers
+// index, holding one profile per speaker with private data merged into data and every
+// talk the speaker appears in regardless of status. Unknown speaker data fields are
+// mapped by the same dynamic templates as in the talk mappings.
+const SpeakerPrivateIndexMapping = `{
+  "settings": {
+    "number_of_shards": 1,
+    "number_of_replicas": 1
+  },
+  "mappings": {
+    "date_detection": false,
+    "numeric_detection": false,
+    "dynamic_templates": [
+      {
+        "unknown_objects": {
+          "match_mapping_type": "object",
+          "mapping": {
+            "type": "object",
+            "enabled": false
+          }
+        }
+      },
+      {
+        "unknown_dates": {
+          "match_mapping_type": "string",
+          "match_pattern": "regex",
+          "match": "^.*(Time|Date|At)$",
+          "mapping": {
+            "type": "date",
+            "format": "strict_date_optional_time||epoch_millis",
+            "ignore_malformed": true
+          }
+        }
+      },
+      {
+        "unknown_strings": {
+          "match_mapping_type": "string",
+          "mapping": {
+            "type": "text",
+            "fields": {
+              "keyword": {
+                "type": "keyword",
+                "ignore_above": 256
+              }
+            }
+          }
+        }
+      },
+      {
+        "unknown_scalars": {
+          "match_mapping_type": ["long", "double", "boolean"],
+          "mapping": {
+            "type": "keyword"
+          }
+        }
+      }
+    ],
+    "properties": {
+      "id": {
+        "type": "keyword"
+      },
+      "name": {
+        "type": "text",
+        "fields": {
+          "keyword": {
+            "type": "keyword",
+            "ignore_above": 256
+          }
+        }
+      },
+      "data": {
+        "properties": {
+          "bio": {
+            "type": "text"
+          },
+          "twitter": {
+            "type": "keyword"
+          },
+          "linkedin": {
+            "type": "keyword",
+            "index": false
+          },
+          "bluesky": {
+            "type": "keyword"
+          },
+          "residence": {
+            "type": "keyword"
+          },
+          "zip-code": {
+            "type": "keyword"
+          },
+          "pictureId": {
+            "type": "keyword",
+            "index": false
+          },
+          "emailAlias": {
+            "type": "keyword"
+          },
+          "speakerAlias": {
+            "type": "keyword"
+          }
+        }
+      },
+      "talks": {
+        "type": "nested",
+        "properties": {
+          "id": {
+            "type": "keyword"
+          },
+          "title": {
+            "type": "text",
+            "fields": {
+              "keyword": {
+                "type": "keyword",
+                "ignore_above": 256
+              }
+            }
+          },
+          "status": {
+            "type": "keyword"
+          },
+          "conferenceId": {
+            "type": "keyword"
+          },
+          "conferenceSlug": {
+            "type": "keyword"
+          }
+        }
+      },
+      "conferences": {
+        "properties": {
+          "id": {
+            "type": "keyword"
+          },
+          "slug": {
+            "type": "keyword"
+          },
+          "name": {
+            "type": "text",
+            "fields": {
+              "keyword": {
+                "type": "keyword",
+                "ignore_above": 256
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// SpeakerPublicIndexMapping defines the Elasticsearch mapping for the public speakers
+// index, holding one profile per speaker of an approved talk without private data.
+// Only the speaker's approved talks are listed.
+const SpeakerPublicIndexMapping = `{
+  "settings": {
+    "number_of_shards": 1,
+    "number_of_replicas": 1
+  },
+  "mappings": {
+    "date_detection": false,
+    "numeric_detection": false,
+    "dynamic_templates": [
+      {
+        "unknown_objects": {
+          "match_mapping_type": "object",
+          "mapping": {
+            "type": "object",
+            "enabled": false
+          }
+        }
+      },
+      {
+        "unknown_dates": {
+          "match_mapping_type": "string",
+          "match_pattern": "regex",
+          "match": "^.*(Time|Date|At)$",
+          "mapping": {
+            "type": "date",
+            "format": "strict_date_optional_time||epoch_millis",
+            "ignore_malformed": true
+          }
+        }
+      },
+      {
+        "unknown_strings": {
+          "match_mapping_type": "string",
+          "mapping": {
+            "type": "text",
+            "fields": {
+              "keyword": {
+                "type": "keyword",
+                "ignore_above": 256
+              }
+            }
+          }
+        }
+      },
+      {
+        "unknown_scalars": {
+          "match_mapping_type": ["long", "double", "boolean"],
+          "mapping": {
+            "type": "keyword"
+          }
+        }
+      }
+    ],
+    "properties": {
+      "id": {
+        "type": "keyword"
+      },
+      "name": {
+        "type": "text",
+        "fields": {
+          "keyword": {
+            "type": "keyword",
+            "ignore_above": 256
+          }
+        }
+      },
+      "data": {
+        "properties": {
+          "bio": {
+            "type": "text"
+          },
+          "twitter": {
+            "type": "keyword"
+          },
+          "linkedin": {
+            "type": "keyword",
+            "index": false
+          },
+          "bluesky": {
+            "type": "keyword"
+          },
+          "pictureId": {
+            "type": "keyword",
+            "index": false
+          }
+        }
+      },
+      "talks": {
+        "type": "nested",
+        "properties": {
+          "id": {
+            "type": "keyword"
+          },
+          "title": {
+            "type": "text",
+            "fields": {
+              "keyword": {
+                "type": "keyword",
+                "ignore_above": 256
+              }
+            }
+          },
+          "status": {
+            "type": "keyword"
+          },
+          "conferenceId": {
+            "type": "keyword"
+          },
+          "conferenceSlug": {
+            "type": "keyword"
+          }
+        }
+      },
+      "conferences": {
+        "properties": {
+          "id": {
+            "type": "keyword"
+          },
+          "slug": {
+            "type": "keyword"
+          },
+          "name": {
+            "type": "text",
+            "fields": {
+              "keyword": {
+                "type": "keyword",
+                "ignore_above": 256
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
@@ -0,0 +1,121 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMappings_LanguageSubfields(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping string
+		fields  []string
+	}{
+		{"private", TalkPrivateIndexMapping, []string{"title", "abstract", "outline"}},
+		{"public", TalkPublicIndexMapping, []string{"title", "abstract"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mapping struct {
+				Settings struct {
+					Analysis struct {
+						Analyzer map[string]json.RawMessage `json:"analyzer"`
+					} `json:"analysis"`
+				} `json:"settings"`
+				Mappings struct {
+					Properties struct {
+						Data struct {
+							Properties map[string]struct {
+								Fields map[string]struct {
+									Analyzer string `json:"analyzer"`
+								} `json:"fields"`
+							} `json:"properties"`
+						} `json:"data"`
+					} `json:"properties"`
+				} `json:"mappings"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(tt.mapping), &mapping))
+
+			for _, field := range tt.fields {
+				subfields := mapping.Mappings.Properties.Data.Properties[field].Fields
+				for _, language := range analyzedLanguages {
+					analyzer := subfields[language].Analyzer
+					assert.NotEmpty(t, analyzer, "data.%s.%s has no analyzer", field, language)
+					assert.Contains(t, mapping.Settings.Analysis.Analyzer, analyzer, "data.%s.%s uses an undefined analyzer", field, language)
+				}
+			}
+		})
+	}
+}
+
+func TestMappings_SpeakerProfiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		mapping     string
+		privateData bool
+	}{
+		{"private", SpeakerPrivateIndexMapping, true},
+		{"public", SpeakerPublicIndexMapping, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mapping struct {
+				Mappings struct {
+					Properties struct {
+						Data struct {
+							Properties map[string]json.RawMessage `json:"properties"`
+						} `json:"data"`
+						Talks struct {
+							Type string `json:"type"`
+						} `json:"talks"`
+					} `json:"properties"`
+				} `json:"mappings"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(tt.mapping), &mapping))
+
+			assert.Equal(t, "nested", mapping.Mappings.Properties.Talks.Type)
+			for _, field := range []string{"residence", "zip-code", "emailAlias", "speakerAlias"} {
+				_, ok := mapping.Mappings.Properties.Data.Properties[field]
+				assert.Equal(t, tt.privateData, ok, "data.%s", field)
+			}
+		})
+	}
+}
+
+func TestMappings_DynamicTemplates(t *testing.T) {
+	mappings := map[string]string{
+		"talk private":    TalkPrivateIndexMapping,
+		"talk public":     TalkPublicIndexMapping,
+		"speaker private": SpeakerPrivateIndexMapping,
+		"speaker public":  SpeakerPublicIndexMapping,
+	}
+
+	for name, mapping := range mappings {
+		t.Run(name, func(t *testing.T) {
+			var parsed struct {
+				Mappings struct {
+					DateDetection    *bool                               `json:"date_detection"`
+					DynamicTemplates []map[string]map[string]interface{} `json:"dynamic_templates"`
+				} `json:"mappings"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(mapping), &parsed))
+
+			require.NotNil(t, parsed.Mappings.DateDetection)
+			assert.False(t, *parsed.Mappings.DateDetection)
+
+			var names []string
+			for _, template := range parsed.Mappings.DynamicTemplates {
+				for templateName := range template {
+					names = append(names, templateName)
+				}
+			}
+			// Objects must come first so unknown objects never fall through to another template
+			assert.Equal(t, []string{"unknown_objects", "unknown_dates", "unknown_strings", "unknown_scalars"}, names)
+		})
+	}
+}
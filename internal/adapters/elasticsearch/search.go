@@ -0,0 +1,238 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// searchTextFields are the talk fields matched by the search text, with their boosts
+var searchTextFields = []string{"data.title^3", "data.abstract", "data.outline", "data.keywords"}
+
+// analyzedTextFields are the text fields with a subfield analyzed per talk language,
+// with their boosts
+var analyzedTextFields = []string{"data.title^3", "data.abstract", "data.outline"}
+
+// analyzedLanguages are the talk languages (data.language) that have analyzed subfields
+// in the index mappings, named after the language
+var analyzedLanguages = []string{"no", "en"}
+
+// languageTextFields returns the search text fields together with the subfields
+// analyzed for the given language
+func languageTextFields(language string) []string {
+	fields := slices.Clone(searchTextFields)
+	for _, field := range analyzedTextFields {
+		name, boost, _ := strings.Cut(field, "^")
+		subfield := name + "." + language
+		if boost != "" {
+			subfield += "^" + boost
+		}
+		fields = append(fields, subfield)
+	}
+	return fields
+}
+
+// Search runs a full-text search over talk titles, abstracts, outlines, keywords and
+// speaker names, restricted by the query's filters, and returns the requested page.
+// Results are ordered by relevance, then by talk ID so pages are stable.
+// A missing index yields an empty result.
+func (c *Client) Search(ctx context.Context, indexName string, query domain.SearchQuery) (*domain.SearchResult, error) {
+	body := map[string]interface{}{
+		"query":            searchQuery(query),
+		"from":             query.From(),
+		"size":             query.Size,
+		"track_total_hits": true,
+		"sort": []interface{}{
+			"_score",
+			map[string]interface{}{"id": "asc"},
+		},
+	}
+
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal search request: %w", err)
+	}
+
+	req := esapi.SearchRequest{
+		Index: []string{indexName},
+		Body:  bytes.NewReader(bodyJSON),
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search index %s: %w", indexName, err)
+	}
+	defer res.Body.Close()
+
+	result := &domain.SearchResult{
+		Page: query.Page,
+		Size: query.Size,
+		Hits: []domain.SearchHit{},
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return result, nil
+	}
+
+	if res.IsError() {
+		errBody, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("search error: %s - %s", res.Status(), string(errBody))
+	}
+
+	var searchResponse struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID     string      `json:"_id"`
+				Score  *float64    `json:"_score"`
+				Source domain.Talk `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&searchResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse search response: %w", err)
+	}
+
+	result.Total = searchResponse.Hits.Total.Value
+	for _, hit := range searchResponse.Hits.Hits {
+		searchHit := domain.SearchHit{ID: hit.ID, Talk: hit.Source}
+		if hit.Score != nil {
+			searchHit.Score = *hit.Score
+		}
+		result.Hits = append(result.Hits, searchHit)
+	}
+
+	return result, nil
+}
+
+// searchFilter is a filterable field and the values a talk must have one of
+type searchFilter struct {
+	facet  string
+	field  string
+	values []string
+}
+
+// searchFilters returns every filterable field of a query in a fixed order, including
+// those without values. The fields are the keyword fields shared by the private and
+// public index mappings, and each is also a facet.
+func searchFilters(filters domain.SearchFilters) []searchFilter {
+	return []searchFilter{
+		{domain.FacetConference, "conferenceSlug", filters.ConferenceSlugs},
+		{domain.FacetStatus, "status", filters.Statuses},
+		{domain.FacetFormat, "data.format", filters.Formats},
+		{domain.FacetLanguage, "data.language", filters.Languages},
+		{domain.FacetLength, "data.length", filters.Lengths},
+		{domain.FacetLevel, "data.level", filters.Levels},
+		{domain.FacetKeyword, "data.keywords.keyword", filters.Keywords},
+	}
+}
+
+// filterClauses returns a terms clause for every filter with values, except the
+// filter of the excluded facet
+func filterClauses(filters []searchFilter, exclude string) []interface{} {
+	clauses := []interface{}{}
+	for _, filter := range filters {
+		if len(filter.values) > 0 && filter.facet != exclude {
+			clauses = append(clauses, map[string]interface{}{
+				"terms": map[string]interface{}{filter.field: filter.values},
+			})
+		}
+	}
+	return clauses
+}
+
+// searchQuery builds the Elasticsearch query for a search. The text must match a
+// talk field or a speaker name, and every filter must match.
+func searchQuery(query domain.SearchQuery) map[string]interface{} {
+	return boolQuery(textClauses(query.Text), filterClauses(searchFilters(query.Filters), ""))
+}
+
+// textClauses returns the clause matching the search text against a talk field or a
+// speaker name, or no clauses for an empty text.
+// Talks in a language with analyzed subfields are matched against those as well, so
+// the text is stemmed with the talk's language; other talks only use the standard fields.
+func textClauses(text string) []interface{} {
+	if text == "" {
+		return nil
+	}
+
+	should := []interface{}{}
+	for _, language := range analyzedLanguages {
+		should = append(should, map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"term": map[string]interface{}{"data.language": language}},
+				},
+				"must": []interface{}{
+					map[string]interface{}{
+						"multi_match": map[string]interface{}{
+							"query":  text,
+							"type":   "most_fields",
+							"fields": languageTextFields(language),
+						},
+					},
+				},
+			},
+		})
+	}
+	should = append(should,
+		map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must_not": []interface{}{
+					map[string]interface{}{"terms": map[string]interface{}{"data.language": analyzedLanguages}},
+				},
+				"must": []interface{}{
+					map[string]interface{}{
+						"multi_match": map[string]interface{}{
+							"query":  text,
+							"fields": searchTextFields,
+						},
+					},
+				},
+			},
+		},
+		map[string]interface{}{
+			"nested": map[string]interface{}{
+				"path": "speakers",
+				"query": map[string]interface{}{
+					"match": map[string]interface{}{"speakers.name": text},
+				},
+			},
+		},
+	)
+
+	return []interface{}{
+		map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should":               should,
+				"minimum_should_match": 1,
+			},
+		},
+	}
+}
+
+// boolQuery combines scoring and filter clauses, matching everything when there are none
+func boolQuery(must, filter []interface{}) map[string]interface{} {
+	query := map[string]interface{}{}
+	if len(must) > 0 {
+		query["must"] = must
+	}
+	if len(filter) > 0 {
+		query["filter"] = filter
+	}
+
+	if len(query) == 0 {
+		return map[string]interface{}{"match_all": map[string]interface{}{}}
+	}
+	return map[string]interface{}{"bool": query}
+}
@@ -0,0 +1,140 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Search(t *testing.T) {
+	t.Run("text and filters", func(t *testing.T) {
+		var searchBody map[string]interface{}
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/javazone_public/_search" {
+				bodyBytes, _ := io.ReadAll(r.Body)
+				json.Unmarshal(bodyBytes, &searchBody)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"hits":{"total":{"value":42},"hits":[
+					{"_id":"talk-1","_score":3.5,"_source":{"id":"talk-1","conferenceSlug":"javazone2024","data":{"title":"Go in production"}}}
+				]}}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		query := domain.SearchQuery{
+			Text: "go",
+			Filters: domain.SearchFilters{
+				ConferenceSlugs: []string{"javazone2024"},
+				Languages:       []string{"en", "no"},
+			},
+			Page: 3,
+			Size: 10,
+		}
+		result, err := client.Search(context.Background(), "javazone_public", query)
+		require.NoError(t, err)
+
+		assert.Equal(t, 42, result.Total)
+		assert.Equal(t, 3, result.Page)
+		assert.Equal(t, 10, result.Size)
+		require.Len(t, result.Hits, 1)
+		assert.Equal(t, "talk-1", result.Hits[0].ID)
+		assert.Equal(t, 3.5, result.Hits[0].Score)
+		assert.Equal(t, "Go in production", result.Hits[0].Talk.Data["title"])
+
+		assert.Equal(t, float64(20), searchBody["from"])
+		assert.Equal(t, float64(10), searchBody["size"])
+
+		boolQuery := searchBody["query"].(map[string]interface{})["bool"].(map[string]interface{})
+		assert.Len(t, boolQuery["must"], 1)
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"terms": map[string]interface{}{"conferenceSlug": []interface{}{"javazone2024"}}},
+			map[string]interface{}{"terms": map[string]interface{}{"data.language": []interface{}{"en", "no"}}},
+		}, boolQuery["filter"])
+	})
+
+	t.Run("empty query matches everything", func(t *testing.T) {
+		var searchBody map[string]interface{}
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bodyBytes, _ := io.ReadAll(r.Body)
+			json.Unmarshal(bodyBytes, &searchBody)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"hits":{"total":{"value":0},"hits":[]}}`))
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.Search(context.Background(), "javazone_public", domain.SearchQuery{Page: 1, Size: 20})
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, result.Total)
+		assert.Empty(t, result.Hits)
+		assert.Equal(t, map[string]interface{}{"match_all": map[string]interface{}{}}, searchBody["query"])
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"index_not_found_exception"}`))
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.Search(context.Background(), "javazone_public", domain.SearchQuery{Page: 1, Size: 20})
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Total)
+		assert.Empty(t, result.Hits)
+	})
+}
+
+func TestTextClauses_LanguageAnalyzedFields(t *testing.T) {
+	clauses := textClauses("testing")
+	require.Len(t, clauses, 1)
+
+	should := clauses[0].(map[string]interface{})["bool"].(map[string]interface{})["should"].([]interface{})
+	require.Len(t, should, 4)
+
+	languageClause := func(i int) (string, []string) {
+		clause := should[i].(map[string]interface{})["bool"].(map[string]interface{})
+		term := clause["filter"].([]interface{})[0].(map[string]interface{})["term"].(map[string]interface{})
+		match := clause["must"].([]interface{})[0].(map[string]interface{})["multi_match"].(map[string]interface{})
+		return term["data.language"].(string), match["fields"].([]string)
+	}
+
+	language, fields := languageClause(0)
+	assert.Equal(t, "no", language)
+	assert.Contains(t, fields, "data.title^3")
+	assert.Contains(t, fields, "data.title.no^3")
+	assert.Contains(t, fields, "data.abstract.no")
+	assert.Contains(t, fields, "data.outline.no")
+	assert.NotContains(t, fields, "data.abstract.en")
+
+	language, fields = languageClause(1)
+	assert.Equal(t, "en", language)
+	assert.Contains(t, fields, "data.title.en^3")
+	assert.NotContains(t, fields, "data.title.no^3")
+
+	// Talks in other languages only match the standard fields
+	other := should[2].(map[string]interface{})["bool"].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"terms": map[string]interface{}{"data.language": analyzedLanguages}},
+	}, other["must_not"])
+	match := other["must"].([]interface{})[0].(map[string]interface{})["multi_match"].(map[string]interface{})
+	assert.Equal(t, searchTextFields, match["fields"])
+
+	assert.Contains(t, should[3], "nested")
+}
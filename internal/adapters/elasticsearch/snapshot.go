@@ -0,0 +1,403 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// snapshotKeepAlive is how long the point in time of an export is kept between pages
+const snapshotKeepAlive = "2m"
+
+// maxSnapshotLine is the largest document line read from a snapshot
+const maxSnapshotLine = 64 << 20
+
+// snapshotSettings are the index settings kept in a snapshot; the others are assigned
+// by the cluster and cannot be set when the index is restored
+var snapshotSettings = []string{"number_of_shards", "number_of_replicas", "analysis"}
+
+// ExportIndex writes a snapshot of an index, or the single index behind an alias, to w:
+// a header line with the index definition followed by one line per document.
+// Documents are read from a point in time, so writes during the export are not included.
+func (c *Client) ExportIndex(ctx context.Context, indexName string, w io.Writer) (*domain.SnapshotHeader, error) {
+	sourceIndex, definition, err := c.indexDefinition(ctx, indexName)
+	if err != nil {
+		return nil, err
+	}
+
+	pitID, err := c.openPointInTime(ctx, sourceIndex)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := c.closePointInTime(context.WithoutCancel(ctx), pitID); err != nil {
+			c.logger.Warn("failed to close point in time", "index", sourceIndex, "error", err)
+		}
+	}()
+
+	header := &domain.SnapshotHeader{
+		Format:      domain.SnapshotFormat,
+		Version:     domain.SnapshotVersion,
+		Index:       indexName,
+		SourceIndex: sourceIndex,
+		ExportedAt:  time.Now().UTC(),
+		Definition:  definition,
+	}
+
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+
+	var searchAfter []interface{}
+	for page := 0; ; page++ {
+		body := map[string]interface{}{
+			"size":             scanPageSize,
+			"pit":              map[string]interface{}{"id": pitID, "keep_alive": snapshotKeepAlive},
+			"sort":             []interface{}{map[string]interface{}{"_shard_doc": "asc"}},
+			"track_total_hits": page == 0,
+		}
+		if searchAfter != nil {
+			body["search_after"] = searchAfter
+		}
+
+		response, err := c.searchPointInTime(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		pitID = response.PitID
+
+		// The total is only known once the first page is in, so the header is written then
+		if page == 0 {
+			header.Documents = response.Hits.Total.Value
+			if err := encoder.Encode(header); err != nil {
+				return nil, fmt.Errorf("failed to write snapshot header: %w", err)
+			}
+		}
+
+		hits := response.Hits.Hits
+		for _, hit := range hits {
+			if err := encoder.Encode(domain.SnapshotDocument{ID: hit.ID, Source: hit.Source}); err != nil {
+				return nil, fmt.Errorf("failed to write document %s: %w", hit.ID, err)
+			}
+		}
+
+		if len(hits) < scanPageSize {
+			break
+		}
+		searchAfter = hits[len(hits)-1].Sort
+	}
+
+	if err := buffered.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	c.logger.Info("exported index", "index", indexName, "sourceIndex", sourceIndex, "documents", header.Documents)
+	return header, nil
+}
+
+// ImportIndex creates a new index from the definition in a snapshot header and bulk
+// indexes the snapshot's documents into it. The index must not exist yet. The
+// definition is used as exported, so the index keeps the mapping hash of the
+// exported index. Documents with a lastUpdated time are indexed with it as their
+// external version, like BulkIndex does. Rejected documents are returned as failures
+// in the result.
+func (c *Client) ImportIndex(ctx context.Context, indexName string, r io.Reader) (*domain.SnapshotResult, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxSnapshotLine)
+
+	header, err := readSnapshotHeader(scanner)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.createIndexFromDefinition(ctx, indexName, header.Definition); err != nil {
+		return nil, err
+	}
+
+	result := &domain.SnapshotResult{
+		Index:       indexName,
+		SourceIndex: header.SourceIndex,
+		ExportedAt:  header.ExportedAt,
+		Documents:   header.Documents,
+	}
+
+	// Documents are sent in chunks large enough to keep every bulk worker busy
+	chunkSize := c.bulk.MaxDocs * c.bulk.Concurrency
+	var chunk []bulkItem
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		bulkResult, err := c.bulkIndex(ctx, indexName, "snapshot documents", chunk)
+		if err != nil {
+			return err
+		}
+		result.Indexed += bulkResult.Indexed
+		result.Failures = append(result.Failures, bulkResult.Failures...)
+		chunk = nil
+		return nil
+	}
+
+	for line := 2; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var doc domain.SnapshotDocument
+		if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse snapshot line %d: %w", line, err)
+		}
+		if doc.ID == "" || len(doc.Source) == 0 {
+			return nil, fmt.Errorf("snapshot line %d has no _id or _source", line)
+		}
+
+		item, err := encodeBulkItem(indexName, doc.ID, snapshotVersion(doc.Source), doc.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode document %s: %w", doc.ID, err)
+		}
+		chunk = append(chunk, item)
+
+		if len(chunk) >= chunkSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	if err := c.Refresh(ctx, indexName); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("imported snapshot",
+		"index", indexName,
+		"sourceIndex", header.SourceIndex,
+		"documents", header.Documents,
+		"indexed", result.Indexed,
+		"failed", len(result.Failures),
+	)
+	return result, nil
+}
+
+// snapshotVersion returns the external version of a snapshot document the same way
+// talkVersion does: its lastUpdated time in milliseconds, or zero if it has none.
+// Restored talks keep their version, so an older write cannot replace them.
+func snapshotVersion(source json.RawMessage) int64 {
+	var doc struct {
+		LastUpdated *time.Time `json:"lastUpdated"`
+	}
+	if err := json.Unmarshal(source, &doc); err != nil || doc.LastUpdated == nil {
+		return 0
+	}
+	return doc.LastUpdated.UnixMilli()
+}
+
+// readSnapshotHeader reads and validates the header line of a snapshot
+func readSnapshotHeader(scanner *bufio.Scanner) (*domain.SnapshotHeader, error) {
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read snapshot header: %w", err)
+		}
+		return nil, fmt.Errorf("snapshot is empty")
+	}
+
+	var header domain.SnapshotHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot header: %w", err)
+	}
+	if header.Format != domain.SnapshotFormat {
+		return nil, fmt.Errorf("not a snapshot: format is %q, want %q", header.Format, domain.SnapshotFormat)
+	}
+	if header.Version != domain.SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d, want %d", header.Version, domain.SnapshotVersion)
+	}
+	if len(header.Definition) == 0 {
+		return nil, fmt.Errorf("snapshot header has no index definition")
+	}
+
+	return &header, nil
+}
+
+// indexDefinition returns the name of the concrete index behind indexName and the
+// settings and mappings it can be recreated with
+func (c *Client) indexDefinition(ctx context.Context, indexName string) (string, json.RawMessage, error) {
+	req := esapi.IndicesGetRequest{
+		Index: []string{indexName},
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get index %s: %w", indexName, err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return "", nil, fmt.Errorf("get index error: %s - %s", res.Status(), string(body))
+	}
+
+	// Response is keyed by index name: {"index-a": {"settings": {...}, "mappings": {...}}}
+	var indexResponse map[string]struct {
+		Settings struct {
+			Index map[string]interface{} `json:"index"`
+		} `json:"settings"`
+		Mappings json.RawMessage `json:"mappings"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&indexResponse); err != nil {
+		return "", nil, fmt.Errorf("failed to parse index response: %w", err)
+	}
+
+	if len(indexResponse) != 1 {
+		names := make([]string, 0, len(indexResponse))
+		for name := range indexResponse {
+			names = append(names, name)
+		}
+		return "", nil, fmt.Errorf("%s resolves to %d indexes (%s), expected one", indexName, len(indexResponse), strings.Join(names, ", "))
+	}
+
+	for sourceIndex, index := range indexResponse {
+		settings := make(map[string]interface{})
+		for _, name := range snapshotSettings {
+			if value, ok := index.Settings.Index[name]; ok {
+				settings[name] = value
+			}
+		}
+
+		definition, err := json.Marshal(map[string]interface{}{
+			"settings": map[string]interface{}{"index": settings},
+			"mappings": index.Mappings,
+		})
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encode index definition: %w", err)
+		}
+		return sourceIndex, definition, nil
+	}
+
+	return "", nil, nil
+}
+
+// createIndexFromDefinition creates an index with the definition exactly as given
+func (c *Client) createIndexFromDefinition(ctx context.Context, indexName string, definition json.RawMessage) error {
+	req := esapi.IndicesCreateRequest{
+		Index: indexName,
+		Body:  bytes.NewReader(definition),
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return fmt.Errorf("failed to create index %s: %w", indexName, err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("create index error: %s - %s", res.Status(), string(body))
+	}
+
+	c.logger.Info("created index from snapshot definition", "index", indexName)
+	return nil
+}
+
+// pitSearchResponse is a page of a point in time search
+type pitSearchResponse struct {
+	PitID string `json:"pit_id"`
+	Hits  struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []searchHit `json:"hits"`
+	} `json:"hits"`
+}
+
+// openPointInTime opens a point in time on the index and returns its ID
+func (c *Client) openPointInTime(ctx context.Context, indexName string) (string, error) {
+	req := esapi.OpenPointInTimeRequest{
+		Index:     []string{indexName},
+		KeepAlive: snapshotKeepAlive,
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return "", fmt.Errorf("failed to open point in time on %s: %w", indexName, err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return "", fmt.Errorf("open point in time error: %s - %s", res.Status(), string(body))
+	}
+
+	var pit struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&pit); err != nil {
+		return "", fmt.Errorf("failed to parse point in time response: %w", err)
+	}
+	return pit.ID, nil
+}
+
+// searchPointInTime runs one page of a point in time search
+func (c *Client) searchPointInTime(ctx context.Context, body map[string]interface{}) (*pitSearchResponse, error) {
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal search request: %w", err)
+	}
+
+	req := esapi.SearchRequest{
+		Body: bytes.NewReader(bodyJSON),
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search point in time: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		errBody, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("search error: %s - %s", res.Status(), string(errBody))
+	}
+
+	var response pitSearchResponse
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to parse search response: %w", err)
+	}
+	return &response, nil
+}
+
+// closePointInTime releases a point in time before its keep alive expires
+func (c *Client) closePointInTime(ctx context.Context, pitID string) error {
+	body, err := json.Marshal(map[string]string{"id": pitID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal point in time: %w", err)
+	}
+
+	req := esapi.ClosePointInTimeRequest{
+		Body: bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return fmt.Errorf("failed to close point in time: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		errBody, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("close point in time error: %s - %s", res.Status(), string(errBody))
+	}
+	return nil
+}
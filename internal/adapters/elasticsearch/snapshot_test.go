@@ -0,0 +1,311 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportServer answers the requests of an export of the javazone_public alias,
+// backed by the javazone_public_1 index holding the given hits
+func exportServer(t *testing.T, hits []map[string]interface{}, closed *bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/javazone_public":
+			w.Write([]byte(`{"javazone_public_1":{
+				"aliases":{"javazone_public":{}},
+				"mappings":{"_meta":{"mappingHash":"abc"},"properties":{"id":{"type":"keyword"}}},
+				"settings":{"index":{"number_of_shards":"1","number_of_replicas":"0","uuid":"x","creation_date":"1","provided_name":"javazone_public_1"}}
+			}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/javazone_public_1/_pit":
+			assert.Equal(t, snapshotKeepAlive, r.URL.Query().Get("keep_alive"))
+			w.Write([]byte(`{"id":"pit-1"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/_search":
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "pit-1", body["pit"].(map[string]interface{})["id"])
+			assert.Equal(t, true, body["track_total_hits"])
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"pit_id": "pit-1",
+				"hits": map[string]interface{}{
+					"total": map[string]interface{}{"value": len(hits)},
+					"hits":  hits,
+				},
+			})
+		case r.Method == http.MethodDelete && r.URL.Path == "/_pit":
+			*closed = true
+			w.Write([]byte(`{"succeeded":true,"num_freed":1}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}
+}
+
+func TestClient_ExportIndex(t *testing.T) {
+	hits := []map[string]interface{}{
+		{"_id": "talk-1", "_source": map[string]interface{}{"id": "talk-1", "title": "First"}, "sort": []interface{}{1}},
+		{"_id": "talk-2", "_source": map[string]interface{}{"id": "talk-2", "title": "Second"}, "sort": []interface{}{2}},
+	}
+	var closed bool
+	server := createMockESServer(exportServer(t, hits, &closed))
+	defer server.Close()
+
+	client, err := New(server.URL, "", "")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	header, err := client.ExportIndex(context.Background(), "javazone_public", &out)
+
+	require.NoError(t, err)
+	assert.Equal(t, "javazone_public", header.Index)
+	assert.Equal(t, "javazone_public_1", header.SourceIndex)
+	assert.Equal(t, 2, header.Documents)
+	assert.True(t, closed, "point in time should be closed")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+
+	var written domain.SnapshotHeader
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &written))
+	assert.Equal(t, domain.SnapshotFormat, written.Format)
+	assert.Equal(t, domain.SnapshotVersion, written.Version)
+	assert.JSONEq(t, `{
+		"settings":{"index":{"number_of_shards":"1","number_of_replicas":"0"}},
+		"mappings":{"_meta":{"mappingHash":"abc"},"properties":{"id":{"type":"keyword"}}}
+	}`, string(written.Definition))
+
+	assert.JSONEq(t, `{"_id":"talk-1","_source":{"id":"talk-1","title":"First"}}`, lines[1])
+	assert.JSONEq(t, `{"_id":"talk-2","_source":{"id":"talk-2","title":"Second"}}`, lines[2])
+}
+
+func TestClient_ExportIndex_MultipleIndexes(t *testing.T) {
+	server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"javazone_public_1":{"mappings":{}},"javazone_public_2":{"mappings":{}}}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "", "")
+	require.NoError(t, err)
+
+	_, err = client.ExportIndex(context.Background(), "javazone_public", io.Discard)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "resolves to 2 indexes")
+}
+
+func TestClient_ImportIndex(t *testing.T) {
+	snapshot := strings.Join([]string{
+		`{"format":"talks-indexer-snapshot","version":1,"index":"javazone_public","sourceIndex":"javazone_public_1","exportedAt":"2024-09-04T12:30:00Z","documents":2,"definition":{"mappings":{"_meta":{"mappingHash":"abc"}}}}`,
+		`{"_id":"talk-1","_source":{"id":"talk-1","title":"First"}}`,
+		``,
+		`{"_id":"talk-2","_source":{"id":"talk-2","title":"Second"}}`,
+	}, "\n")
+
+	t.Run("creates the index and indexes every document", func(t *testing.T) {
+		var created string
+		var bulkIDs []string
+		var refreshed bool
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			body, _ := io.ReadAll(r.Body)
+
+			switch {
+			case r.Method == http.MethodPut && r.URL.Path == "/restored":
+				created = string(body)
+				w.Write([]byte(`{"acknowledged":true}`))
+			case strings.HasSuffix(r.URL.Path, "/_bulk"):
+				ids := bulkRequestIDs(t, body)
+				bulkIDs = append(bulkIDs, ids...)
+				assert.NotContains(t, string(body), "version_type", "documents without lastUpdated are not versioned")
+				writeBulkResponse(w, ids, map[string]int{"talk-2": http.StatusBadRequest})
+			case strings.HasSuffix(r.URL.Path, "/_refresh"):
+				refreshed = true
+				w.Write([]byte(`{}`))
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.ImportIndex(context.Background(), "restored", strings.NewReader(snapshot))
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"mappings":{"_meta":{"mappingHash":"abc"}}}`, created, "definition should be used as exported")
+		assert.Equal(t, []string{"talk-1", "talk-2"}, bulkIDs)
+		assert.True(t, refreshed)
+		assert.Equal(t, "restored", result.Index)
+		assert.Equal(t, "javazone_public_1", result.SourceIndex)
+		assert.Equal(t, 2, result.Documents)
+		assert.Equal(t, 1, result.Indexed)
+		require.Len(t, result.Failures, 1)
+		assert.Equal(t, "talk-2", result.Failures[0].DocumentID)
+	})
+
+	t.Run("restored talks keep their version and reject older writes", func(t *testing.T) {
+		lastUpdated := time.Date(2024, 9, 4, 12, 30, 0, 0, time.UTC)
+		versioned := strings.Join([]string{
+			`{"format":"talks-indexer-snapshot","version":1,"index":"javazone_public","sourceIndex":"javazone_public_1","exportedAt":"2024-09-04T12:30:00Z","documents":1,"definition":{}}`,
+			`{"_id":"talk-1","_source":{"id":"talk-1","title":"First","lastUpdated":"2024-09-04T12:30:00Z"}}`,
+		}, "\n")
+
+		// The mock enforces external_gte versions the way Elasticsearch does
+		versions := map[string]float64{}
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			body, _ := io.ReadAll(r.Body)
+
+			switch {
+			case r.Method == http.MethodPut && r.URL.Path == "/restored":
+				w.Write([]byte(`{"acknowledged":true}`))
+			case strings.HasSuffix(r.URL.Path, "/_bulk"):
+				var ids []string
+				statuses := map[string]int{}
+				scanner := bufio.NewScanner(bytes.NewReader(body))
+				scanner.Buffer(make([]byte, 1<<20), 1<<20)
+				for line := 0; scanner.Scan(); line++ {
+					if line%2 != 0 {
+						continue
+					}
+					var action map[string]map[string]interface{}
+					require.NoError(t, json.Unmarshal(scanner.Bytes(), &action))
+					id := action["index"]["_id"].(string)
+					version, _ := action["index"]["version"].(float64)
+					assert.Equal(t, "external_gte", action["index"]["version_type"])
+					ids = append(ids, id)
+					if current, ok := versions[id]; ok && version < current {
+						statuses[id] = http.StatusConflict
+						continue
+					}
+					versions[id] = version
+				}
+				writeVersionedBulkResponse(w, ids, statuses)
+			case strings.HasSuffix(r.URL.Path, "/_refresh"):
+				w.Write([]byte(`{}`))
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.ImportIndex(context.Background(), "restored", strings.NewReader(versioned))
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Indexed)
+		assert.Equal(t, float64(lastUpdated.UnixMilli()), versions["talk-1"])
+
+		older := lastUpdated.Add(-time.Hour)
+		bulkResult, err := client.BulkIndex(context.Background(), "restored", []domain.Talk{{ID: "talk-1", LastUpdated: &older}})
+		require.NoError(t, err)
+		assert.Equal(t, 0, bulkResult.Indexed)
+		assert.Equal(t, 1, bulkResult.Stale, "an older write should not replace the restored talk")
+		assert.Equal(t, float64(lastUpdated.UnixMilli()), versions["talk-1"])
+	})
+
+	t.Run("rejects invalid snapshots before creating the index", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		tests := []struct {
+			name     string
+			snapshot string
+			wantErr  string
+		}{
+			{name: "empty", snapshot: "", wantErr: "snapshot is empty"},
+			{name: "not json", snapshot: "talks\n", wantErr: "failed to parse snapshot header"},
+			{name: "other format", snapshot: `{"format":"other","version":1}`, wantErr: "not a snapshot"},
+			{name: "newer version", snapshot: `{"format":"talks-indexer-snapshot","version":2}`, wantErr: "unsupported snapshot version 2"},
+			{name: "no definition", snapshot: `{"format":"talks-indexer-snapshot","version":1}`, wantErr: "no index definition"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := client.ImportIndex(context.Background(), "restored", strings.NewReader(tt.snapshot))
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestClient_ExportImport_RoundTrip(t *testing.T) {
+	hits := []map[string]interface{}{
+		{"_id": "talk-1", "_source": map[string]interface{}{"id": "talk-1", "abstract": "line one\nline two"}, "sort": []interface{}{1}},
+	}
+	var closed bool
+	exporter := createMockESServer(exportServer(t, hits, &closed))
+	defer exporter.Close()
+
+	var sources []string
+	importer := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		body, _ := io.ReadAll(r.Body)
+		if !strings.HasSuffix(r.URL.Path, "/_bulk") {
+			w.Write([]byte(`{"acknowledged":true}`))
+			return
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for line := 0; scanner.Scan(); line++ {
+			if line%2 == 1 {
+				sources = append(sources, scanner.Text())
+			}
+		}
+		writeBulkResponse(w, bulkRequestIDs(t, body), nil)
+	}))
+	defer importer.Close()
+
+	source, err := New(exporter.URL, "", "")
+	require.NoError(t, err)
+	target, err := New(importer.URL, "", "")
+	require.NoError(t, err)
+
+	var snapshot bytes.Buffer
+	_, err = source.ExportIndex(context.Background(), "javazone_public", &snapshot)
+	require.NoError(t, err)
+
+	result, err := target.ImportIndex(context.Background(), "restored", &snapshot)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Indexed)
+	require.Len(t, sources, 1)
+	assert.JSONEq(t, `{"id":"talk-1","abstract":"line one\nline two"}`, sources[0])
+}
+
+// writeVersionedBulkResponse answers a bulk request, rejecting the given documents with
+// a version conflict
+func writeVersionedBulkResponse(w http.ResponseWriter, ids []string, statuses map[string]int) {
+	items := make([]map[string]interface{}, len(ids))
+	for i, id := range ids {
+		details := map[string]interface{}{"_id": id, "status": http.StatusCreated}
+		if status, ok := statuses[id]; ok {
+			details["status"] = status
+			details["error"] = map[string]interface{}{"type": versionConflictType, "reason": "version conflict"}
+		}
+		items[i] = map[string]interface{}{"index": details}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"errors": len(statuses) > 0, "items": items})
+}
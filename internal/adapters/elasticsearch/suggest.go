@@ -0,0 +1,238 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// suggestField is the document field holding the completion inputs. It is derived
+// from the talk when indexing and left out of documents read back from the index.
+const suggestField = "suggest"
+
+// maxSuggestWords bounds how many words into a title or name a suggestion can start
+const maxSuggestWords = 10
+
+// suggestOverfetch is how many times the requested size is fetched for groups
+// whose options are deduplicated afterwards
+const suggestOverfetch = 3
+
+// completionInput is the input of a completion field
+type completionInput struct {
+	Input []string `json:"input"`
+}
+
+// talkSuggest holds the completion inputs of a talk, one field per suggestion group
+type talkSuggest struct {
+	Title   *completionInput `json:"title,omitempty"`
+	Speaker *completionInput `json:"speaker,omitempty"`
+	Keyword *completionInput `json:"keyword,omitempty"`
+}
+
+// indexedTalk is a talk as written to the index, with its completion inputs
+type indexedTalk struct {
+	domain.Talk
+	Suggest talkSuggest `json:"suggest"`
+}
+
+// newIndexedTalk adds the completion inputs to a talk. Titles and speaker names can be
+// completed from the start of any of their words, so "kot" suggests "Modern Kotlin".
+func newIndexedTalk(talk domain.Talk) indexedTalk {
+	doc := indexedTalk{Talk: talk}
+
+	if title, ok := talk.Data["title"].(string); ok {
+		doc.Suggest.Title = newCompletionInput(wordSuffixes(title))
+	}
+
+	var names []string
+	for _, speaker := range talk.Speakers {
+		names = append(names, wordSuffixes(speaker.Name)...)
+	}
+	doc.Suggest.Speaker = newCompletionInput(names)
+
+	doc.Suggest.Keyword = newCompletionInput(stringValues(talk.Data["keywords"]))
+
+	return doc
+}
+
+// newCompletionInput returns the input of a completion field, or nil without any inputs
+func newCompletionInput(inputs []string) *completionInput {
+	if len(inputs) == 0 {
+		return nil
+	}
+	return &completionInput{Input: inputs}
+}
+
+// wordSuffixes returns s and every suffix of it starting at a later word
+func wordSuffixes(s string) []string {
+	words := strings.Fields(s)
+	var suffixes []string
+	for i := 0; i < len(words) && i < maxSuggestWords; i++ {
+		suffixes = append(suffixes, strings.Join(words[i:], " "))
+	}
+	return suffixes
+}
+
+// stringValues returns the non-empty strings of a string or list data value
+func stringValues(value interface{}) []string {
+	var values []string
+	switch v := value.(type) {
+	case string:
+		values = append(values, v)
+	case []string:
+		values = append(values, v...)
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	result := values[:0]
+	for _, s := range values {
+		if s = strings.TrimSpace(s); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// suggestOption is one option of a completion suggestion in a search response
+type suggestOption struct {
+	Text   string `json:"text"`
+	ID     string `json:"_id"`
+	Source struct {
+		Data struct {
+			Title string `json:"title"`
+		} `json:"data"`
+		Speakers []struct {
+			Name string `json:"name"`
+		} `json:"speakers"`
+	} `json:"_source"`
+}
+
+// Suggest returns completions of the query text for talk titles, speaker names and
+// keywords, limited to the query's conferences if any are given.
+// A missing index yields no suggestions.
+func (c *Client) Suggest(ctx context.Context, indexName string, query domain.SuggestQuery) (*domain.Suggestions, error) {
+	completion := func(field string, size int, skipDuplicates bool) map[string]interface{} {
+		options := map[string]interface{}{
+			"field": suggestField + "." + field,
+			"size":  size,
+		}
+		if skipDuplicates {
+			options["skip_duplicates"] = true
+		}
+		if len(query.ConferenceSlugs) > 0 {
+			options["contexts"] = map[string]interface{}{"conference": query.ConferenceSlugs}
+		}
+		return map[string]interface{}{"prefix": query.Text, "completion": options}
+	}
+
+	body := map[string]interface{}{
+		"size":    0,
+		"_source": []string{"id", "data.title", "speakers.name"},
+		"suggest": map[string]interface{}{
+			"title":   completion("title", query.Size*suggestOverfetch, false),
+			"speaker": completion("speaker", query.Size*suggestOverfetch, false),
+			"keyword": completion("keyword", query.Size, true),
+		},
+	}
+
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal suggest request: %w", err)
+	}
+
+	req := esapi.SearchRequest{
+		Index: []string{indexName},
+		Body:  bytes.NewReader(bodyJSON),
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest from index %s: %w", indexName, err)
+	}
+	defer res.Body.Close()
+
+	result := &domain.Suggestions{
+		Titles:   []domain.Suggestion{},
+		Speakers: []domain.Suggestion{},
+		Keywords: []domain.Suggestion{},
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return result, nil
+	}
+
+	if res.IsError() {
+		errBody, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("suggest error: %s - %s", res.Status(), string(errBody))
+	}
+
+	var suggestResponse struct {
+		Suggest map[string][]struct {
+			Options []suggestOption `json:"options"`
+		} `json:"suggest"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&suggestResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse suggest response: %w", err)
+	}
+
+	options := func(name string) []suggestOption {
+		var result []suggestOption
+		for _, entry := range suggestResponse.Suggest[name] {
+			result = append(result, entry.Options...)
+		}
+		return result
+	}
+
+	// A title matches once per word suffix, so keep the first option of each talk
+	seen := make(map[string]bool)
+	for _, option := range options("title") {
+		if len(result.Titles) == query.Size || seen[option.ID] {
+			continue
+		}
+		seen[option.ID] = true
+		result.Titles = append(result.Titles, domain.Suggestion{Text: option.Source.Data.Title, TalkID: option.ID})
+	}
+
+	// Speakers of several talks match many times, so keep each name once
+	seen = make(map[string]bool)
+	for _, option := range options("speaker") {
+		name := matchingSpeaker(option, query.Text)
+		if len(result.Speakers) == query.Size || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result.Speakers = append(result.Speakers, domain.Suggestion{Text: name})
+	}
+
+	for _, option := range options("keyword") {
+		result.Keywords = append(result.Keywords, domain.Suggestion{Text: option.Text})
+	}
+
+	return result, nil
+}
+
+// matchingSpeaker returns the full name of the speaker of a suggestion whose name has
+// a word starting with the prefix, falling back to the matched input
+func matchingSpeaker(option suggestOption, prefix string) string {
+	prefix = strings.ToLower(prefix)
+	for _, speaker := range option.Source.Speakers {
+		for _, suffix := range wordSuffixes(speaker.Name) {
+			if strings.HasPrefix(strings.ToLower(suffix), prefix) {
+				return speaker.Name
+			}
+		}
+	}
+	return option.Text
+}
@@ -0,0 +1,122 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIndexedTalk(t *testing.T) {
+	talk := domain.Talk{
+		ID:             "talk-1",
+		ConferenceSlug: "javazone2024",
+		Speakers:       domain.Speakers{{Name: "Kari Nordmann"}},
+		Data: map[string]interface{}{
+			"title":    "Modern Kotlin in practice",
+			"keywords": []interface{}{"kotlin", " ", "jvm"},
+		},
+	}
+
+	data, err := json.Marshal(newIndexedTalk(talk))
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	// The talk fields are kept at the top level of the document
+	assert.Equal(t, "talk-1", doc["id"])
+	assert.Equal(t, "javazone2024", doc["conferenceSlug"])
+
+	assert.Equal(t, map[string]interface{}{
+		"title":   map[string]interface{}{"input": []interface{}{"Modern Kotlin in practice", "Kotlin in practice", "in practice", "practice"}},
+		"speaker": map[string]interface{}{"input": []interface{}{"Kari Nordmann", "Nordmann"}},
+		"keyword": map[string]interface{}{"input": []interface{}{"kotlin", "jvm"}},
+	}, doc[suggestField])
+}
+
+func TestNewIndexedTalk_WithoutInputs(t *testing.T) {
+	data, err := json.Marshal(newIndexedTalk(domain.Talk{ID: "talk-1"}))
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, map[string]interface{}{}, doc[suggestField])
+}
+
+func TestClient_Suggest(t *testing.T) {
+	t.Run("grouped and deduplicated", func(t *testing.T) {
+		var searchBody map[string]interface{}
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/javazone_public/_search" {
+				bodyBytes, _ := io.ReadAll(r.Body)
+				json.Unmarshal(bodyBytes, &searchBody)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"suggest":{
+					"title":[{"text":"kot","options":[
+						{"text":"Kotlin in practice","_id":"talk-1","_source":{"data":{"title":"Modern Kotlin in practice"}}},
+						{"text":"Kotlin","_id":"talk-2","_source":{"data":{"title":"Why Kotlin"}}},
+						{"text":"Kotlin in practice","_id":"talk-1","_source":{"data":{"title":"Modern Kotlin in practice"}}}
+					]}],
+					"speaker":[{"text":"kot","options":[
+						{"text":"Kotlinsen","_id":"talk-1","_source":{"speakers":[{"name":"Ola Nordmann"},{"name":"Kari Kotlinsen"}]}},
+						{"text":"Kotlinsen","_id":"talk-3","_source":{"speakers":[{"name":"Kari Kotlinsen"}]}}
+					]}],
+					"keyword":[{"text":"kot","options":[{"text":"kotlin","_id":"talk-1"}]}]
+				}}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.Suggest(context.Background(), "javazone_public", domain.SuggestQuery{
+			Text:            "kot",
+			ConferenceSlugs: []string{"javazone2024"},
+			Size:            5,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []domain.Suggestion{
+			{Text: "Modern Kotlin in practice", TalkID: "talk-1"},
+			{Text: "Why Kotlin", TalkID: "talk-2"},
+		}, result.Titles)
+		assert.Equal(t, []domain.Suggestion{{Text: "Kari Kotlinsen"}}, result.Speakers)
+		assert.Equal(t, []domain.Suggestion{{Text: "kotlin"}}, result.Keywords)
+
+		suggest := searchBody["suggest"].(map[string]interface{})
+		keyword := suggest["keyword"].(map[string]interface{})
+		assert.Equal(t, "kot", keyword["prefix"])
+		completion := keyword["completion"].(map[string]interface{})
+		assert.Equal(t, "suggest.keyword", completion["field"])
+		assert.Equal(t, true, completion["skip_duplicates"])
+		assert.Equal(t, map[string]interface{}{"conference": []interface{}{"javazone2024"}}, completion["contexts"])
+
+		title := suggest["title"].(map[string]interface{})["completion"].(map[string]interface{})
+		assert.Equal(t, float64(5*suggestOverfetch), title["size"])
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		server := createMockESServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"index_not_found_exception"}`))
+		}))
+		defer server.Close()
+
+		client, err := New(server.URL, "", "")
+		require.NoError(t, err)
+
+		result, err := client.Suggest(context.Background(), "javazone_public", domain.SuggestQuery{Text: "go", Size: 5})
+		require.NoError(t, err)
+		assert.Empty(t, result.Titles)
+		assert.Empty(t, result.Speakers)
+		assert.Empty(t, result.Keywords)
+	})
+}
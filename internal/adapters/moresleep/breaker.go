@@ -0,0 +1,151 @@
+package moresleep
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// BreakerOptions controls when the circuit breaker stops sending requests to moresleep
+type BreakerOptions struct {
+	// FailureThreshold is the number of consecutive failed attempts that opens the
+	// breaker. Zero disables the breaker.
+	FailureThreshold int
+
+	// Cooldown is how long the breaker stays open before a probe request is let through
+	Cooldown time.Duration
+}
+
+// DefaultBreakerOptions returns the breaker settings used unless SetBreakerOptions is called
+func DefaultBreakerOptions() BreakerOptions {
+	return BreakerOptions{
+		FailureThreshold: 5,
+		Cooldown:         30 * time.Second,
+	}
+}
+
+// attemptOutcome classifies a request attempt for the circuit breaker
+type attemptOutcome int
+
+const (
+	// outcomeSuccess means moresleep answered, even if with a client error such as 404
+	outcomeSuccess attemptOutcome = iota
+
+	// outcomeFailure means moresleep could not be reached or answered with a server error
+	outcomeFailure
+
+	// outcomeIgnored says nothing about moresleep's health, e.g. throttling or a cancelled request
+	outcomeIgnored
+)
+
+// circuitBreaker fails requests fast after moresleep has failed FailureThreshold times
+// in a row. After the cooldown one probe request is let through: if it succeeds the
+// breaker closes, otherwise it opens for another cooldown.
+type circuitBreaker struct {
+	opts BreakerOptions
+	now  func() time.Time
+
+	mu        sync.Mutex
+	state     domain.BreakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	lastError string
+}
+
+// newCircuitBreaker creates a closed breaker
+func newCircuitBreaker(opts BreakerOptions) *circuitBreaker {
+	return &circuitBreaker{
+		opts:  opts,
+		now:   time.Now,
+		state: domain.BreakerClosed,
+	}
+}
+
+// allow returns domain.ErrSourceUnavailable if a request may not be sent now.
+// Every allowed request must be followed by a call to record.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.opts.FailureThreshold < 1 {
+		return nil
+	}
+
+	switch b.state {
+	case domain.BreakerOpen:
+		retryAt := b.openedAt.Add(b.opts.Cooldown)
+		if b.now().Before(retryAt) {
+			return fmt.Errorf("%w: moresleep circuit breaker is open until %s after %d consecutive failures: %s",
+				domain.ErrSourceUnavailable, retryAt.Format(time.RFC3339), b.failures, b.lastError)
+		}
+		b.state = domain.BreakerHalfOpen
+		b.probing = true
+		return nil
+	case domain.BreakerHalfOpen:
+		if b.probing {
+			return fmt.Errorf("%w: moresleep circuit breaker is waiting for a probe request", domain.ErrSourceUnavailable)
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record updates the breaker with the outcome of an allowed request.
+// It returns true if the outcome opened the breaker.
+func (b *circuitBreaker) record(outcome attemptOutcome, err error) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.opts.FailureThreshold < 1 {
+		return false
+	}
+
+	wasProbe := b.state == domain.BreakerHalfOpen
+	b.probing = false
+
+	switch outcome {
+	case outcomeSuccess:
+		b.state = domain.BreakerClosed
+		b.failures = 0
+		b.lastError = ""
+		return false
+	case outcomeFailure:
+		b.failures++
+		if err != nil {
+			b.lastError = err.Error()
+		}
+		if wasProbe || b.failures >= b.opts.FailureThreshold {
+			b.state = domain.BreakerOpen
+			b.openedAt = b.now()
+			return true
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// status returns the current state of the breaker
+func (b *circuitBreaker) status() domain.BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := domain.BreakerStatus{
+		Name:                "moresleep",
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		LastError:           b.lastError,
+	}
+	if b.state != domain.BreakerClosed {
+		openedAt := b.openedAt
+		retryAt := b.openedAt.Add(b.opts.Cooldown)
+		status.OpenedAt = &openedAt
+		status.RetryAt = &retryAt
+	}
+	return status
+}
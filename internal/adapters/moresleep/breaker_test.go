@@ -0,0 +1,128 @@
+package moresleep
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	server := flakyServer(&calls, 500, 500, 500)
+	defer server.Close()
+
+	now := time.Date(2024, 9, 4, 12, 0, 0, 0, time.UTC)
+	client := New(server.URL, "", "")
+	client.SetRetryOptions(RetryOptions{})
+	client.SetBreakerOptions(BreakerOptions{FailureThreshold: 2, Cooldown: time.Minute})
+	client.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Two failures in a row open the breaker
+	_, err := client.GetConferences(ctx)
+	require.Error(t, err)
+	assert.Equal(t, domain.BreakerClosed, client.BreakerStatus().State)
+	_, err = client.GetConferences(ctx)
+	require.Error(t, err)
+
+	status := client.BreakerStatus()
+	assert.Equal(t, domain.BreakerOpen, status.State)
+	assert.Equal(t, 2, status.ConsecutiveFailures)
+	assert.Equal(t, now.Add(time.Minute), *status.RetryAt)
+	assert.Contains(t, status.LastError, "unexpected status code: 500")
+
+	// While open, requests fail fast without reaching moresleep
+	_, err = client.GetTalk(ctx, "talk-1")
+	assert.True(t, errors.Is(err, domain.ErrSourceUnavailable))
+	assert.Equal(t, int32(2), calls.Load())
+
+	// After the cooldown a failing probe opens the breaker again
+	now = now.Add(time.Minute)
+	_, err = client.GetConferences(ctx)
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, domain.ErrSourceUnavailable), "the probe should reach moresleep")
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, domain.BreakerOpen, client.BreakerStatus().State)
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	conferences, err := client.GetConferences(ctx)
+	require.NoError(t, err)
+	assert.Len(t, conferences, 1)
+
+	status = client.BreakerStatus()
+	assert.Equal(t, domain.BreakerClosed, status.State)
+	assert.Zero(t, status.ConsecutiveFailures)
+	assert.Nil(t, status.RetryAt)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("lets one probe through when half open", func(t *testing.T) {
+		now := time.Date(2024, 9, 4, 12, 0, 0, 0, time.UTC)
+		breaker := newCircuitBreaker(BreakerOptions{FailureThreshold: 1, Cooldown: time.Minute})
+		breaker.now = func() time.Time { return now }
+
+		require.NoError(t, breaker.allow())
+		assert.True(t, breaker.record(outcomeFailure, errors.New("connection refused")))
+
+		now = now.Add(time.Minute)
+		require.NoError(t, breaker.allow())
+		assert.Equal(t, domain.BreakerHalfOpen, breaker.status().State)
+		assert.ErrorIs(t, breaker.allow(), domain.ErrSourceUnavailable)
+
+		// An inconclusive probe frees the slot for another one
+		breaker.record(outcomeIgnored, nil)
+		require.NoError(t, breaker.allow())
+	})
+
+	t.Run("ignores throttling", func(t *testing.T) {
+		breaker := newCircuitBreaker(BreakerOptions{FailureThreshold: 2, Cooldown: time.Minute})
+
+		breaker.record(outcomeFailure, errors.New("bad gateway"))
+		breaker.record(outcomeIgnored, nil)
+		assert.Equal(t, 1, breaker.status().ConsecutiveFailures)
+		assert.Equal(t, domain.BreakerClosed, breaker.status().State)
+	})
+
+	t.Run("disabled with a zero threshold", func(t *testing.T) {
+		breaker := newCircuitBreaker(BreakerOptions{FailureThreshold: 0, Cooldown: time.Minute})
+
+		for range 10 {
+			require.NoError(t, breaker.allow())
+			assert.False(t, breaker.record(outcomeFailure, errors.New("connection refused")))
+		}
+		assert.Equal(t, domain.BreakerClosed, breaker.status().State)
+	})
+}
+
+func TestClassifyAttempt(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		cancelled bool
+		retryable bool
+		outcome   attemptOutcome
+	}{
+		{name: "success", err: nil, outcome: outcomeSuccess},
+		{name: "connection error", err: errors.New("connection refused"), retryable: true, outcome: outcomeFailure},
+		{name: "cancelled", err: context.Canceled, cancelled: true, outcome: outcomeIgnored},
+		{name: "server error", err: &statusError{status: http.StatusBadGateway, err: errors.New("502")}, retryable: true, outcome: outcomeFailure},
+		{name: "throttled", err: &statusError{status: http.StatusTooManyRequests, err: errors.New("429")}, retryable: true, outcome: outcomeIgnored},
+		{name: "not found", err: &statusError{status: http.StatusNotFound, err: errors.New("404")}, outcome: outcomeSuccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryable, outcome := classifyAttempt(tt.err, tt.cancelled)
+			assert.Equal(t, tt.retryable, retryable)
+			assert.Equal(t, tt.outcome, outcome)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package moresleep
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// DefaultCatalogTTL is how long the conference list is served from the catalog
+// before it is refreshed
+const DefaultCatalogTTL = 5 * time.Minute
+
+// catalogMissInterval is how old the conference list must be before a lookup of
+// an unknown conference refetches it, so a burst of lookups for a conference that
+// does not exist costs one request
+const catalogMissInterval = 30 * time.Second
+
+// catalogRefreshTimeout bounds a background refresh, which has no caller context
+const catalogRefreshTimeout = time.Minute
+
+// ConferenceCatalog caches the conference list. Once the TTL has passed the stale
+// list is still served while a single background refresh fetches a new one; only
+// an empty or invalidated catalog makes callers wait for moresleep. Concurrent
+// fetches are shared.
+type ConferenceCatalog struct {
+	fetch  func(ctx context.Context) ([]domain.Conference, error)
+	ttl    time.Duration
+	now    func() time.Time
+	logger *slog.Logger
+
+	mu          sync.Mutex
+	conferences []domain.Conference
+	byID        map[string]domain.Conference
+	fetchedAt   time.Time
+	refreshing  bool
+	inflight    *catalogFetch
+}
+
+// catalogFetch is a fetch of the conference list shared by concurrent callers
+type catalogFetch struct {
+	done        chan struct{}
+	conferences []domain.Conference
+	err         error
+}
+
+// NewConferenceCatalog creates a catalog that loads the conference list with fetch
+func NewConferenceCatalog(fetch func(ctx context.Context) ([]domain.Conference, error), ttl time.Duration) *ConferenceCatalog {
+	return &ConferenceCatalog{
+		fetch:  fetch,
+		ttl:    ttl,
+		now:    time.Now,
+		logger: slog.Default(),
+	}
+}
+
+// SetLogger sets a custom logger for the catalog
+func (c *ConferenceCatalog) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
+// SetTTL sets how long the conference list is served before it is refreshed
+func (c *ConferenceCatalog) SetTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+// GetConferences returns the cached conference list, fetching it if the catalog is
+// empty and refreshing it in the background if it has expired
+func (c *ConferenceCatalog) GetConferences(ctx context.Context) ([]domain.Conference, error) {
+	c.mu.Lock()
+	if c.conferences == nil {
+		c.mu.Unlock()
+		return c.load(ctx)
+	}
+
+	conferences := c.conferences
+	if c.now().Sub(c.fetchedAt) >= c.ttl && !c.refreshing {
+		c.refreshing = true
+		go c.refresh()
+	}
+	c.mu.Unlock()
+
+	return conferences, nil
+}
+
+// Conference looks up a conference by ID. An unknown ID refetches the list once it
+// is older than catalogMissInterval, so conferences created since the last fetch
+// are found. It returns false if the conference does not exist.
+func (c *ConferenceCatalog) Conference(ctx context.Context, id string) (domain.Conference, bool, error) {
+	if _, err := c.GetConferences(ctx); err != nil {
+		return domain.Conference{}, false, err
+	}
+
+	c.mu.Lock()
+	conference, ok := c.byID[id]
+	stale := c.now().Sub(c.fetchedAt) >= catalogMissInterval
+	c.mu.Unlock()
+
+	if ok || !stale {
+		return conference, ok, nil
+	}
+
+	c.logger.DebugContext(ctx, "conference not in catalog, refetching", "conferenceID", id)
+	if _, err := c.load(ctx); err != nil {
+		return domain.Conference{}, false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	conference, ok = c.byID[id]
+	return conference, ok, nil
+}
+
+// Store replaces the cached list with one fetched elsewhere and restarts the TTL
+func (c *ConferenceCatalog) Store(conferences []domain.Conference) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store(conferences)
+}
+
+// Invalidate drops the cached list so the next caller fetches a fresh one
+func (c *ConferenceCatalog) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conferences = nil
+	c.byID = nil
+	c.fetchedAt = time.Time{}
+}
+
+// load fetches the conference list and stores it, joining a fetch that is already
+// running instead of starting another
+func (c *ConferenceCatalog) load(ctx context.Context) ([]domain.Conference, error) {
+	c.mu.Lock()
+	if call := c.inflight; call != nil {
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.conferences, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	call := &catalogFetch{done: make(chan struct{})}
+	c.inflight = call
+	c.mu.Unlock()
+
+	call.conferences, call.err = c.fetch(ctx)
+
+	c.mu.Lock()
+	c.inflight = nil
+	if call.err == nil {
+		c.store(call.conferences)
+	}
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.conferences, call.err
+}
+
+// refresh fetches the conference list in the background. On failure the stale list
+// is kept and the next caller after the TTL tries again.
+func (c *ConferenceCatalog) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), catalogRefreshTimeout)
+	defer cancel()
+
+	if _, err := c.load(ctx); err != nil {
+		c.logger.Warn("failed to refresh conference catalog, serving stale conferences", "error", err)
+	}
+
+	c.mu.Lock()
+	c.refreshing = false
+	c.mu.Unlock()
+}
+
+// store replaces the cached list. The caller must hold the mutex.
+func (c *ConferenceCatalog) store(conferences []domain.Conference) {
+	if conferences == nil {
+		conferences = []domain.Conference{}
+	}
+
+	byID := make(map[string]domain.Conference, len(conferences))
+	for _, conference := range conferences {
+		byID[conference.ID] = conference
+	}
+
+	c.conferences = conferences
+	c.byID = byID
+	c.fetchedAt = c.now()
+}
@@ -0,0 +1,186 @@
+package moresleep
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCatalog returns a catalog backed by a counting fetch and a clock the test controls
+func testCatalog(fetch func(ctx context.Context) ([]domain.Conference, error)) (*ConferenceCatalog, *time.Time) {
+	now := time.Date(2024, 9, 4, 12, 0, 0, 0, time.UTC)
+	catalog := NewConferenceCatalog(fetch, time.Minute)
+	catalog.now = func() time.Time { return now }
+	return catalog, &now
+}
+
+func conferencesFetch(calls *atomic.Int32, conferences ...domain.Conference) func(ctx context.Context) ([]domain.Conference, error) {
+	return func(ctx context.Context) ([]domain.Conference, error) {
+		calls.Add(1)
+		return conferences, nil
+	}
+}
+
+func TestConferenceCatalog_GetConferences(t *testing.T) {
+	conf := domain.Conference{ID: "conf-1", Slug: "javazone2024", Name: "JavaZone 2024"}
+
+	t.Run("fetches once while fresh", func(t *testing.T) {
+		var calls atomic.Int32
+		catalog, _ := testCatalog(conferencesFetch(&calls, conf))
+
+		for range 3 {
+			conferences, err := catalog.GetConferences(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []domain.Conference{conf}, conferences)
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("serves stale conferences while refreshing in the background", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		refreshed := make(chan struct{})
+		catalog, now := testCatalog(func(ctx context.Context) ([]domain.Conference, error) {
+			if calls.Add(1) == 1 {
+				return []domain.Conference{conf}, nil
+			}
+			<-release
+			defer close(refreshed)
+			return []domain.Conference{conf, {ID: "conf-2"}}, nil
+		})
+
+		_, err := catalog.GetConferences(context.Background())
+		require.NoError(t, err)
+
+		*now = now.Add(2 * time.Minute)
+		for range 3 {
+			conferences, err := catalog.GetConferences(context.Background())
+			require.NoError(t, err)
+			assert.Len(t, conferences, 1, "stale list should be served without waiting")
+		}
+
+		close(release)
+		<-refreshed
+		assert.Eventually(t, func() bool {
+			conferences, _ := catalog.GetConferences(context.Background())
+			return len(conferences) == 2
+		}, time.Second, 5*time.Millisecond)
+		assert.Equal(t, int32(2), calls.Load(), "expired reads should share one refresh")
+	})
+
+	t.Run("keeps stale conferences when the refresh fails", func(t *testing.T) {
+		var calls atomic.Int32
+		catalog, now := testCatalog(func(ctx context.Context) ([]domain.Conference, error) {
+			if calls.Add(1) == 1 {
+				return []domain.Conference{conf}, nil
+			}
+			return nil, errors.New("moresleep unavailable")
+		})
+
+		_, err := catalog.GetConferences(context.Background())
+		require.NoError(t, err)
+
+		*now = now.Add(2 * time.Minute)
+		_, err = catalog.GetConferences(context.Background())
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, 5*time.Millisecond)
+
+		conferences, err := catalog.GetConferences(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Conference{conf}, conferences)
+	})
+
+	t.Run("invalidate fetches again", func(t *testing.T) {
+		var calls atomic.Int32
+		catalog, _ := testCatalog(conferencesFetch(&calls, conf))
+
+		_, err := catalog.GetConferences(context.Background())
+		require.NoError(t, err)
+		catalog.Invalidate()
+		_, err = catalog.GetConferences(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("returns the error when nothing is cached", func(t *testing.T) {
+		catalog, _ := testCatalog(func(ctx context.Context) ([]domain.Conference, error) {
+			return nil, errors.New("moresleep unavailable")
+		})
+
+		_, err := catalog.GetConferences(context.Background())
+
+		assert.EqualError(t, err, "moresleep unavailable")
+	})
+
+	t.Run("concurrent callers share a fetch", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		catalog, _ := testCatalog(func(ctx context.Context) ([]domain.Conference, error) {
+			calls.Add(1)
+			<-release
+			return []domain.Conference{conf}, nil
+		})
+
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				conferences, err := catalog.GetConferences(context.Background())
+				assert.NoError(t, err)
+				assert.Len(t, conferences, 1)
+			}()
+		}
+
+		assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, 5*time.Millisecond)
+		close(release)
+		wg.Wait()
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestConferenceCatalog_Conference(t *testing.T) {
+	conf := domain.Conference{ID: "conf-1", Slug: "javazone2024", Name: "JavaZone 2024"}
+
+	t.Run("finds a cached conference", func(t *testing.T) {
+		var calls atomic.Int32
+		catalog, _ := testCatalog(conferencesFetch(&calls, conf))
+
+		found, ok, err := catalog.Conference(context.Background(), "conf-1")
+
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, conf, found)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("refetches an unknown conference once the list is old enough", func(t *testing.T) {
+		var calls atomic.Int32
+		catalog, now := testCatalog(func(ctx context.Context) ([]domain.Conference, error) {
+			if calls.Add(1) == 1 {
+				return []domain.Conference{conf}, nil
+			}
+			return []domain.Conference{conf, {ID: "conf-2", Slug: "javazone2025"}}, nil
+		})
+
+		_, ok, err := catalog.Conference(context.Background(), "conf-2")
+		require.NoError(t, err)
+		assert.False(t, ok, "a just fetched list is not refetched")
+		assert.Equal(t, int32(1), calls.Load())
+
+		*now = now.Add(catalogMissInterval)
+		found, ok, err := catalog.Conference(context.Background(), "conf-2")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "javazone2025", found.Slug)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
@@ -18,41 +18,132 @@ type Client struct {
 	username   string
 	password   string
 	httpClient *http.Client
+	retry      RetryOptions
+	breaker    *circuitBreaker
+	catalog    *ConferenceCatalog
 	logger     *slog.Logger
 }
 
 // New creates a new moresleep Client
 // If username and password are provided, Basic Auth will be used for all requests
 func New(baseURL, username, password string) *Client {
-	return &Client{
-		baseURL:  baseURL,
-		username: username,
-		password: password,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		logger: slog.Default(),
-	}
+	return NewWithHTTPClient(baseURL, username, password, &http.Client{
+		Timeout: 30 * time.Second,
+	})
 }
 
 // NewWithHTTPClient creates a new moresleep Client with a custom HTTP client
 func NewWithHTTPClient(baseURL, username, password string, httpClient *http.Client) *Client {
-	return &Client{
+	c := &Client{
 		baseURL:    baseURL,
 		username:   username,
 		password:   password,
 		httpClient: httpClient,
+		retry:      DefaultRetryOptions(),
+		breaker:    newCircuitBreaker(DefaultBreakerOptions()),
 		logger:     slog.Default(),
 	}
+	c.catalog = NewConferenceCatalog(c.fetchConferences, DefaultCatalogTTL)
+	return c
 }
 
 // SetLogger sets a custom logger for the client
 func (c *Client) SetLogger(logger *slog.Logger) {
 	c.logger = logger
+	c.catalog.SetLogger(logger)
+}
+
+// SetTimeout sets how long a single request attempt may take, including reading the response
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.httpClient.Timeout = timeout
+}
+
+// SetRetryOptions sets how failed GET requests are retried.
+// Negative retries are treated as zero; delays below zero fall back to the defaults.
+func (c *Client) SetRetryOptions(opts RetryOptions) {
+	defaults := DefaultRetryOptions()
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.Backoff < 0 {
+		opts.Backoff = defaults.Backoff
+	}
+	if opts.MaxBackoff < opts.Backoff {
+		opts.MaxBackoff = opts.Backoff
+	}
+	c.retry = opts
+}
+
+// SetBreakerOptions replaces the circuit breaker with a closed one using the given
+// settings. A threshold below one disables the breaker.
+func (c *Client) SetBreakerOptions(opts BreakerOptions) {
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = DefaultBreakerOptions().Cooldown
+	}
+	c.breaker = newCircuitBreaker(opts)
+}
+
+// BreakerStatus returns the state of the circuit breaker guarding requests to moresleep
+func (c *Client) BreakerStatus() domain.BreakerStatus {
+	return c.breaker.status()
+}
+
+// Catalog returns the cached conference list the client resolves conference slugs
+// and names from. It can be shared with other consumers of the conference list.
+func (c *Client) Catalog() *ConferenceCatalog {
+	return c.catalog
+}
+
+// doRequest performs an HTTP request with optional Basic Auth and passes the body of
+// the 200 OK response to decode, which may read it as a stream. GET requests that fail
+// with a connection error, 429 Too Many Requests or a server error are retried with
+// backoff; once decode has started the request is not retried. While the circuit
+// breaker is open requests fail with domain.ErrSourceUnavailable without being sent.
+func (c *Client) doRequest(ctx context.Context, method, path string, decode func(io.Reader) error) error {
+	retryable := method == http.MethodGet
+
+	for attempt := 0; ; attempt++ {
+		if err := c.breaker.allow(); err != nil {
+			return err
+		}
+
+		resp, err := c.attemptRequest(ctx, method, path)
+		canRetry, outcome := classifyAttempt(err, ctx.Err() != nil)
+		if c.breaker.record(outcome, err) {
+			c.logger.ErrorContext(ctx, "moresleep circuit breaker opened",
+				"cooldown", c.breaker.opts.Cooldown,
+				"error", err,
+			)
+		}
+
+		if err == nil {
+			defer resp.Body.Close()
+			return decode(resp.Body)
+		}
+		if !retryable || !canRetry || attempt >= c.retry.MaxRetries {
+			return err
+		}
+
+		delay := c.retryDelay(attempt, err)
+		c.logger.WarnContext(ctx, "moresleep request failed, retrying",
+			"path", path,
+			"attempt", attempt+1,
+			"delay", delay,
+			"error", err,
+		)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w after %d attempts: %v", ctx.Err(), attempt+1, err)
+		case <-time.After(delay):
+		}
+	}
 }
 
-// doRequest performs an HTTP request with optional Basic Auth
-func (c *Client) doRequest(ctx context.Context, method, path string) ([]byte, error) {
+// attemptRequest sends a single request and returns the response with its body
+// unread. Responses other than 200 OK are closed and returned as a *statusError
+// holding a truncated snippet of the body.
+func (c *Client) attemptRequest(ctx context.Context, method, path string) (*http.Response, error) {
 	url := c.baseURL + path
 
 	req, err := http.NewRequestWithContext(ctx, method, url, nil)
@@ -76,20 +167,29 @@ func (c *Client) doRequest(ctx context.Context, method, path string) ([]byte, er
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
 
 	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body := readSnippet(resp.Body)
+
 		c.logger.ErrorContext(ctx, "HTTP request failed",
 			"status", resp.StatusCode,
 			"url", url,
-			"body", string(body),
+			"body", body,
 		)
-		return nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+		statusErr := &statusError{
+			status:     resp.StatusCode,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			statusErr.err = fmt.Errorf("%w: unexpected status code: %d, body: %s", domain.ErrNotFound, resp.StatusCode, body)
+		case http.StatusUnauthorized, http.StatusForbidden:
+			statusErr.err = fmt.Errorf("%w: unexpected status code: %d, body: %s", domain.ErrUnauthorized, resp.StatusCode, body)
+		default:
+			statusErr.err = fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, body)
+		}
+		return nil, statusErr
 	}
 
 	c.logger.DebugContext(ctx, "HTTP request successful",
@@ -97,33 +197,44 @@ func (c *Client) doRequest(ctx context.Context, method, path string) ([]byte, er
 		"url", url,
 	)
 
-	return body, nil
+	return resp, nil
 }
 
-// GetConferences retrieves all available conferences from the moresleep API
+// GetConferences retrieves all available conferences from the moresleep API.
+// It always fetches a fresh list, which also refreshes the catalog.
 func (c *Client) GetConferences(ctx context.Context) ([]domain.Conference, error) {
-	c.logger.InfoContext(ctx, "Fetching conferences from moresleep API")
-
-	body, err := c.doRequest(ctx, http.MethodGet, "/data/conference")
+	conferences, err := c.fetchConferences(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch conferences: %w", err)
+		return nil, err
 	}
+	c.catalog.Store(conferences)
+	return conferences, nil
+}
+
+// fetchConferences retrieves all available conferences from the moresleep API
+func (c *Client) fetchConferences(ctx context.Context) ([]domain.Conference, error) {
+	c.logger.InfoContext(ctx, "Fetching conferences from moresleep API")
 
-	var response ConferencesAPIResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		// Try to parse as direct array for backward compatibility
-		var conferences []ConferenceResponse
-		if err := json.Unmarshal(body, &conferences); err != nil {
+	var responses []ConferenceResponse
+	err := c.doRequest(ctx, http.MethodGet, "/data/conference", func(body io.Reader) error {
+		// The list is either wrapped as {"conferences": [...]} or a bare array
+		_, err := decodeList(body, "conferences", func(conference ConferenceResponse) error {
+			responses = append(responses, conference)
+			return nil
+		})
+		if err != nil {
 			c.logger.ErrorContext(ctx, "Failed to unmarshal conferences response",
 				"error", err,
-				"body", string(body),
 			)
-			return nil, fmt.Errorf("failed to unmarshal conferences: %w", err)
+			return fmt.Errorf("failed to unmarshal conferences: %w", err)
 		}
-		response.Conferences = conferences
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch conferences: %w", err)
 	}
 
-	conferences := MapConferences(response.Conferences)
+	conferences := MapConferences(responses)
 
 	c.logger.InfoContext(ctx, "Successfully fetched conferences",
 		"count", len(conferences),
@@ -134,61 +245,62 @@ func (c *Client) GetConferences(ctx context.Context) ([]domain.Conference, error
 
 // GetTalks retrieves all talks for a specific conference from the moresleep API
 func (c *Client) GetTalks(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+	var talks []domain.Talk
+	err := c.StreamTalks(ctx, conferenceID, func(talk domain.Talk) error {
+		talks = append(talks, talk)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if talks == nil {
+		talks = []domain.Talk{}
+	}
+	return talks, nil
+}
+
+// StreamTalks retrieves the talks of a conference from the moresleep API and passes
+// them to yield one at a time as the response is decoded, so neither the response
+// body nor the raw sessions are held in memory. An error from yield stops the stream.
+func (c *Client) StreamTalks(ctx context.Context, conferenceID string, yield func(domain.Talk) error) error {
 	c.logger.InfoContext(ctx, "Fetching talks from moresleep API",
 		"conferenceID", conferenceID,
 	)
 
-	path := fmt.Sprintf("/data/conference/%s/session", conferenceID)
-	body, err := c.doRequest(ctx, http.MethodGet, path)
+	// We need the conference slug and name for mapping
+	conferenceSlug, conferenceName, err := c.conferenceDetails(ctx, conferenceID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch talks for conference %s: %w", conferenceID, err)
+		return err
 	}
 
-	var response SessionsAPIResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		// Try to parse as direct array for backward compatibility
-		var sessions []SessionResponse
-		if err := json.Unmarshal(body, &sessions); err != nil {
+	var count int
+	path := fmt.Sprintf("/data/conference/%s/session", conferenceID)
+	err = c.doRequest(ctx, http.MethodGet, path, func(body io.Reader) error {
+		// The list is either wrapped as {"sessions": [...]} or a bare array
+		var err error
+		count, err = decodeList(body, "sessions", func(session SessionResponse) error {
+			return yield(MapTalk(session, conferenceSlug, conferenceName))
+		})
+		if err != nil {
 			c.logger.ErrorContext(ctx, "Failed to unmarshal sessions response",
 				"error", err,
 				"conferenceID", conferenceID,
-				"body", string(body),
+				"decoded", count,
 			)
-			return nil, fmt.Errorf("failed to unmarshal sessions: %w", err)
+			return fmt.Errorf("failed to unmarshal sessions: %w", err)
 		}
-		response.Sessions = sessions
-	}
-
-	// We need to get the conference slug and name for mapping
-	// First, fetch the conference to get its details
-	conferences, err := c.GetConferences(ctx)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch conferences to get details: %w", err)
+		return fmt.Errorf("failed to fetch talks for conference %s: %w", conferenceID, err)
 	}
 
-	var conferenceSlug, conferenceName string
-	for _, conf := range conferences {
-		if conf.ID == conferenceID {
-			conferenceSlug = conf.Slug
-			conferenceName = conf.Name
-			break
-		}
-	}
-
-	if conferenceSlug == "" {
-		c.logger.WarnContext(ctx, "Conference not found, using empty strings",
-			"conferenceID", conferenceID,
-		)
-	}
-
-	talks := MapTalks(response.Sessions, conferenceSlug, conferenceName)
-
 	c.logger.InfoContext(ctx, "Successfully fetched talks",
 		"conferenceID", conferenceID,
-		"count", len(talks),
+		"count", count,
 	)
 
-	return talks, nil
+	return nil
 }
 
 // GetTalk retrieves a single talk by its ID from the moresleep API
@@ -197,41 +309,26 @@ func (c *Client) GetTalk(ctx context.Context, talkID string) (*domain.Talk, erro
 		"talkID", talkID,
 	)
 
+	var session SessionResponse
 	path := fmt.Sprintf("/data/session/%s", talkID)
-	body, err := c.doRequest(ctx, http.MethodGet, path)
+	err := c.doRequest(ctx, http.MethodGet, path, func(body io.Reader) error {
+		if err := json.NewDecoder(body).Decode(&session); err != nil {
+			c.logger.ErrorContext(ctx, "Failed to unmarshal session response",
+				"error", err,
+				"talkID", talkID,
+			)
+			return fmt.Errorf("failed to unmarshal session: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch talk %s: %w", talkID, err)
 	}
 
-	var session SessionResponse
-	if err := json.Unmarshal(body, &session); err != nil {
-		c.logger.ErrorContext(ctx, "Failed to unmarshal session response",
-			"error", err,
-			"talkID", talkID,
-			"body", string(body),
-		)
-		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
-	}
-
-	// We need to get the conference slug and name for mapping
-	conferences, err := c.GetConferences(ctx)
+	// We need the conference slug and name for mapping
+	conferenceSlug, conferenceName, err := c.conferenceDetails(ctx, session.ConferenceID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch conferences to get details: %w", err)
-	}
-
-	var conferenceSlug, conferenceName string
-	for _, conf := range conferences {
-		if conf.ID == session.ConferenceID {
-			conferenceSlug = conf.Slug
-			conferenceName = conf.Name
-			break
-		}
-	}
-
-	if conferenceSlug == "" {
-		c.logger.WarnContext(ctx, "Conference not found, using empty strings",
-			"conferenceID", session.ConferenceID,
-		)
+		return nil, err
 	}
 
 	talk := MapTalk(session, conferenceSlug, conferenceName)
@@ -243,3 +340,19 @@ func (c *Client) GetTalk(ctx context.Context, talkID string) (*domain.Talk, erro
 
 	return &talk, nil
 }
+
+// conferenceDetails returns the slug and name of a conference from the catalog,
+// or empty strings if the conference does not exist
+func (c *Client) conferenceDetails(ctx context.Context, conferenceID string) (string, string, error) {
+	conference, ok, err := c.catalog.Conference(ctx, conferenceID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch conferences to get details: %w", err)
+	}
+
+	if !ok {
+		c.logger.WarnContext(ctx, "Conference not found, using empty strings",
+			"conferenceID", conferenceID,
+		)
+	}
+	return conference.Slug, conference.Name, nil
+}
@@ -3,6 +3,7 @@ package moresleep
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -105,6 +106,7 @@ func TestClient_GetConferences(t *testing.T) {
 		defer server.Close()
 
 		client := New(server.URL, "", "")
+		client.SetRetryOptions(RetryOptions{})
 		conferences, err := client.GetConferences(context.Background())
 
 		require.Error(t, err)
@@ -112,6 +114,20 @@ func TestClient_GetConferences(t *testing.T) {
 		assert.Contains(t, err.Error(), "unexpected status code: 500")
 	})
 
+	t.Run("rejected credentials", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		client := New(server.URL, "testuser", "wrong")
+		conferences, err := client.GetConferences(context.Background())
+
+		require.Error(t, err)
+		assert.Nil(t, conferences)
+		assert.True(t, errors.Is(err, domain.ErrUnauthorized))
+	})
+
 	t.Run("invalid json response", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
@@ -328,6 +344,7 @@ func TestClient_GetTalks(t *testing.T) {
 		defer server.Close()
 
 		client := New(server.URL, "", "")
+		client.SetRetryOptions(RetryOptions{})
 		talks, err := client.GetTalks(context.Background(), "conf-1")
 
 		require.Error(t, err)
@@ -361,6 +378,84 @@ func TestClient_GetTalks(t *testing.T) {
 	})
 }
 
+func TestClient_GetTalk(t *testing.T) {
+	t.Run("talk not found", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/data/session/missing", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"session not found"}`))
+		}))
+		defer server.Close()
+
+		client := New(server.URL, "", "")
+		talk, err := client.GetTalk(context.Background(), "missing")
+
+		require.Error(t, err)
+		assert.Nil(t, talk)
+		assert.True(t, errors.Is(err, domain.ErrNotFound))
+	})
+
+	t.Run("server error is not reported as not found", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		client := New(server.URL, "", "")
+		client.SetRetryOptions(RetryOptions{})
+		talk, err := client.GetTalk(context.Background(), "talk-1")
+
+		require.Error(t, err)
+		assert.Nil(t, talk)
+		assert.False(t, errors.Is(err, domain.ErrNotFound))
+	})
+}
+
+func TestClient_ConferenceCatalog(t *testing.T) {
+	var conferenceCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/data/conference":
+			conferenceCalls++
+			json.NewEncoder(w).Encode(ConferencesAPIResponse{
+				Conferences: []ConferenceResponse{
+					{ID: "conf-1", Name: "JavaZone 2023", Slug: "javazone2023"},
+					{ID: "conf-2", Name: "JavaZone 2024", Slug: "javazone2024"},
+				},
+			})
+		case "/data/session/talk-1":
+			json.NewEncoder(w).Encode(SessionResponse{ID: "talk-1", ConferenceID: "conf-2", Status: "APPROVED"})
+		default:
+			json.NewEncoder(w).Encode(SessionsAPIResponse{Sessions: []SessionResponse{{ID: "talk-1", Status: "APPROVED"}}})
+		}
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "", "")
+	ctx := context.Background()
+
+	conferences, err := client.GetConferences(ctx)
+	require.NoError(t, err)
+	for _, conf := range conferences {
+		talks, err := client.GetTalks(ctx, conf.ID)
+		require.NoError(t, err)
+		require.Len(t, talks, 1)
+		assert.Equal(t, conf.Slug, talks[0].ConferenceSlug)
+	}
+
+	talk, err := client.GetTalk(ctx, "talk-1")
+	require.NoError(t, err)
+	assert.Equal(t, "javazone2024", talk.ConferenceSlug)
+	assert.Equal(t, "JavaZone 2024", talk.ConferenceName)
+
+	assert.Equal(t, 1, conferenceCalls, "talk lookups should use the catalog filled by GetConferences")
+
+	_, err = client.GetConferences(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, conferenceCalls, "GetConferences should always fetch a fresh list")
+}
+
 func TestClient_NewWithHTTPClient(t *testing.T) {
 	customClient := &http.Client{
 		Timeout: 5 * time.Second,
@@ -0,0 +1,182 @@
+package moresleep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+const (
+	// conferencesFile holds the conference list in a FileSource directory
+	conferencesFile = "conferences.json"
+
+	// sessionsDir holds one <conferenceID>.json file of sessions per conference
+	sessionsDir = "sessions"
+)
+
+// FileSource implements the TalkSource interface by reading a frozen dataset in the
+// moresleep wire format from a directory:
+//
+//	conferences.json              ConferencesAPIResponse, or a bare array of conferences
+//	sessions/<conferenceID>.json  SessionsAPIResponse, or a bare array of sessions
+//
+// Files are read on every call, so the dataset can be edited without a restart.
+// A listed conference without a sessions file has no talks.
+type FileSource struct {
+	dir    string
+	logger *slog.Logger
+}
+
+// NewFileSource creates a FileSource reading from dir. It returns an error if dir is
+// not a directory.
+func NewFileSource(dir string) (*FileSource, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open talk source directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("talk source %s is not a directory", dir)
+	}
+
+	return &FileSource{
+		dir:    dir,
+		logger: slog.Default(),
+	}, nil
+}
+
+// SetLogger sets a custom logger for the source
+func (s *FileSource) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// Invalidate does nothing, since conferences are read from disk on every call.
+// It lets the source stand in for a ConferenceCatalog.
+func (s *FileSource) Invalidate() {}
+
+// GetConferences reads all conferences from conferences.json
+func (s *FileSource) GetConferences(ctx context.Context) ([]domain.Conference, error) {
+	var responses []ConferenceResponse
+	err := decodeFile(filepath.Join(s.dir, conferencesFile), "conferences", func(conference ConferenceResponse) error {
+		responses = append(responses, conference)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conferences: %w", err)
+	}
+
+	conferences := MapConferences(responses)
+
+	s.logger.DebugContext(ctx, "Read conferences from file",
+		"dir", s.dir,
+		"count", len(conferences),
+	)
+
+	return conferences, nil
+}
+
+// GetTalks reads the talks of a conference from its sessions file.
+// It returns domain.ErrNotFound if the conference is not listed.
+func (s *FileSource) GetTalks(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+	conference, err := s.conference(ctx, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	talks := []domain.Talk{}
+	err = s.decodeSessions(conferenceID, func(session SessionResponse) error {
+		talks = append(talks, MapTalk(session, conference.Slug, conference.Name))
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		s.logger.WarnContext(ctx, "No sessions file for conference, treating it as empty",
+			"conferenceID", conferenceID,
+		)
+		return talks, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read talks for conference %s: %w", conferenceID, err)
+	}
+
+	s.logger.DebugContext(ctx, "Read talks from file",
+		"conferenceID", conferenceID,
+		"count", len(talks),
+	)
+
+	return talks, nil
+}
+
+// GetTalk finds a talk by its ID in the sessions files of the listed conferences.
+// It returns domain.ErrNotFound if no conference has the talk.
+func (s *FileSource) GetTalk(ctx context.Context, talkID string) (*domain.Talk, error) {
+	conferences, err := s.GetConferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stops decoding once the talk is found
+	errFound := errors.New("found")
+
+	for _, conference := range conferences {
+		var talk domain.Talk
+		err := s.decodeSessions(conference.ID, func(session SessionResponse) error {
+			if session.ID != talkID {
+				return nil
+			}
+			talk = MapTalk(session, conference.Slug, conference.Name)
+			return errFound
+		})
+		switch {
+		case errors.Is(err, errFound):
+			return &talk, nil
+		case errors.Is(err, fs.ErrNotExist):
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("failed to read talks for conference %s: %w", conference.ID, err)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: talk %s is not in %s", domain.ErrNotFound, talkID, s.dir)
+}
+
+// conference looks up a listed conference by ID
+func (s *FileSource) conference(ctx context.Context, conferenceID string) (domain.Conference, error) {
+	conferences, err := s.GetConferences(ctx)
+	if err != nil {
+		return domain.Conference{}, err
+	}
+	for _, conference := range conferences {
+		if conference.ID == conferenceID {
+			return conference, nil
+		}
+	}
+	return domain.Conference{}, fmt.Errorf("%w: conference %s is not in %s", domain.ErrNotFound, conferenceID, conferencesFile)
+}
+
+// decodeSessions passes the sessions of a conference to yield. The conference ID is
+// only used as a file name within the sessions directory.
+func (s *FileSource) decodeSessions(conferenceID string, yield func(SessionResponse) error) error {
+	if !filepath.IsLocal(conferenceID) || filepath.Base(conferenceID) != conferenceID {
+		return fmt.Errorf("%w: invalid conference ID %q", domain.ErrNotFound, conferenceID)
+	}
+	return decodeFile(filepath.Join(s.dir, sessionsDir, conferenceID+".json"), "sessions", yield)
+}
+
+// decodeFile decodes a list in the moresleep wire format from a file, one element at a time
+func decodeFile[T any](path string, key string, yield func(T) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := decodeList(file, key, yield); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package moresleep
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFileSource(t *testing.T) *FileSource {
+	t.Helper()
+	source, err := NewFileSource(filepath.Join("testdata", "dataset"))
+	require.NoError(t, err)
+	return source
+}
+
+func TestNewFileSource(t *testing.T) {
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := NewFileSource(filepath.Join(t.TempDir(), "missing"))
+		require.Error(t, err)
+	})
+
+	t.Run("not a directory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "conferences.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[]`), 0o644))
+
+		_, err := NewFileSource(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a directory")
+	})
+}
+
+func TestFileSource_GetConferences(t *testing.T) {
+	conferences, err := testFileSource(t).GetConferences(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Conference{
+		{ID: "conf-2024", Name: "JavaZone 2024", Slug: "javazone2024"},
+		{ID: "conf-2025", Name: "JavaZone 2025", Slug: "javazone2025"},
+		{ID: "conf-2026", Name: "JavaZone 2026", Slug: "javazone2026"},
+	}, conferences)
+}
+
+func TestFileSource_GetConferencesMissingFile(t *testing.T) {
+	source, err := NewFileSource(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = source.GetConferences(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFileSource_GetTalks(t *testing.T) {
+	source := testFileSource(t)
+
+	tests := []struct {
+		name         string
+		conferenceID string
+		wantIDs      []string
+		wantSlug     string
+	}{
+		{name: "wrapped sessions", conferenceID: "conf-2024", wantIDs: []string{"talk-1", "talk-2"}, wantSlug: "javazone2024"},
+		{name: "bare array", conferenceID: "conf-2025", wantIDs: []string{"talk-3"}, wantSlug: "javazone2025"},
+		{name: "no sessions file", conferenceID: "conf-2026", wantIDs: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			talks, err := source.GetTalks(context.Background(), tt.conferenceID)
+			require.NoError(t, err)
+			require.NotNil(t, talks)
+
+			var ids []string
+			for _, talk := range talks {
+				ids = append(ids, talk.ID)
+				assert.Equal(t, tt.conferenceID, talk.ConferenceID)
+				assert.Equal(t, tt.wantSlug, talk.ConferenceSlug)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestFileSource_GetTalksMapsSessions(t *testing.T) {
+	talks, err := testFileSource(t).GetTalks(context.Background(), "conf-2024")
+	require.NoError(t, err)
+	require.Len(t, talks, 2)
+
+	talk := talks[0]
+	assert.Equal(t, "JavaZone 2024", talk.ConferenceName)
+	assert.Equal(t, "APPROVED", talk.Status)
+	assert.Equal(t, "Introduction to Go", talk.Data["title"])
+	require.Len(t, talk.Speakers, 1)
+	assert.Equal(t, "Jane Doe", talk.Speakers[0].Name)
+	require.NotNil(t, talk.LastUpdated)
+	assert.Equal(t, 2024, talk.LastUpdated.Year())
+}
+
+func TestFileSource_GetTalksUnknownConference(t *testing.T) {
+	source := testFileSource(t)
+
+	for _, id := range []string{"conf-1999", "../conf-2024", ""} {
+		_, err := source.GetTalks(context.Background(), id)
+		assert.ErrorIs(t, err, domain.ErrNotFound, id)
+	}
+}
+
+func TestFileSource_GetTalksInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, conferencesFile), []byte(`[{"id":"conf-1","slug":"conf"}]`), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, sessionsDir), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, sessionsDir, "conf-1.json"), []byte(`{"sessions":[{"id":`), 0o644))
+
+	source, err := NewFileSource(dir)
+	require.NoError(t, err)
+
+	_, err = source.GetTalks(context.Background(), "conf-1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conf-1.json")
+	assert.NotErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestFileSource_GetTalk(t *testing.T) {
+	source := testFileSource(t)
+
+	talk, err := source.GetTalk(context.Background(), "talk-3")
+	require.NoError(t, err)
+	assert.Equal(t, "talk-3", talk.ID)
+	assert.Equal(t, "javazone2025", talk.ConferenceSlug)
+	assert.Equal(t, "Virtual Threads in Practice", talk.Data["title"])
+
+	_, err = source.GetTalk(context.Background(), "talk-404")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestFileSource_ReadsChangesWithoutRestart(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, conferencesFile)
+	require.NoError(t, os.WriteFile(path, []byte(`[{"id":"conf-1","slug":"one"}]`), 0o644))
+
+	source, err := NewFileSource(dir)
+	require.NoError(t, err)
+
+	conferences, err := source.GetConferences(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, conferences, 1)
+
+	require.NoError(t, os.WriteFile(path, []byte(`[{"id":"conf-1","slug":"one"},{"id":"conf-2","slug":"two"}]`), 0o644))
+	source.Invalidate()
+
+	conferences, err = source.GetConferences(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, conferences, 2)
+}
@@ -5,7 +5,8 @@ import (
 )
 
 // isEmptyValue checks if a value is nil or an empty string.
-// Empty values cause issues with Elasticsearch dynamic type mapping.
+// Empty values carry no information, so they are left out of the indexed documents.
+// Unknown fields are typed by the index's dynamic templates rather than by their first value.
 func isEmptyValue(v interface{}) bool {
 	if v == nil {
 		return true
@@ -45,7 +46,7 @@ func MapSpeaker(sr SpeakerResponse) domain.Speaker {
 	}
 
 	// Extract all data fields, separating public and private
-	// Skip empty values to avoid Elasticsearch dynamic mapping issues
+	// Skip empty values
 	for key, dv := range sr.Data {
 		if isEmptyValue(dv.Value) {
 			continue
@@ -93,7 +94,7 @@ func MapTalk(sr SessionResponse, conferenceSlug, conferenceName string) domain.T
 	}
 
 	// Extract all data fields, separating public and private
-	// Skip empty values to avoid Elasticsearch dynamic mapping issues
+	// Skip empty values
 	for key, dv := range sr.Data {
 		if isEmptyValue(dv.Value) {
 			continue
@@ -0,0 +1,104 @@
+package moresleep
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryOptions controls how failed GET requests to moresleep are retried
+type RetryOptions struct {
+	// MaxRetries is how many times a request is retried after the first attempt
+	MaxRetries int
+
+	// Backoff is the delay before the first retry; it doubles on every further retry
+	Backoff time.Duration
+
+	// MaxBackoff caps the delay between attempts, including delays asked for with Retry-After
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryOptions returns the retry settings used unless SetRetryOptions is called
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{
+		MaxRetries: 3,
+		Backoff:    500 * time.Millisecond,
+		MaxBackoff: 10 * time.Second,
+	}
+}
+
+// statusError is a response from moresleep with a status other than 200 OK
+type statusError struct {
+	status     int
+	retryAfter time.Duration
+	err        error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+// classifyAttempt decides whether a failed attempt may be retried and how it counts
+// for the circuit breaker. Responses other than server errors show moresleep is up.
+// Errors without a response are connection failures unless the caller gave up.
+func classifyAttempt(err error, cancelled bool) (retryable bool, outcome attemptOutcome) {
+	if err == nil {
+		return false, outcomeSuccess
+	}
+	if cancelled {
+		return false, outcomeIgnored
+	}
+
+	var statusErr *statusError
+	if !errors.As(err, &statusErr) {
+		return true, outcomeFailure
+	}
+	switch {
+	case statusErr.status == http.StatusTooManyRequests:
+		return true, outcomeIgnored
+	case statusErr.status >= 500:
+		return true, outcomeFailure
+	default:
+		return false, outcomeSuccess
+	}
+}
+
+// retryDelay returns the delay before retry number attempt+1: exponential backoff
+// with equal jitter, or the Retry-After delay if moresleep asked for a longer one,
+// capped at MaxBackoff
+func (c *Client) retryDelay(attempt int, err error) time.Duration {
+	delay := c.retry.Backoff << attempt
+	if delay <= 0 || delay > c.retry.MaxBackoff {
+		delay = c.retry.MaxBackoff
+	}
+	if delay > 1 {
+		delay = delay/2 + rand.N(delay/2)
+	}
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) && statusErr.retryAfter > delay {
+		delay = statusErr.retryAfter
+	}
+	return min(delay, c.retry.MaxBackoff)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+// It returns zero if the header is missing or invalid.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
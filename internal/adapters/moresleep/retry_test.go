@@ -0,0 +1,146 @@
+package moresleep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastRetries retries quickly so tests do not wait for real backoff
+var fastRetries = RetryOptions{MaxRetries: 3, Backoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+// flakyServer answers with the given statuses in order and with an empty conference list afterwards
+func flakyServer(calls *atomic.Int32, statuses ...int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(calls.Add(1))
+		if call <= len(statuses) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(statuses[call-1])
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ConferencesAPIResponse{Conferences: []ConferenceResponse{{ID: "conf-1"}}})
+	}))
+}
+
+func TestClient_Retries(t *testing.T) {
+	t.Run("retries server errors and throttling", func(t *testing.T) {
+		var calls atomic.Int32
+		server := flakyServer(&calls, http.StatusBadGateway, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+		defer server.Close()
+
+		client := New(server.URL, "", "")
+		client.SetRetryOptions(fastRetries)
+		conferences, err := client.GetConferences(context.Background())
+
+		require.NoError(t, err)
+		assert.Len(t, conferences, 1)
+		assert.Equal(t, int32(4), calls.Load())
+		assert.Equal(t, domain.BreakerClosed, client.BreakerStatus().State)
+		assert.Zero(t, client.BreakerStatus().ConsecutiveFailures, "a success resets the failure count")
+	})
+
+	t.Run("gives up after the last retry", func(t *testing.T) {
+		var calls atomic.Int32
+		server := flakyServer(&calls, 500, 500, 500, 500, 500)
+		defer server.Close()
+
+		client := New(server.URL, "", "")
+		client.SetRetryOptions(fastRetries)
+		_, err := client.GetConferences(context.Background())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unexpected status code: 500")
+		assert.Equal(t, int32(4), calls.Load())
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		for _, status := range []int{http.StatusNotFound, http.StatusUnauthorized, http.StatusBadRequest} {
+			var calls atomic.Int32
+			server := flakyServer(&calls, status)
+
+			client := New(server.URL, "", "")
+			client.SetRetryOptions(fastRetries)
+			_, err := client.GetTalk(context.Background(), "talk-1")
+			server.Close()
+
+			require.Error(t, err)
+			assert.Equal(t, int32(1), calls.Load(), "status %d", status)
+			assert.Zero(t, client.BreakerStatus().ConsecutiveFailures, "status %d shows moresleep is up", status)
+		}
+	})
+
+	t.Run("retries connection errors", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		client := New(server.URL, "", "")
+		client.SetRetryOptions(fastRetries)
+		_, err := client.GetConferences(context.Background())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to execute request")
+		assert.Equal(t, 4, client.BreakerStatus().ConsecutiveFailures)
+	})
+
+	t.Run("stops retrying when the context is cancelled", func(t *testing.T) {
+		var calls atomic.Int32
+		server := flakyServer(&calls, 500, 500, 500, 500)
+		defer server.Close()
+
+		client := New(server.URL, "", "")
+		client.SetRetryOptions(RetryOptions{MaxRetries: 3, Backoff: time.Hour, MaxBackoff: time.Hour})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := client.GetConferences(ctx)
+
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestClient_RetryDelay(t *testing.T) {
+	client := New("http://moresleep", "", "")
+	client.SetRetryOptions(RetryOptions{MaxRetries: 5, Backoff: 100 * time.Millisecond, MaxBackoff: time.Second})
+
+	t.Run("doubles with jitter", func(t *testing.T) {
+		for attempt, base := range []time.Duration{100, 200, 400, 800} {
+			delay := client.retryDelay(attempt, errors.New("connection refused"))
+			assert.GreaterOrEqual(t, delay, base*time.Millisecond/2)
+			assert.Less(t, delay, base*time.Millisecond)
+		}
+	})
+
+	t.Run("caps at max backoff", func(t *testing.T) {
+		delay := client.retryDelay(10, errors.New("connection refused"))
+		assert.LessOrEqual(t, delay, time.Second)
+	})
+
+	t.Run("honours a longer Retry-After up to max backoff", func(t *testing.T) {
+		err := &statusError{status: http.StatusTooManyRequests, retryAfter: 700 * time.Millisecond, err: errors.New("throttled")}
+		assert.Equal(t, 700*time.Millisecond, client.retryDelay(0, err))
+
+		err.retryAfter = time.Minute
+		assert.Equal(t, time.Second, client.retryDelay(0, err))
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 9, 4, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3", now))
+	assert.Equal(t, 90*time.Second, parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+	assert.Zero(t, parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+	assert.Zero(t, parseRetryAfter("", now))
+	assert.Zero(t, parseRetryAfter("soon", now))
+}
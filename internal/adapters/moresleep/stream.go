@@ -0,0 +1,109 @@
+package moresleep
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// maxLoggedBody is the largest part of an error response that is logged and included in errors
+const maxLoggedBody = 512
+
+// decodeList decodes a JSON list one element at a time and passes each element to
+// yield, so the list is never held in memory as a whole. The list is either a bare
+// array or the array under key in an object such as {"sessions": [...]}; other keys
+// of the object are skipped. An object without the key is an empty list.
+// It returns the number of elements decoded.
+func decodeList[T any](r io.Reader, key string, yield func(T) error) (int, error) {
+	decoder := json.NewDecoder(r)
+
+	token, err := decoder.Token()
+	if err != nil {
+		return 0, err
+	}
+
+	switch token {
+	case json.Delim('['):
+		return decodeArray(decoder, yield)
+	case json.Delim('{'):
+	default:
+		return 0, fmt.Errorf("expected an array or an object at offset %d, got %v", decoder.InputOffset(), token)
+	}
+
+	count := 0
+	for decoder.More() {
+		nameToken, err := decoder.Token()
+		if err != nil {
+			return count, err
+		}
+
+		if name, _ := nameToken.(string); name != key {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return count, err
+			}
+			continue
+		}
+
+		token, err := decoder.Token()
+		if err != nil {
+			return count, err
+		}
+		if token == nil {
+			continue
+		}
+		if token != json.Delim('[') {
+			return count, fmt.Errorf("expected an array for %q at offset %d, got %v", key, decoder.InputOffset(), token)
+		}
+
+		n, err := decodeArray(decoder, yield)
+		count += n
+		if err != nil {
+			return count, err
+		}
+	}
+
+	// Consume the closing brace so truncated bodies are reported
+	if _, err := decoder.Token(); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// decodeArray decodes the elements of an array whose opening bracket has been read,
+// including the closing bracket
+func decodeArray[T any](decoder *json.Decoder, yield func(T) error) (int, error) {
+	count := 0
+	for decoder.More() {
+		var element T
+		if err := decoder.Decode(&element); err != nil {
+			return count, fmt.Errorf("element %d: %w", count, err)
+		}
+		if err := yield(element); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// readSnippet reads at most maxLoggedBody bytes of a response body for logging,
+// marking the snippet if the body was longer
+func readSnippet(r io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(r, maxLoggedBody+1))
+	if len(data) <= maxLoggedBody {
+		return string(data)
+	}
+
+	// Do not cut a multi-byte character in half
+	data = data[:maxLoggedBody]
+	for i := 0; i < utf8.UTFMax-1 && !utf8.Valid(data); i++ {
+		data = data[:len(data)-1]
+	}
+	return string(data) + "... (truncated)"
+}
@@ -0,0 +1,157 @@
+package moresleep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type listItem struct {
+	ID string `json:"id"`
+}
+
+func TestDecodeList(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []string
+		wantErr string
+	}{
+		{name: "bare array", body: `[{"id":"a"},{"id":"b"}]`, want: []string{"a", "b"}},
+		{name: "wrapped", body: `{"sessions":[{"id":"a"},{"id":"b"}]}`, want: []string{"a", "b"}},
+		{name: "other keys are skipped", body: `{"total":2,"meta":{"x":[1,2]},"sessions":[{"id":"a"}],"next":null}`, want: []string{"a"}},
+		{name: "missing key", body: `{"total":0}`},
+		{name: "null list", body: `{"sessions":null}`},
+		{name: "empty array", body: `[]`},
+		{name: "not a list", body: `"sessions"`, wantErr: "expected an array or an object"},
+		{name: "key is not an array", body: `{"sessions":{"id":"a"}}`, wantErr: `expected an array for "sessions"`},
+		{name: "invalid element", body: `[{"id":"a"},{"id":1}]`, want: []string{"a"}, wantErr: "element 1"},
+		{name: "truncated", body: `{"sessions":[{"id":"a"},{"id":`, want: []string{"a"}, wantErr: "unexpected EOF"},
+		{name: "invalid json", body: `invalid json`, wantErr: "invalid character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			count, err := decodeList(strings.NewReader(tt.body), "sessions", func(item listItem) error {
+				got = append(got, item.ID)
+				return nil
+			})
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, len(tt.want), count)
+		})
+	}
+
+	t.Run("yield error stops decoding", func(t *testing.T) {
+		stop := errors.New("stop")
+		var got []string
+		_, err := decodeList(strings.NewReader(`[{"id":"a"},{"id":"b"},{"id":"c"}]`), "sessions", func(item listItem) error {
+			got = append(got, item.ID)
+			if item.ID == "b" {
+				return stop
+			}
+			return nil
+		})
+
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, []string{"a", "b"}, got)
+	})
+}
+
+func TestReadSnippet(t *testing.T) {
+	assert.Equal(t, "short body", readSnippet(strings.NewReader("short body")))
+
+	exact := strings.Repeat("x", maxLoggedBody)
+	assert.Equal(t, exact, readSnippet(strings.NewReader(exact)))
+
+	long := readSnippet(strings.NewReader(strings.Repeat("x", 10*maxLoggedBody)))
+	assert.Equal(t, strings.Repeat("x", maxLoggedBody)+"... (truncated)", long)
+
+	// A multi-byte character on the boundary is dropped rather than cut
+	multiByte := readSnippet(strings.NewReader(strings.Repeat("x", maxLoggedBody-1) + "ø" + "tail"))
+	assert.Equal(t, strings.Repeat("x", maxLoggedBody-1)+"... (truncated)", multiByte)
+}
+
+func TestClient_StreamTalks(t *testing.T) {
+	const sessionCount = 2000
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/data/conference" {
+			w.Write([]byte(`{"conferences":[{"id":"conf-1","name":"JavaZone 2024","slug":"javazone2024"}]}`))
+			return
+		}
+
+		// Write the sessions one by one, as a large response arrives in chunks
+		w.Write([]byte(`{"sessions":[`))
+		for i := range sessionCount {
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `{"id":"talk-%d","conferenceId":"conf-1","status":"APPROVED","data":{"title":{"value":"Talk %d"}}}`, i, i)
+		}
+		w.Write([]byte(`]}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "", "")
+
+	t.Run("yields every talk mapped", func(t *testing.T) {
+		var talks []domain.Talk
+		err := client.StreamTalks(context.Background(), "conf-1", func(talk domain.Talk) error {
+			talks = append(talks, talk)
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.Len(t, talks, sessionCount)
+		assert.Equal(t, "talk-1999", talks[sessionCount-1].ID)
+		assert.Equal(t, "Talk 1999", talks[sessionCount-1].Data["title"])
+		assert.Equal(t, "javazone2024", talks[0].ConferenceSlug)
+	})
+
+	t.Run("stops when yield fails", func(t *testing.T) {
+		stop := errors.New("stop")
+		var yielded int
+		err := client.StreamTalks(context.Background(), "conf-1", func(talk domain.Talk) error {
+			yielded++
+			if yielded == 10 {
+				return stop
+			}
+			return nil
+		})
+
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 10, yielded)
+	})
+}
+
+func TestClient_ErrorBodyIsTruncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(strings.Repeat("e", 100*maxLoggedBody)))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "", "")
+	_, err := client.GetTalk(context.Background(), "talk-1")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "... (truncated)")
+	assert.Less(t, len(err.Error()), 2*maxLoggedBody)
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/javaBin/talks-indexer/internal/adapters/web/templates"
+)
+
+// HandleRefreshConferences drops the cached conference list, fetches it again and
+// renders the options of the conference select
+func (h *Handler) HandleRefreshConferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	slog.InfoContext(ctx, "web: refreshing conference list")
+
+	h.conferences.Invalidate()
+	conferences, err := h.conferences.GetConferences(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch conferences", "error", err)
+		http.Error(w, "Failed to load conferences", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	templates.ConferenceOptions(conferences).Render(ctx, w)
+}
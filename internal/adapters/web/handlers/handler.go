@@ -1,50 +1,33 @@
 package handlers
 
-import (
-	"context"
-	"sync"
-
-	"github.com/javaBin/talks-indexer/internal/domain"
-	"github.com/javaBin/talks-indexer/internal/ports"
-)
+import "github.com/javaBin/talks-indexer/internal/ports"
 
 // Handler handles web UI requests for the admin dashboard
 type Handler struct {
 	indexer     ports.Indexer
-	provider    ports.ConferenceProvider
-	conferences []domain.Conference
-	confMu      sync.RWMutex
+	jobs        ports.JobManager
+	conferences ports.ConferenceCatalog
+	scheduler   ports.Scheduler
+	searcher    ports.Searcher
 }
 
-// NewHandler creates a new web Handler with the provided dependencies
-func NewHandler(indexer ports.Indexer, provider ports.ConferenceProvider) *Handler {
+// NewHandler creates a new web Handler with the provided dependencies.
+// The conference list shown on the dashboard is read from the catalog, which
+// decides how long it is cached.
+func NewHandler(indexer ports.Indexer, jobs ports.JobManager, conferences ports.ConferenceCatalog) *Handler {
 	return &Handler{
-		indexer:  indexer,
-		provider: provider,
+		indexer:     indexer,
+		jobs:        jobs,
+		conferences: conferences,
 	}
 }
 
-// getConferences returns cached conferences, fetching them if not yet cached
-func (h *Handler) getConferences(ctx context.Context) ([]domain.Conference, error) {
-	h.confMu.RLock()
-	if h.conferences != nil {
-		defer h.confMu.RUnlock()
-		return h.conferences, nil
-	}
-	h.confMu.RUnlock()
-
-	h.confMu.Lock()
-	defer h.confMu.Unlock()
-
-	// Double-check after acquiring write lock
-	if h.conferences != nil {
-		return h.conferences, nil
-	}
+// SetScheduler sets the scheduler whose runs are shown on the dashboard
+func (h *Handler) SetScheduler(scheduler ports.Scheduler) {
+	h.scheduler = scheduler
+}
 
-	conferences, err := h.provider.GetConferences(ctx)
-	if err != nil {
-		return nil, err
-	}
-	h.conferences = conferences
-	return conferences, nil
+// SetSearcher sets the searcher used by the admin search endpoint
+func (h *Handler) SetSearcher(searcher ports.Searcher) {
+	h.searcher = searcher
 }
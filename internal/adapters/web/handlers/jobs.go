@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/javaBin/talks-indexer/internal/adapters/web/templates"
+)
+
+// HandleJobStatus renders the current status of a background job
+func (h *Handler) HandleJobStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := r.PathValue("id")
+
+	job, err := h.jobs.Get(id)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err != nil {
+		slog.ErrorContext(ctx, "web: failed to get job", "jobID", id, "error", err)
+		templates.ResultError("Failed to get job status: "+err.Error()).Render(ctx, w)
+		return
+	}
+
+	templates.JobStatus(*job).Render(ctx, w)
+}
+
+// HandleCancelJob cancels a background job and renders its status
+func (h *Handler) HandleCancelJob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := r.PathValue("id")
+
+	slog.InfoContext(ctx, "web: cancelling job", "jobID", id)
+
+	job, err := h.jobs.Cancel(id)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err != nil {
+		slog.ErrorContext(ctx, "web: failed to cancel job", "jobID", id, "error", err)
+		templates.ResultError("Failed to cancel job: "+err.Error()).Render(ctx, w)
+		return
+	}
+
+	templates.JobStatus(*job).Render(ctx, w)
+}
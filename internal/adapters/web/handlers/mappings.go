@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/javaBin/talks-indexer/internal/adapters/web/templates"
+)
+
+// HandleUnmappedFields renders the source fields that have no explicit mapping in the talk indexes
+func (h *Handler) HandleUnmappedFields(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	slog.InfoContext(ctx, "web: inspecting unmapped fields")
+
+	report, err := h.indexer.UnmappedFields(ctx)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err != nil {
+		slog.ErrorContext(ctx, "web: failed to inspect unmapped fields", "error", err)
+		templates.ResultError("Failed to inspect unmapped fields: "+err.Error()).Render(ctx, w)
+		return
+	}
+
+	templates.UnmappedFields(report).Render(ctx, w)
+}
@@ -5,27 +5,31 @@ import (
 	"net/http"
 
 	"github.com/javaBin/talks-indexer/internal/adapters/web/templates"
+	"github.com/javaBin/talks-indexer/internal/domain"
 )
 
-// HandleReindexAll triggers a full reindex of all conferences
+// HandleReindexAll queues a full reindex of all conferences as a background job
+// and renders its status, which keeps polling until the job finishes
 func (h *Handler) HandleReindexAll(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	slog.InfoContext(ctx, "web: starting full reindex")
+	opts := reindexOptions(r)
+	slog.InfoContext(ctx, "web: queueing full reindex", "dryRun", opts.DryRun, "force", opts.Force)
 
-	err := h.indexer.ReindexAll(ctx)
+	job, err := h.jobs.Submit(domain.JobRequest{Type: domain.JobReindexAll, DryRun: opts.DryRun, Force: opts.Force})
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err != nil {
-		slog.ErrorContext(ctx, "web: failed to reindex all", "error", err)
-		templates.ResultError("Failed to reindex: "+err.Error()).Render(ctx, w)
+		slog.ErrorContext(ctx, "web: failed to queue full reindex", "error", err)
+		templates.ResultError("Failed to start reindex: "+err.Error()).Render(ctx, w)
 		return
 	}
 
-	slog.InfoContext(ctx, "web: full reindex completed")
-	templates.ResultSuccess("Successfully reindexed all conferences").Render(ctx, w)
+	slog.InfoContext(ctx, "web: full reindex queued", "jobID", job.ID)
+	templates.JobStatus(*job).Render(ctx, w)
 }
 
-// HandleReindexConference triggers a reindex for a single conference
+// HandleReindexConference queues a reindex of a single conference as a background job
+// and renders its status
 func (h *Handler) HandleReindexConference(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -36,22 +40,23 @@ func (h *Handler) HandleReindexConference(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	slog.InfoContext(ctx, "web: starting conference reindex", "slug", slug)
+	opts := reindexOptions(r)
+	slog.InfoContext(ctx, "web: queueing conference reindex", "slug", slug, "dryRun", opts.DryRun, "force", opts.Force)
 
-	err := h.indexer.ReindexConference(ctx, slug)
+	job, err := h.jobs.Submit(domain.JobRequest{Type: domain.JobReindexConference, Target: slug, DryRun: opts.DryRun, Force: opts.Force})
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err != nil {
-		slog.ErrorContext(ctx, "web: failed to reindex conference", "slug", slug, "error", err)
-		templates.ResultError("Failed to reindex conference: "+err.Error()).Render(ctx, w)
+		slog.ErrorContext(ctx, "web: failed to queue conference reindex", "slug", slug, "error", err)
+		templates.ResultError("Failed to start conference reindex: "+err.Error()).Render(ctx, w)
 		return
 	}
 
-	slog.InfoContext(ctx, "web: conference reindex completed", "slug", slug)
-	templates.ResultSuccess("Successfully reindexed conference: "+slug).Render(ctx, w)
+	slog.InfoContext(ctx, "web: conference reindex queued", "slug", slug, "jobID", job.ID)
+	templates.JobStatus(*job).Render(ctx, w)
 }
 
-// HandleReindexTalk triggers a reindex for a single talk
+// HandleReindexTalk queues a reindex of a single talk as a background job and renders its status
 func (h *Handler) HandleReindexTalk(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -62,17 +67,27 @@ func (h *Handler) HandleReindexTalk(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	slog.InfoContext(ctx, "web: starting talk reindex", "talkID", talkID)
+	// Talk reindexes are not guarded against shrinking, so force does not apply
+	opts := reindexOptions(r)
+	slog.InfoContext(ctx, "web: queueing talk reindex", "talkID", talkID, "dryRun", opts.DryRun)
 
-	err := h.indexer.ReindexTalk(ctx, talkID)
+	job, err := h.jobs.Submit(domain.JobRequest{Type: domain.JobReindexTalk, Target: talkID, DryRun: opts.DryRun})
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err != nil {
-		slog.ErrorContext(ctx, "web: failed to reindex talk", "talkID", talkID, "error", err)
-		templates.ResultError("Failed to reindex talk: "+err.Error()).Render(ctx, w)
+		slog.ErrorContext(ctx, "web: failed to queue talk reindex", "talkID", talkID, "error", err)
+		templates.ResultError("Failed to start talk reindex: "+err.Error()).Render(ctx, w)
 		return
 	}
 
-	slog.InfoContext(ctx, "web: talk reindex completed", "talkID", talkID)
-	templates.ResultSuccess("Successfully reindexed talk: "+talkID).Render(ctx, w)
+	slog.InfoContext(ctx, "web: talk reindex queued", "talkID", talkID, "jobID", job.ID)
+	templates.JobStatus(*job).Render(ctx, w)
+}
+
+// reindexOptions reads the reindex options posted by the dashboard buttons
+func reindexOptions(r *http.Request) domain.ReindexOptions {
+	return domain.ReindexOptions{
+		DryRun: r.FormValue("dryRun") == "true",
+		Force:  r.FormValue("force") == "true",
+	}
 }
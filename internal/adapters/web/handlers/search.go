@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// errSearchNotConfigured is returned by the admin search endpoints when no searcher is set
+var errSearchNotConfigured = errors.New("search is not configured")
+
+// searchResponse is the JSON body of an admin search or facets request, in the same
+// shape as the public search API
+type searchResponse struct {
+	Status  string      `json:"status"`
+	Message string      `json:"message,omitempty"`
+	Result  interface{} `json:"result,omitempty"`
+}
+
+// HandleSearch searches the private index, which includes talks of every status
+// and their private data. It answers with JSON for program committee tooling.
+// See domain.ParseSearchQuery for the supported query parameters.
+func (h *Handler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	query, err := h.searchQuery(r)
+	if err != nil {
+		writeSearchResponse(w, r, nil, err)
+		return
+	}
+
+	result, err := h.searcher.SearchPrivate(r.Context(), query)
+	writeSearchResponse(w, r, result, err)
+}
+
+// HandleSearchFacets counts the talks in the private index matching a search per facet value
+func (h *Handler) HandleSearchFacets(w http.ResponseWriter, r *http.Request) {
+	query, err := h.searchQuery(r)
+	if err != nil {
+		writeSearchResponse(w, r, nil, err)
+		return
+	}
+
+	result, err := h.searcher.FacetsPrivate(r.Context(), query)
+	writeSearchResponse(w, r, result, err)
+}
+
+// searchQuery parses the search query of the request
+func (h *Handler) searchQuery(r *http.Request) (domain.SearchQuery, error) {
+	if h.searcher == nil {
+		return domain.SearchQuery{}, errSearchNotConfigured
+	}
+	return domain.ParseSearchQuery(r.URL.Query())
+}
+
+// writeSearchResponse writes the result of a search as JSON, or its error with
+// 400 Bad Request for invalid queries and 500 Internal Server Error otherwise
+func writeSearchResponse(w http.ResponseWriter, r *http.Request, result interface{}, err error) {
+	ctx := r.Context()
+
+	status := http.StatusOK
+	response := searchResponse{Status: "success", Result: result}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "web: failed to search private index", "path", r.URL.Path, "error", err)
+		status = http.StatusInternalServerError
+		if errors.Is(err, domain.ErrInvalidSearch) {
+			status = http.StatusBadRequest
+		}
+		response = searchResponse{Status: "error", Message: "search failed: " + err.Error()}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.ErrorContext(ctx, "web: failed to encode search response", "error", err)
+	}
+}
@@ -16,6 +16,20 @@ func RegisterRoutes(mux *http.ServeMux, h *handlers.Handler) {
 	mux.HandleFunc("POST /admin/reindex/all", h.HandleReindexAll)
 	mux.HandleFunc("POST /admin/reindex/conference", h.HandleReindexConference)
 	mux.HandleFunc("POST /admin/reindex/talk", h.HandleReindexTalk)
+
+	// htmx endpoints for background job status
+	mux.HandleFunc("GET /admin/jobs/{id}", h.HandleJobStatus)
+	mux.HandleFunc("POST /admin/jobs/{id}/cancel", h.HandleCancelJob)
+
+	// Search of the private index
+	mux.HandleFunc("GET /admin/search", h.HandleSearch)
+	mux.HandleFunc("GET /admin/search/facets", h.HandleSearchFacets)
+
+	// Source fields without an explicit index mapping
+	mux.HandleFunc("GET /admin/mappings/unmapped", h.HandleUnmappedFields)
+
+	// htmx endpoint to refetch the conference list
+	mux.HandleFunc("POST /admin/conferences/refresh", h.HandleRefreshConferences)
 }
 
 // RegisterProtectedRoutes registers admin routes protected by auth middleware
@@ -25,10 +39,22 @@ func RegisterProtectedRoutes(mux *http.ServeMux, h *handlers.Handler, authMiddle
 	protectedReindexAll := authMiddleware.RequireAuth(http.HandlerFunc(h.HandleReindexAll))
 	protectedReindexConf := authMiddleware.RequireAuth(http.HandlerFunc(h.HandleReindexConference))
 	protectedReindexTalk := authMiddleware.RequireAuth(http.HandlerFunc(h.HandleReindexTalk))
+	protectedJobStatus := authMiddleware.RequireAuth(http.HandlerFunc(h.HandleJobStatus))
+	protectedCancelJob := authMiddleware.RequireAuth(http.HandlerFunc(h.HandleCancelJob))
+	protectedSearch := authMiddleware.RequireAuth(http.HandlerFunc(h.HandleSearch))
+	protectedFacets := authMiddleware.RequireAuth(http.HandlerFunc(h.HandleSearchFacets))
+	protectedUnmapped := authMiddleware.RequireAuth(http.HandlerFunc(h.HandleUnmappedFields))
+	protectedRefreshConfs := authMiddleware.RequireAuth(http.HandlerFunc(h.HandleRefreshConferences))
 
 	// Register protected routes
 	mux.Handle("GET /admin", protectedDashboard)
 	mux.Handle("POST /admin/reindex/all", protectedReindexAll)
 	mux.Handle("POST /admin/reindex/conference", protectedReindexConf)
 	mux.Handle("POST /admin/reindex/talk", protectedReindexTalk)
+	mux.Handle("GET /admin/jobs/{id}", protectedJobStatus)
+	mux.Handle("POST /admin/jobs/{id}/cancel", protectedCancelJob)
+	mux.Handle("GET /admin/search", protectedSearch)
+	mux.Handle("GET /admin/search/facets", protectedFacets)
+	mux.Handle("GET /admin/mappings/unmapped", protectedUnmapped)
+	mux.Handle("POST /admin/conferences/refresh", protectedRefreshConfs)
 }
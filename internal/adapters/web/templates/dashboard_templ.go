@@ -8,9 +8,14 @@ package templates
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-import "github.com/javaBin/talks-indexer/internal/domain"
+import (
+	"fmt"
+	"time"
 
-func Dashboard(conferences []domain.Conference) templ.Component {
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+func Dashboard(conferences []domain.Conference, schedules []domain.ScheduleStatus) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -43,43 +48,119 @@ func Dashboard(conferences []domain.Conference) templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"section\"><h2>Reindex All Conferences</h2><p>Reindex all talks from all conferences. This will recreate both indexes.</p><button hx-post=\"/admin/reindex/all\" hx-target=\"#result-all\" hx-indicator=\"#loading-all\" hx-disabled-elt=\"this\">Reindex All</button><div id=\"loading-all\" class=\"htmx-indicator\"><div class=\"result loading\">Reindexing all conferences...</div></div><div id=\"result-all\"></div></div><div class=\"section\"><h2>Reindex Single Conference</h2><p>Select a conference to reindex only its talks.</p><div class=\"form-group\"><select name=\"slug\" id=\"conference-select\"><option value=\"\">Select a conference...</option> ")
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
-			}
-			for _, conf := range conferences {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<option value=\"")
-				if templ_7745c5c3_Err != nil {
-					return templ_7745c5c3_Err
-				}
-				var templ_7745c5c3_Var3 string
-				templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(conf.Slug)
-				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/adapters/web/templates/dashboard.templ`, Line: 31, Col: 31}
-				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
-				if templ_7745c5c3_Err != nil {
-					return templ_7745c5c3_Err
-				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "\">")
+			if len(schedules) > 0 {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"section\"><h2>Scheduled Runs</h2><table class=\"report-table\"><thead><tr><th>Schedule</th><th>Expression</th><th>Last run</th><th>Next run</th><th>Skipped</th></tr></thead> <tbody>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var4 string
-				templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(conf.Name)
-				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/adapters/web/templates/dashboard.templ`, Line: 31, Col: 45}
-				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
-				if templ_7745c5c3_Err != nil {
-					return templ_7745c5c3_Err
+				for _, schedule := range schedules {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<tr><td>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var3 string
+					templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(schedule.Name)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/adapters/web/templates/dashboard.templ`, Line: 28, Col: 27}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</td><td><code>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var4 string
+					templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(schedule.Expression)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/adapters/web/templates/dashboard.templ`, Line: 29, Col: 39}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</code></td><td>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var5 string
+					templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(formatScheduleTime(schedule.LastRun))
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/adapters/web/templates/dashboard.templ`, Line: 31, Col: 47}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, " ")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					if schedule.LastError != "" {
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "<span class=\"schedule-error\">")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						var templ_7745c5c3_Var6 string
+						templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(schedule.LastError)
+						if templ_7745c5c3_Err != nil {
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/adapters/web/templates/dashboard.templ`, Line: 33, Col: 59}
+						}
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "</span>")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "</td><td>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var7 string
+					templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(formatScheduleTime(schedule.NextRun))
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/adapters/web/templates/dashboard.templ`, Line: 36, Col: 50}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "</td><td>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var8 string
+					templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprint(schedule.SkippedRuns))
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/adapters/web/templates/dashboard.templ`, Line: 37, Col: 46}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "</td></tr>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</option>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</tbody></table></div>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "</select> <button hx-post=\"/admin/reindex/conference\" hx-include=\"#conference-select\" hx-target=\"#result-conference\" hx-indicator=\"#loading-conference\" hx-disabled-elt=\"this\">Reindex Conference</button></div><div id=\"loading-conference\" class=\"htmx-indicator\"><div class=\"result loading\">Reindexing conference...</div></div><div id=\"result-conference\"></div></div><div class=\"section\"><h2>Reindex Single Talk</h2><p>Enter a talk ID to reindex that specific talk.</p><div class=\"form-group\"><input type=\"text\" name=\"talkId\" id=\"talk-id\" placeholder=\"Enter talk ID...\"> <button hx-post=\"/admin/reindex/talk\" hx-include=\"#talk-id\" hx-target=\"#result-talk\" hx-indicator=\"#loading-talk\" hx-disabled-elt=\"this\">Reindex Talk</button></div><div id=\"loading-talk\" class=\"htmx-indicator\"><div class=\"result loading\">Reindexing talk...</div></div><div id=\"result-talk\"></div></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, " <div class=\"section\"><h2>Reindex All Conferences</h2><p>Reindex all talks from all conferences. Fresh indexes are built and swapped in once indexing succeeds. The reindex runs as a background job and its progress is shown below. A dry run shows what would change without writing anything. A reindex that would drop more documents than the safety threshold allows is refused and can be repeated with force.</p><div class=\"form-group\"><button hx-post=\"/admin/reindex/all\" hx-target=\"#result-all\" hx-indicator=\"#loading-all\" hx-disabled-elt=\"this\">Reindex All</button> <button class=\"secondary\" hx-post=\"/admin/reindex/all\" hx-vals='{\"dryRun\": \"true\"}' hx-target=\"#result-all\" hx-indicator=\"#loading-all\" hx-disabled-elt=\"this\">Dry Run</button></div><div id=\"loading-all\" class=\"htmx-indicator\"><div class=\"result loading\">Starting reindex job...</div></div><div id=\"result-all\"></div></div><div class=\"section\"><h2>Reindex Single Conference</h2><p>Select a conference to reindex only its talks. The reindex runs as a background job like a full reindex.</p><div class=\"form-group\"><select name=\"slug\" id=\"conference-select\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = ConferenceOptions(conferences).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "</select> <button hx-post=\"/admin/reindex/conference\" hx-include=\"#conference-select\" hx-target=\"#result-conference\" hx-indicator=\"#loading-conference\" hx-disabled-elt=\"this\">Reindex Conference</button> <button class=\"secondary\" hx-post=\"/admin/reindex/conference\" hx-include=\"#conference-select\" hx-vals='{\"dryRun\": \"true\"}' hx-target=\"#result-conference\" hx-indicator=\"#loading-conference\" hx-disabled-elt=\"this\">Dry Run</button> <button class=\"secondary\" hx-post=\"/admin/conferences/refresh\" hx-target=\"#conference-select\" hx-disabled-elt=\"this\" title=\"Fetch the conference list from moresleep again\">Refresh List</button></div><div id=\"loading-conference\" class=\"htmx-indicator\"><div class=\"result loading\">Starting reindex job...</div></div><div id=\"result-conference\"></div></div><div class=\"section\"><h2>Reindex Single Talk</h2><p>Enter a talk ID to reindex that specific talk. The reindex runs as a background job.</p><div class=\"form-group\"><input type=\"text\" name=\"talkId\" id=\"talk-id\" placeholder=\"Enter talk ID...\"> <button hx-post=\"/admin/reindex/talk\" hx-include=\"#talk-id\" hx-target=\"#result-talk\" hx-indicator=\"#loading-talk\" hx-disabled-elt=\"this\">Reindex Talk</button> <button class=\"secondary\" hx-post=\"/admin/reindex/talk\" hx-include=\"#talk-id\" hx-vals='{\"dryRun\": \"true\"}' hx-target=\"#result-talk\" hx-indicator=\"#loading-talk\" hx-disabled-elt=\"this\">Dry Run</button></div><div id=\"loading-talk\" class=\"htmx-indicator\"><div class=\"result loading\">Starting reindex job...</div></div><div id=\"result-talk\"></div></div><div class=\"section\"><h2>Unmapped Fields</h2><p>List the fields in the moresleep data that have no explicit mapping in the talk indexes. Their types are decided by the dynamic templates; map or drop them as needed. Fetches all talks, which may take a while.</p><div class=\"form-group\"><button class=\"secondary\" hx-get=\"/admin/mappings/unmapped\" hx-target=\"#result-unmapped\" hx-indicator=\"#loading-unmapped\" hx-disabled-elt=\"this\">Find Unmapped Fields</button></div><div id=\"loading-unmapped\" class=\"htmx-indicator\"><div class=\"result loading\">Inspecting source data...</div></div><div id=\"result-unmapped\"></div></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -93,4 +174,74 @@ func Dashboard(conferences []domain.Conference) templ.Component {
 	})
 }
 
+// formatScheduleTime renders a schedule time in the server's local time zone
+func formatScheduleTime(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04:05 MST")
+}
+
+// ConferenceOptions renders the options of the conference select
+func ConferenceOptions(conferences []domain.Conference) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var9 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var9 == nil {
+			templ_7745c5c3_Var9 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "<option value=\"\">Select a conference...</option> ")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		for _, conf := range conferences {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "<option value=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var10 string
+			templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(conf.Slug)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/adapters/web/templates/dashboard.templ`, Line: 182, Col: 27}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var11 string
+			templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(conf.Name)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/adapters/web/templates/dashboard.templ`, Line: 182, Col: 41}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "</option>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		return nil
+	})
+}
+
 var _ = templruntime.GeneratedTemplate
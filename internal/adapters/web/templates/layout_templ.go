@@ -55,7 +55,7 @@ func Layout(title string) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</title><script src=\"https://unpkg.com/htmx.org@2.0.4\"></script><style>\n\t\t\t\t* {\n\t\t\t\t\tbox-sizing: border-box;\n\t\t\t\t}\n\t\t\t\tbody {\n\t\t\t\t\tfont-family: system-ui, -apple-system, sans-serif;\n\t\t\t\t\tmax-width: 800px;\n\t\t\t\t\tmargin: 0 auto;\n\t\t\t\t\tpadding: 0 1rem;\n\t\t\t\t\tbackground-color: #f5f5f5;\n\t\t\t\t}\n\t\t\t\theader {\n\t\t\t\t\tdisplay: flex;\n\t\t\t\t\tjustify-content: space-between;\n\t\t\t\t\talign-items: center;\n\t\t\t\t\tpadding: 1rem 0;\n\t\t\t\t\tmargin-bottom: 1rem;\n\t\t\t\t\tborder-bottom: 1px solid #ddd;\n\t\t\t\t}\n\t\t\t\theader .user-info {\n\t\t\t\t\tdisplay: flex;\n\t\t\t\t\talign-items: center;\n\t\t\t\t\tgap: 1rem;\n\t\t\t\t\tcolor: #666;\n\t\t\t\t\tfont-size: 0.9rem;\n\t\t\t\t}\n\t\t\t\theader .logout-btn {\n\t\t\t\t\tpadding: 0.4rem 0.8rem;\n\t\t\t\t\tbackground-color: #dc3545;\n\t\t\t\t\tcolor: white;\n\t\t\t\t\tborder: none;\n\t\t\t\t\tborder-radius: 4px;\n\t\t\t\t\tcursor: pointer;\n\t\t\t\t\tfont-size: 0.85rem;\n\t\t\t\t}\n\t\t\t\theader .logout-btn:hover {\n\t\t\t\t\tbackground-color: #c82333;\n\t\t\t\t}\n\t\t\t\th1 {\n\t\t\t\t\tcolor: #333;\n\t\t\t\t\tmargin: 0;\n\t\t\t\t}\n\t\t\t\t.section {\n\t\t\t\t\tmargin-bottom: 1.5rem;\n\t\t\t\t\tpadding: 1.5rem;\n\t\t\t\t\tbackground: white;\n\t\t\t\t\tborder: 1px solid #ddd;\n\t\t\t\t\tborder-radius: 8px;\n\t\t\t\t\tbox-shadow: 0 1px 3px rgba(0,0,0,0.1);\n\t\t\t\t}\n\t\t\t\t.section h2 {\n\t\t\t\t\tmargin-top: 0;\n\t\t\t\t\tcolor: #444;\n\t\t\t\t\tfont-size: 1.25rem;\n\t\t\t\t}\n\t\t\t\t.section p {\n\t\t\t\t\tcolor: #666;\n\t\t\t\t\tmargin-bottom: 1rem;\n\t\t\t\t}\n\t\t\t\tbutton {\n\t\t\t\t\tpadding: 0.5rem 1rem;\n\t\t\t\t\tcursor: pointer;\n\t\t\t\t\tbackground-color: #0066cc;\n\t\t\t\t\tcolor: white;\n\t\t\t\t\tborder: none;\n\t\t\t\t\tborder-radius: 4px;\n\t\t\t\t\tfont-size: 0.9rem;\n\t\t\t\t}\n\t\t\t\tbutton:hover {\n\t\t\t\t\tbackground-color: #0055aa;\n\t\t\t\t}\n\t\t\t\tbutton:disabled {\n\t\t\t\t\tbackground-color: #ccc;\n\t\t\t\t\tcursor: not-allowed;\n\t\t\t\t}\n\t\t\t\tselect, input[type=\"text\"] {\n\t\t\t\t\tpadding: 0.5rem;\n\t\t\t\t\tmin-width: 250px;\n\t\t\t\t\tborder: 1px solid #ccc;\n\t\t\t\t\tborder-radius: 4px;\n\t\t\t\t\tfont-size: 0.9rem;\n\t\t\t\t}\n\t\t\t\t.form-group {\n\t\t\t\t\tdisplay: flex;\n\t\t\t\t\tgap: 0.5rem;\n\t\t\t\t\talign-items: center;\n\t\t\t\t\tflex-wrap: wrap;\n\t\t\t\t}\n\t\t\t\t.result {\n\t\t\t\t\tmargin-top: 1rem;\n\t\t\t\t\tpadding: 0.75rem 1rem;\n\t\t\t\t\tborder-radius: 4px;\n\t\t\t\t}\n\t\t\t\t.success {\n\t\t\t\t\tbackground-color: #d4edda;\n\t\t\t\t\tcolor: #155724;\n\t\t\t\t\tborder: 1px solid #c3e6cb;\n\t\t\t\t}\n\t\t\t\t.error {\n\t\t\t\t\tbackground-color: #f8d7da;\n\t\t\t\t\tcolor: #721c24;\n\t\t\t\t\tborder: 1px solid #f5c6cb;\n\t\t\t\t}\n\t\t\t\t.htmx-request button {\n\t\t\t\t\topacity: 0.6;\n\t\t\t\t}\n\t\t\t\t.htmx-indicator {\n\t\t\t\t\tdisplay: none;\n\t\t\t\t}\n\t\t\t\t.htmx-request .htmx-indicator {\n\t\t\t\t\tdisplay: block;\n\t\t\t\t}\n\t\t\t\t.loading {\n\t\t\t\t\tbackground-color: #fff3cd;\n\t\t\t\t\tcolor: #856404;\n\t\t\t\t\tborder: 1px solid #ffeeba;\n\t\t\t\t}\n\t\t\t</style></head><body><header><h1>Talks Indexer</h1>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</title><script src=\"https://unpkg.com/htmx.org@2.0.4\"></script><style>\n\t\t\t\t* {\n\t\t\t\t\tbox-sizing: border-box;\n\t\t\t\t}\n\t\t\t\tbody {\n\t\t\t\t\tfont-family: system-ui, -apple-system, sans-serif;\n\t\t\t\t\tmax-width: 800px;\n\t\t\t\t\tmargin: 0 auto;\n\t\t\t\t\tpadding: 0 1rem;\n\t\t\t\t\tbackground-color: #f5f5f5;\n\t\t\t\t}\n\t\t\t\theader {\n\t\t\t\t\tdisplay: flex;\n\t\t\t\t\tjustify-content: space-between;\n\t\t\t\t\talign-items: center;\n\t\t\t\t\tpadding: 1rem 0;\n\t\t\t\t\tmargin-bottom: 1rem;\n\t\t\t\t\tborder-bottom: 1px solid #ddd;\n\t\t\t\t}\n\t\t\t\theader .user-info {\n\t\t\t\t\tdisplay: flex;\n\t\t\t\t\talign-items: center;\n\t\t\t\t\tgap: 1rem;\n\t\t\t\t\tcolor: #666;\n\t\t\t\t\tfont-size: 0.9rem;\n\t\t\t\t}\n\t\t\t\theader .logout-btn {\n\t\t\t\t\tpadding: 0.4rem 0.8rem;\n\t\t\t\t\tbackground-color: #dc3545;\n\t\t\t\t\tcolor: white;\n\t\t\t\t\tborder: none;\n\t\t\t\t\tborder-radius: 4px;\n\t\t\t\t\tcursor: pointer;\n\t\t\t\t\tfont-size: 0.85rem;\n\t\t\t\t}\n\t\t\t\theader .logout-btn:hover {\n\t\t\t\t\tbackground-color: #c82333;\n\t\t\t\t}\n\t\t\t\th1 {\n\t\t\t\t\tcolor: #333;\n\t\t\t\t\tmargin: 0;\n\t\t\t\t}\n\t\t\t\t.section {\n\t\t\t\t\tmargin-bottom: 1.5rem;\n\t\t\t\t\tpadding: 1.5rem;\n\t\t\t\t\tbackground: white;\n\t\t\t\t\tborder: 1px solid #ddd;\n\t\t\t\t\tborder-radius: 8px;\n\t\t\t\t\tbox-shadow: 0 1px 3px rgba(0,0,0,0.1);\n\t\t\t\t}\n\t\t\t\t.section h2 {\n\t\t\t\t\tmargin-top: 0;\n\t\t\t\t\tcolor: #444;\n\t\t\t\t\tfont-size: 1.25rem;\n\t\t\t\t}\n\t\t\t\t.section p {\n\t\t\t\t\tcolor: #666;\n\t\t\t\t\tmargin-bottom: 1rem;\n\t\t\t\t}\n\t\t\t\tbutton {\n\t\t\t\t\tpadding: 0.5rem 1rem;\n\t\t\t\t\tcursor: pointer;\n\t\t\t\t\tbackground-color: #0066cc;\n\t\t\t\t\tcolor: white;\n\t\t\t\t\tborder: none;\n\t\t\t\t\tborder-radius: 4px;\n\t\t\t\t\tfont-size: 0.9rem;\n\t\t\t\t}\n\t\t\t\tbutton:hover {\n\t\t\t\t\tbackground-color: #0055aa;\n\t\t\t\t}\n\t\t\t\tbutton.secondary {\n\t\t\t\t\tbackground-color: #6c757d;\n\t\t\t\t}\n\t\t\t\tbutton.secondary:hover {\n\t\t\t\t\tbackground-color: #5a6268;\n\t\t\t\t}\n\t\t\t\tbutton.danger {\n\t\t\t\t\tbackground-color: #dc3545;\n\t\t\t\t}\n\t\t\t\tbutton.danger:hover {\n\t\t\t\t\tbackground-color: #c82333;\n\t\t\t\t}\n\t\t\t\tbutton:disabled {\n\t\t\t\t\tbackground-color: #ccc;\n\t\t\t\t\tcursor: not-allowed;\n\t\t\t\t}\n\t\t\t\tselect, input[type=\"text\"] {\n\t\t\t\t\tpadding: 0.5rem;\n\t\t\t\t\tmin-width: 250px;\n\t\t\t\t\tborder: 1px solid #ccc;\n\t\t\t\t\tborder-radius: 4px;\n\t\t\t\t\tfont-size: 0.9rem;\n\t\t\t\t}\n\t\t\t\t.form-group {\n\t\t\t\t\tdisplay: flex;\n\t\t\t\t\tgap: 0.5rem;\n\t\t\t\t\talign-items: center;\n\t\t\t\t\tflex-wrap: wrap;\n\t\t\t\t}\n\t\t\t\t.result {\n\t\t\t\t\tmargin-top: 1rem;\n\t\t\t\t\tpadding: 0.75rem 1rem;\n\t\t\t\t\tborder-radius: 4px;\n\t\t\t\t}\n\t\t\t\t.success {\n\t\t\t\t\tbackground-color: #d4edda;\n\t\t\t\t\tcolor: #155724;\n\t\t\t\t\tborder: 1px solid #c3e6cb;\n\t\t\t\t}\n\t\t\t\t.error {\n\t\t\t\t\tbackground-color: #f8d7da;\n\t\t\t\t\tcolor: #721c24;\n\t\t\t\t\tborder: 1px solid #f5c6cb;\n\t\t\t\t}\n\t\t\t\t.htmx-request button {\n\t\t\t\t\topacity: 0.6;\n\t\t\t\t}\n\t\t\t\t.htmx-indicator {\n\t\t\t\t\tdisplay: none;\n\t\t\t\t}\n\t\t\t\t.htmx-request .htmx-indicator {\n\t\t\t\t\tdisplay: block;\n\t\t\t\t}\n\t\t\t\t.loading {\n\t\t\t\t\tbackground-color: #fff3cd;\n\t\t\t\t\tcolor: #856404;\n\t\t\t\t\tborder: 1px solid #ffeeba;\n\t\t\t\t}\n\t\t\t\t.partial {\n\t\t\t\t\tbackground-color: #ffe5cc;\n\t\t\t\t\tcolor: #7a3e00;\n\t\t\t\t\tborder: 1px solid #ffc999;\n\t\t\t\t}\n\t\t\t\t.report {\n\t\t\t\t\tmargin-top: 0.5rem;\n\t\t\t\t\tfont-size: 0.85rem;\n\t\t\t\t}\n\t\t\t\t.report p.report-summary, .report p.report-heading {\n\t\t\t\t\tmargin: 0.25rem 0;\n\t\t\t\t\tcolor: inherit;\n\t\t\t\t}\n\t\t\t\t.report-heading {\n\t\t\t\t\tfont-weight: 600;\n\t\t\t\t}\n\t\t\t\t.report-table {\n\t\t\t\t\twidth: 100%;\n\t\t\t\t\tborder-collapse: collapse;\n\t\t\t\t}\n\t\t\t\t.report-table th, .report-table td {\n\t\t\t\t\ttext-align: left;\n\t\t\t\t\tpadding: 0.2rem 0.5rem 0.2rem 0;\n\t\t\t\t}\n\t\t\t\t.report-list {\n\t\t\t\t\tmargin: 0;\n\t\t\t\t\tpadding-left: 1.25rem;\n\t\t\t\t}\n\t\t\t\t.report-diff summary {\n\t\t\t\t\tcursor: pointer;\n\t\t\t\t\tfont-weight: 600;\n\t\t\t\t}\n\t\t\t\t.report-diff code {\n\t\t\t\t\tfont-size: 0.8rem;\n\t\t\t\t}\n\t\t\t\t.schedule-error {\n\t\t\t\t\tdisplay: block;\n\t\t\t\t\tcolor: #721c24;\n\t\t\t\t\tfont-size: 0.8rem;\n\t\t\t\t}\n\t\t\t\t.job-header {\n\t\t\t\t\tdisplay: flex;\n\t\t\t\t\talign-items: center;\n\t\t\t\t\tgap: 0.75rem;\n\t\t\t\t}\n\t\t\t\t.job-meta {\n\t\t\t\t\tflex: 1;\n\t\t\t\t\tfont-size: 0.85rem;\n\t\t\t\t}\n\t\t\t\t.job-header .cancel-btn {\n\t\t\t\t\tbackground-color: #dc3545;\n\t\t\t\t\tpadding: 0.3rem 0.7rem;\n\t\t\t\t\tfont-size: 0.8rem;\n\t\t\t\t}\n\t\t\t\t.job-progress {\n\t\t\t\t\twidth: 100%;\n\t\t\t\t\tmargin-top: 0.5rem;\n\t\t\t\t\tborder-collapse: collapse;\n\t\t\t\t\tfont-size: 0.85rem;\n\t\t\t\t}\n\t\t\t\t.job-progress td {\n\t\t\t\t\tpadding: 0.2rem 0.5rem 0.2rem 0;\n\t\t\t\t}\n\t\t\t\t.job-errors {\n\t\t\t\t\tmargin: 0.5rem 0 0;\n\t\t\t\t\tpadding-left: 1.25rem;\n\t\t\t\t\tfont-size: 0.85rem;\n\t\t\t\t}\n\t\t\t</style></head><body><header><h1>Talks Indexer</h1>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -67,7 +67,7 @@ func Layout(title string) templ.Component {
 			var templ_7745c5c3_Var3 string
 			templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(email)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/adapters/web/templates/layout.templ`, Line: 148, Col: 19}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/adapters/web/templates/layout.templ`, Line: 228, Col: 19}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 			if templ_7745c5c3_Err != nil {
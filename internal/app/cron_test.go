@@ -0,0 +1,66 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	// Wednesday
+	from := time.Date(2024, 9, 4, 12, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"*/5 * * * *", time.Date(2024, 9, 4, 12, 35, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, 9, 5, 3, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 9, 5, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 9, 4, 13, 0, 0, 0, time.UTC)},
+		{"15,45 8-18 * * *", time.Date(2024, 9, 4, 12, 45, 0, 0, time.UTC)},
+		{"0 6 * * 1-5", time.Date(2024, 9, 5, 6, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 9, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// With both day fields restricted either one matches
+		{"0 0 13 * 5", time.Date(2024, 9, 6, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", from.Add(90 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, schedule.Next(from))
+		})
+	}
+}
+
+func TestParseSchedule_NeverMatches(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Date(2024, 9, 4, 0, 0, 0, 0, time.UTC)).IsZero())
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every soon",
+		"@every 10ms",
+		"@fortnightly",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseSchedule(expr)
+			assert.Error(t, err)
+		})
+	}
+}
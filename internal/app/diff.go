@@ -0,0 +1,204 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// diffIndex compares the documents a reindex would write with the documents currently
+// in the index. Indexed documents that are not in docs are listed as deleted.
+func diffIndex(indexName string, current map[string]map[string]interface{}, docs []domain.Talk) (domain.IndexDiff, error) {
+	diff := domain.IndexDiff{
+		Index:   indexName,
+		Added:   []string{},
+		Updated: []domain.DocumentChange{},
+		Deleted: []string{},
+	}
+
+	seen := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		seen[doc.ID] = true
+
+		existing, ok := current[doc.ID]
+		if !ok {
+			diff.Added = append(diff.Added, doc.ID)
+			continue
+		}
+
+		fields, err := diffDocument(existing, doc)
+		if err != nil {
+			return diff, err
+		}
+		if len(fields) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		diff.Updated = append(diff.Updated, domain.DocumentChange{ID: doc.ID, Fields: fields})
+	}
+
+	for id := range current {
+		if !seen[id] {
+			diff.Deleted = append(diff.Deleted, id)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Deleted)
+	sort.Slice(diff.Updated, func(i, j int) bool { return diff.Updated[i].ID < diff.Updated[j].ID })
+
+	return diff, nil
+}
+
+// diffDocument returns the field-level changes between an indexed document source and
+// the document that would replace it. The new document goes through the same JSON
+// encoding used for indexing so both sides are compared in the same representation.
+func diffDocument(existing map[string]interface{}, doc domain.Talk) ([]domain.FieldChange, error) {
+	source, err := documentSource(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	before := make(map[string]interface{})
+	flattenFields("", existing, before)
+	after := make(map[string]interface{})
+	flattenFields("", source, after)
+
+	var changes []domain.FieldChange
+	for field, value := range after {
+		previous, ok := before[field]
+		if !ok || !reflect.DeepEqual(previous, value) {
+			changes = append(changes, domain.FieldChange{Field: field, Before: previous, After: value})
+		}
+	}
+	for field, previous := range before {
+		if _, ok := after[field]; !ok {
+			changes = append(changes, domain.FieldChange{Field: field, Before: previous})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+// documentSource encodes a talk into the generic form of an indexed document source
+func documentSource(doc domain.Talk) (map[string]interface{}, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode talk %s: %w", doc.ID, err)
+	}
+
+	var source map[string]interface{}
+	if err := json.Unmarshal(data, &source); err != nil {
+		return nil, fmt.Errorf("failed to decode talk %s: %w", doc.ID, err)
+	}
+	return source, nil
+}
+
+// flattenFields writes every leaf value of a nested object into out keyed by its dotted path.
+// Arrays are treated as single values. Null values and empty arrays or objects are left
+// out, since Elasticsearch indexes them the same as a missing field.
+func flattenFields(prefix string, value map[string]interface{}, out map[string]interface{}) {
+	for key, v := range value {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		switch v := v.(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			flattenFields(path, v, out)
+		case []interface{}:
+			if len(v) > 0 {
+				out[path] = v
+			}
+		default:
+			out[path] = v
+		}
+	}
+}
+
+// dryRunIndexes scans the indexed documents, limited to one conference unless
+// conferenceID is empty, and adds the private and public diffs to the report.
+// The diffs are also returned so callers can summarize them.
+// Indexed documents missing from talks are listed as deleted: a full reindex replaces
+// the whole index, and a conference reindex removes its stale talks.
+func (s *IndexerService) dryRunIndexes(ctx context.Context, conferenceID string, talks []domain.Talk, report *domain.ReindexReport) (privateDiff, publicDiff domain.IndexDiff, err error) {
+	privateCurrent, err := s.searchIndex.ScanDocuments(ctx, s.privateIndex, conferenceID)
+	if err != nil {
+		return privateDiff, publicDiff, fmt.Errorf("failed to read private index: %w", err)
+	}
+	privateDiff, err = diffIndex(s.privateIndex, privateCurrent, prepareTalksForPrivateIndex(talks))
+	if err != nil {
+		return privateDiff, publicDiff, err
+	}
+
+	publicCurrent, err := s.searchIndex.ScanDocuments(ctx, s.publicIndex, conferenceID)
+	if err != nil {
+		return privateDiff, publicDiff, fmt.Errorf("failed to read public index: %w", err)
+	}
+	publicDiff, err = diffIndex(s.publicIndex, publicCurrent, filterApprovedTalksForPublic(talks))
+	if err != nil {
+		return privateDiff, publicDiff, err
+	}
+
+	report.Diffs = append(report.Diffs, privateDiff, publicDiff)
+	return privateDiff, publicDiff, nil
+}
+
+// dryRunTalk computes the diff of reindexing a single talk. A nil talk means it no
+// longer exists in the source and would be removed from both indexes.
+func (s *IndexerService) dryRunTalk(ctx context.Context, talkID string, talk *domain.Talk, report *domain.ReindexReport) error {
+	var privateDocs, publicDocs []domain.Talk
+	if talk != nil {
+		privateDocs = []domain.Talk{talk.ToPrivate()}
+		if domain.TalkStatus(talk.Status).IsPublic() {
+			publicDocs = []domain.Talk{talk.ToPublic()}
+		}
+	}
+
+	var privateDiff, publicDiff domain.IndexDiff
+	for _, target := range []struct {
+		index string
+		docs  []domain.Talk
+		diff  *domain.IndexDiff
+	}{
+		{s.privateIndex, privateDocs, &privateDiff},
+		{s.publicIndex, publicDocs, &publicDiff},
+	} {
+		current := make(map[string]map[string]interface{})
+		var source map[string]interface{}
+		found, err := s.searchIndex.GetDocument(ctx, target.index, talkID, &source)
+		if err != nil {
+			return fmt.Errorf("failed to read talk %s from %s: %w", talkID, target.index, err)
+		}
+		if found {
+			current[talkID] = source
+		}
+
+		diff, err := diffIndex(target.index, current, target.docs)
+		if err != nil {
+			return err
+		}
+		*target.diff = diff
+	}
+	report.Diffs = append(report.Diffs, privateDiff, publicDiff)
+
+	if talk != nil {
+		report.AddConference(domain.ConferenceReport{
+			ConferenceID:  talk.ConferenceID,
+			Slug:          talk.ConferenceSlug,
+			PrivateCount:  len(privateDocs),
+			PublicCount:   len(publicDocs),
+			PublicDeleted: len(publicDiff.Deleted),
+		})
+	}
+
+	return nil
+}
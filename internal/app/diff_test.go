@@ -0,0 +1,200 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failOnLock makes any attempt to take the indexing lock fail the test
+func failOnLock(t *testing.T) func(ctx context.Context, indexName string, lockID string, owner string, ttl time.Duration) (bool, error) {
+	return func(ctx context.Context, indexName string, lockID string, owner string, ttl time.Duration) (bool, error) {
+		t.Error("dry run must not take the indexing lock")
+		return false, errors.New("unexpected lock")
+	}
+}
+
+// assertNothingWritten verifies that an operation left the indexes untouched
+func assertNothingWritten(t *testing.T, index *mockSearchIndex) {
+	t.Helper()
+	assert.Empty(t, index.bulkIndexCalls)
+	assert.Empty(t, index.createIndexCalls)
+	assert.Empty(t, index.swapAliasCalls)
+	assert.Empty(t, index.deleteIndexCalls)
+	assert.Empty(t, index.deleteDocsCalls)
+	assert.Empty(t, index.deleteStaleCalls)
+}
+
+func TestDiffIndex(t *testing.T) {
+	current := map[string]map[string]interface{}{}
+	for _, talk := range []domain.Talk{
+		{ID: "talk-1", Status: "APPROVED", Data: map[string]interface{}{"title": "Same"}},
+		{ID: "talk-2", Status: "APPROVED", Data: map[string]interface{}{"title": "Old", "level": "beginner"}},
+		{ID: "talk-4", Status: "APPROVED"},
+	} {
+		source, err := documentSource(talk)
+		require.NoError(t, err)
+		current[talk.ID] = source
+	}
+	docs := []domain.Talk{
+		{ID: "talk-1", Status: "APPROVED", Data: map[string]interface{}{"title": "Same"}, Speakers: domain.Speakers{}},
+		{ID: "talk-2", Status: "APPROVED", Data: map[string]interface{}{"title": "New", "format": "lightning"}},
+		{ID: "talk-3", Status: "APPROVED"},
+	}
+
+	diff, err := diffIndex("public", current, docs)
+	require.NoError(t, err)
+
+	assert.Equal(t, "public", diff.Index)
+	assert.Equal(t, []string{"talk-3"}, diff.Added)
+	assert.Equal(t, []string{"talk-4"}, diff.Deleted)
+	assert.Equal(t, 1, diff.Unchanged)
+	require.Len(t, diff.Updated, 1)
+	assert.Equal(t, domain.DocumentChange{
+		ID: "talk-2",
+		Fields: []domain.FieldChange{
+			{Field: "data.format", After: "lightning"},
+			{Field: "data.level", Before: "beginner"},
+			{Field: "data.title", Before: "Old", After: "New"},
+		},
+	}, diff.Updated[0])
+	assert.True(t, diff.HasChanges())
+}
+
+func TestReindexAll_DryRun(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Name: "JavaZone 2024", Slug: "javazone2024"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return []domain.Talk{
+				{ID: "talk-1", ConferenceID: "conf-1", Status: "APPROVED", Data: map[string]interface{}{"title": "New title"}},
+				{ID: "talk-2", ConferenceID: "conf-1", Status: "SUBMITTED"},
+			}, nil
+		},
+	}
+
+	index := &mockSearchIndex{}
+	index.acquireLockFunc = failOnLock(t)
+	require.NoError(t, index.PutDocument(context.Background(), "private", "talk-1",
+		domain.Talk{ID: "talk-1", ConferenceID: "conf-1", Status: "APPROVED", Data: map[string]interface{}{"title": "Old title"}}))
+	require.NoError(t, index.PutDocument(context.Background(), "public", "talk-1",
+		domain.Talk{ID: "talk-1", ConferenceID: "conf-1", Status: "APPROVED", Data: map[string]interface{}{"title": "Old title"}}))
+	require.NoError(t, index.PutDocument(context.Background(), "public", "talk-9",
+		domain.Talk{ID: "talk-9", ConferenceID: "conf-0", Status: "APPROVED"}))
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	report, err := service.ReindexAll(context.Background(), domain.ReindexOptions{DryRun: true})
+	require.NoError(t, err)
+
+	assertNothingWritten(t, index)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 2, report.PrivateCount)
+	assert.Equal(t, 1, report.PublicCount)
+
+	require.Len(t, report.Diffs, 2)
+	private, public := report.Diffs[0], report.Diffs[1]
+
+	assert.Equal(t, "private", private.Index)
+	assert.Equal(t, []string{"talk-2"}, private.Added)
+	require.Len(t, private.Updated, 1)
+	assert.Equal(t, []domain.FieldChange{{Field: "data.title", Before: "Old title", After: "New title"}}, private.Updated[0].Fields)
+	assert.Empty(t, private.Deleted)
+
+	// Documents of conferences no longer in the source would be dropped by the new generation
+	assert.Equal(t, "public", public.Index)
+	assert.Empty(t, public.Added)
+	assert.Len(t, public.Updated, 1)
+	assert.Equal(t, []string{"talk-9"}, public.Deleted)
+}
+
+func TestReindexConference_DryRun(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Name: "JavaZone 2024", Slug: "javazone2024"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return []domain.Talk{{ID: "talk-1", ConferenceID: "conf-1", Status: "REJECTED"}}, nil
+		},
+	}
+
+	index := &mockSearchIndex{}
+	index.acquireLockFunc = failOnLock(t)
+	for _, indexName := range []string{"private", "public"} {
+		require.NoError(t, index.PutDocument(context.Background(), indexName, "talk-1",
+			domain.Talk{ID: "talk-1", ConferenceID: "conf-1", Status: "APPROVED"}))
+		require.NoError(t, index.PutDocument(context.Background(), indexName, "talk-2",
+			domain.Talk{ID: "talk-2", ConferenceID: "conf-1", Status: "APPROVED"}))
+		// Talks of other conferences are out of scope for a conference reindex
+		require.NoError(t, index.PutDocument(context.Background(), indexName, "talk-9",
+			domain.Talk{ID: "talk-9", ConferenceID: "conf-2", Status: "APPROVED"}))
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	report, err := service.ReindexConference(context.Background(), "javazone2024", domain.ReindexOptions{DryRun: true})
+	require.NoError(t, err)
+
+	assertNothingWritten(t, index)
+	require.Len(t, report.Diffs, 2)
+
+	private := report.Diffs[0]
+	require.Len(t, private.Updated, 1)
+	assert.Equal(t, []domain.FieldChange{{Field: "status", Before: "APPROVED", After: "REJECTED"}}, private.Updated[0].Fields)
+	assert.Equal(t, []string{"talk-2"}, private.Deleted)
+
+	public := report.Diffs[1]
+	assert.Empty(t, public.Updated)
+	assert.Equal(t, []string{"talk-1", "talk-2"}, public.Deleted)
+
+	require.Len(t, report.Conferences, 1)
+	assert.Equal(t, 1, report.Conferences[0].PrivateDeleted)
+	assert.Equal(t, 2, report.Conferences[0].PublicDeleted)
+}
+
+func TestReindexTalk_DryRun(t *testing.T) {
+	t.Run("new talk", func(t *testing.T) {
+		source := &mockTalkSource{
+			getTalkFunc: func(ctx context.Context, talkID string) (*domain.Talk, error) {
+				return &domain.Talk{ID: talkID, ConferenceID: "conf-1", Status: "APPROVED"}, nil
+			},
+		}
+		index := &mockSearchIndex{}
+		index.acquireLockFunc = failOnLock(t)
+
+		service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+		report, err := service.ReindexTalk(context.Background(), "talk-1", domain.ReindexOptions{DryRun: true})
+		require.NoError(t, err)
+
+		assertNothingWritten(t, index)
+		require.Len(t, report.Diffs, 2)
+		assert.Equal(t, []string{"talk-1"}, report.Diffs[0].Added)
+		assert.Equal(t, []string{"talk-1"}, report.Diffs[1].Added)
+	})
+
+	t.Run("talk removed from source", func(t *testing.T) {
+		source := &mockTalkSource{
+			getTalkFunc: func(ctx context.Context, talkID string) (*domain.Talk, error) {
+				return nil, domain.ErrNotFound
+			},
+		}
+		index := &mockSearchIndex{}
+		index.acquireLockFunc = failOnLock(t)
+		require.NoError(t, index.PutDocument(context.Background(), "private", "talk-1",
+			domain.Talk{ID: "talk-1", ConferenceID: "conf-1", Status: "SUBMITTED"}))
+
+		service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+		report, err := service.ReindexTalk(context.Background(), "talk-1", domain.ReindexOptions{DryRun: true})
+		require.NoError(t, err)
+
+		assertNothingWritten(t, index)
+		require.Len(t, report.Diffs, 2)
+		assert.Equal(t, []string{"talk-1"}, report.Diffs[0].Deleted)
+		assert.False(t, report.Diffs[1].HasChanges())
+		assert.Empty(t, report.Conferences)
+	})
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// SetMappingDriftPolicy sets what happens when an index was created with a mapping that
+// differs from the compiled one: warn and keep writing, fail, or rebuild all indexes
+func (s *IndexerService) SetMappingDriftPolicy(policy domain.MappingDriftPolicy) {
+	if policy == "" {
+		policy = domain.MappingDriftWarn
+	}
+	s.driftPolicy = policy
+}
+
+// CheckMappings compares the indexes behind every alias with the compiled mappings and
+// returns the ones that have drifted. With the fail policy drift is also returned as
+// domain.ErrMappingDrift. The caller decides how to rebuild; a full reindex always
+// creates its generations with the compiled mappings.
+func (s *IndexerService) CheckMappings(ctx context.Context) ([]domain.MappingDrift, error) {
+	drifts, err := s.mappingDrifts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logDrifts(drifts)
+	if len(drifts) > 0 && s.driftPolicy == domain.MappingDriftFail {
+		return drifts, driftError(drifts)
+	}
+	return drifts, nil
+}
+
+// guardMappings checks the mappings before a partial reindex writes into the existing
+// indexes and records any drift in the report. It returns true if the caller should run
+// a full rebuild instead, and domain.ErrMappingDrift if the policy refuses to write.
+// Dry runs write nothing, so drift is only reported.
+func (s *IndexerService) guardMappings(ctx context.Context, opts domain.ReindexOptions, report *domain.ReindexReport) (bool, error) {
+	drifts, err := s.mappingDrifts(ctx)
+	if err != nil {
+		return false, err
+	}
+	if len(drifts) == 0 {
+		return false, nil
+	}
+
+	report.MappingDrift = drifts
+	s.logDrifts(drifts)
+
+	if opts.DryRun {
+		return false, nil
+	}
+
+	switch s.driftPolicy {
+	case domain.MappingDriftFail:
+		return false, driftError(drifts)
+	case domain.MappingDriftRebuild:
+		s.logger.Info("rebuilding all indexes to migrate drifted mappings", "operation", report.Operation)
+		report.MappingRebuild = true
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// mappingDrifts returns every alias whose index was created with a different mapping
+func (s *IndexerService) mappingDrifts(ctx context.Context) ([]domain.MappingDrift, error) {
+	var drifts []domain.MappingDrift
+	for _, alias := range s.aliases() {
+		drift, err := s.searchIndex.CheckMapping(ctx, alias, s.getMappingForIndex(alias))
+		if err != nil {
+			return nil, fmt.Errorf("failed to check mapping of %s: %w", alias, err)
+		}
+		if drift != nil {
+			drift.Alias = alias
+			drifts = append(drifts, *drift)
+		}
+	}
+	return drifts, nil
+}
+
+// aliases returns the talk index aliases followed by the speaker index aliases when enabled
+func (s *IndexerService) aliases() []string {
+	aliases := []string{s.privateIndex, s.publicIndex}
+	if s.speakers != nil {
+		aliases = append(aliases, s.speakers.privateIndex, s.speakers.publicIndex)
+	}
+	return aliases
+}
+
+// logDrifts warns about every drifted index
+func (s *IndexerService) logDrifts(drifts []domain.MappingDrift) {
+	for _, drift := range drifts {
+		s.logger.Warn("index mapping differs from the compiled mapping",
+			"alias", drift.Alias,
+			"index", drift.Index,
+			"expected", drift.Expected,
+			"actual", drift.Actual,
+			"policy", s.driftPolicy,
+		)
+	}
+}
+
+// driftError wraps domain.ErrMappingDrift with the drifted indexes
+func driftError(drifts []domain.MappingDrift) error {
+	indexes := make([]string, len(drifts))
+	for i, drift := range drifts {
+		indexes[i] = drift.Index
+	}
+	return fmt.Errorf("%w: %s (run a full reindex to migrate)", domain.ErrMappingDrift, strings.Join(indexes, ", "))
+}
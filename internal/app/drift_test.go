@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// driftingIndex returns a mock index where only the public alias has drifted
+func driftingIndex() *mockSearchIndex {
+	return &mockSearchIndex{
+		checkMappingFunc: func(ctx context.Context, indexName string, mapping string) (*domain.MappingDrift, error) {
+			if indexName != "public" {
+				return nil, nil
+			}
+			return &domain.MappingDrift{Index: "public_20240101000000000", Expected: "new", Actual: "old"}, nil
+		},
+	}
+}
+
+func TestCheckMappings(t *testing.T) {
+	t.Run("checks every alias against its mapping", func(t *testing.T) {
+		checked := map[string]string{}
+		index := &mockSearchIndex{
+			checkMappingFunc: func(ctx context.Context, indexName string, mapping string) (*domain.MappingDrift, error) {
+				checked[indexName] = mapping
+				return nil, nil
+			},
+		}
+
+		service := newSpeakerService(&mockTalkSource{}, index)
+		drifts, err := service.CheckMappings(context.Background())
+
+		require.NoError(t, err)
+		assert.Empty(t, drifts)
+		assert.Equal(t, map[string]string{
+			"private":          testPrivateMapping,
+			"public":           testPublicMapping,
+			"speakers_private": testSpeakerPrivateMapping,
+			"speakers_public":  testSpeakerPublicMapping,
+		}, checked)
+	})
+
+	t.Run("warn returns the drift", func(t *testing.T) {
+		service := NewIndexerService(&mockTalkSource{}, driftingIndex(), "private", "public", testPrivateMapping, testPublicMapping)
+
+		drifts, err := service.CheckMappings(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.MappingDrift{
+			{Alias: "public", Index: "public_20240101000000000", Expected: "new", Actual: "old"},
+		}, drifts)
+	})
+
+	t.Run("fail returns an error", func(t *testing.T) {
+		service := NewIndexerService(&mockTalkSource{}, driftingIndex(), "private", "public", testPrivateMapping, testPublicMapping)
+		service.SetMappingDriftPolicy(domain.MappingDriftFail)
+
+		drifts, err := service.CheckMappings(context.Background())
+
+		assert.ErrorIs(t, err, domain.ErrMappingDrift)
+		assert.Contains(t, err.Error(), "public_20240101000000000")
+		assert.Len(t, drifts, 1)
+	})
+}
+
+func TestReindexConference_MappingDrift(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Name: "JavaZone 2024", Slug: "javazone2024"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return []domain.Talk{{ID: "talk-1", ConferenceID: "conf-1", Status: "APPROVED"}}, nil
+		},
+	}
+
+	t.Run("warn writes into the existing indexes", func(t *testing.T) {
+		index := driftingIndex()
+		service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+
+		report, err := service.ReindexConference(context.Background(), "javazone2024", domain.ReindexOptions{})
+
+		require.NoError(t, err)
+		assert.Len(t, report.MappingDrift, 1)
+		assert.False(t, report.MappingRebuild)
+		require.Len(t, index.bulkIndexCalls, 2)
+		assert.Equal(t, "public", index.bulkIndexCalls[1].IndexName)
+	})
+
+	t.Run("fail refuses to write", func(t *testing.T) {
+		index := driftingIndex()
+		service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+		service.SetMappingDriftPolicy(domain.MappingDriftFail)
+
+		report, err := service.ReindexConference(context.Background(), "javazone2024", domain.ReindexOptions{})
+
+		assert.ErrorIs(t, err, domain.ErrMappingDrift)
+		assert.Len(t, report.MappingDrift, 1)
+		assert.Empty(t, index.bulkIndexCalls)
+	})
+
+	t.Run("rebuild runs a full reindex into new generations", func(t *testing.T) {
+		index := driftingIndex()
+		service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+		service.SetMappingDriftPolicy(domain.MappingDriftRebuild)
+
+		report, err := service.ReindexConference(context.Background(), "javazone2024", domain.ReindexOptions{})
+
+		require.NoError(t, err)
+		assert.True(t, report.MappingRebuild)
+		assert.Equal(t, 1, report.PrivateCount)
+		assert.Len(t, index.createIndexCalls, 2)
+		require.Len(t, index.swapAliasCalls, 2)
+		assert.Equal(t, "private", index.swapAliasCalls[0].Alias)
+		assert.Equal(t, "public", index.swapAliasCalls[1].Alias)
+	})
+
+	t.Run("dry run only reports the drift", func(t *testing.T) {
+		index := driftingIndex()
+		service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+		service.SetMappingDriftPolicy(domain.MappingDriftFail)
+
+		report, err := service.ReindexConference(context.Background(), "javazone2024", domain.ReindexOptions{DryRun: true})
+
+		require.NoError(t, err)
+		assert.Len(t, report.MappingDrift, 1)
+		assert.Empty(t, index.createIndexCalls)
+	})
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// DefaultFetchConcurrency is the number of conferences whose talks are fetched in parallel
+const DefaultFetchConcurrency = 4
+
+// DefaultFetchTimeout bounds how long fetching the talks of a single conference may take
+const DefaultFetchTimeout = 2 * time.Minute
+
+// SetFetchConcurrency sets how many conferences a full reindex fetches in parallel.
+// Values below one are treated as one.
+func (s *IndexerService) SetFetchConcurrency(concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	s.fetchConcurrency = concurrency
+}
+
+// SetFetchTimeout sets how long fetching the talks of a single conference may take.
+// Zero or negative values disable the per-conference timeout.
+func (s *IndexerService) SetFetchTimeout(timeout time.Duration) {
+	s.fetchTimeout = timeout
+}
+
+// conferenceTalks holds the outcome of fetching the talks of one conference
+type conferenceTalks struct {
+	conference domain.Conference
+	talks      []domain.Talk
+	err        error
+}
+
+// fetchConferenceTalks fetches the talks of every conference using a pool of
+// fetchConcurrency workers. Results are returned in the order of conferences
+// regardless of which fetch finishes first.
+// A failed or timed out conference is recorded in its result and the others carry
+// on. A fatal error, one that means every other fetch would fail too, cancels the
+// remaining fetches and is returned, as is cancellation of ctx.
+func (s *IndexerService) fetchConferenceTalks(ctx context.Context, conferences []domain.Conference) ([]conferenceTalks, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// List every conference up front so job progress follows the conference order
+	for _, conf := range conferences {
+		reportProgress(ctx, conferenceProgress(conf, domain.ProgressPending, 0, nil))
+	}
+
+	results := make([]conferenceTalks, len(conferences))
+	indexes := make(chan int)
+
+	workers := min(s.fetchConcurrency, len(conferences))
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = s.fetchConference(ctx, conferences[i])
+				if isFatalFetchError(results[i].err) {
+					cancel(results[i].err)
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range conferences {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// fetchConference fetches the talks of one conference within the per-conference timeout
+func (s *IndexerService) fetchConference(ctx context.Context, conf domain.Conference) conferenceTalks {
+	reportProgress(ctx, conferenceProgress(conf, domain.ProgressRunning, 0, nil))
+
+	fetchCtx := ctx
+	if s.fetchTimeout > 0 {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithTimeout(ctx, s.fetchTimeout)
+		defer cancel()
+	}
+
+	talks, err := s.source.GetTalks(fetchCtx, conf.ID)
+	if err != nil {
+		// Report a timeout of this conference distinctly from cancellation of the whole operation
+		if errors.Is(fetchCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			err = fmt.Errorf("timed out after %s: %w", s.fetchTimeout, err)
+		}
+
+		s.logger.Error("failed to fetch talks for conference",
+			"conferenceID", conf.ID,
+			"conferenceName", conf.Name,
+			"error", err,
+		)
+		reportProgress(ctx, conferenceProgress(conf, domain.ProgressFailed, 0, err))
+		return conferenceTalks{conference: conf, err: err}
+	}
+
+	s.logger.Info("fetched talks for conference",
+		"conferenceID", conf.ID,
+		"conferenceName", conf.Name,
+		"count", len(talks),
+	)
+	reportProgress(ctx, conferenceProgress(conf, domain.ProgressDone, len(talks), nil))
+
+	return conferenceTalks{conference: conf, talks: talks}
+}
+
+// isFatalFetchError reports whether a fetch error means the remaining fetches would fail as well
+func isFatalFetchError(err error) bool {
+	return errors.Is(err, domain.ErrUnauthorized) || errors.Is(err, domain.ErrSourceUnavailable)
+}
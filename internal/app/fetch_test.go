@@ -0,0 +1,206 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConferences returns n conferences with IDs conf-0 to conf-(n-1)
+func testConferences(n int) []domain.Conference {
+	conferences := make([]domain.Conference, n)
+	for i := range conferences {
+		conferences[i] = domain.Conference{ID: fmt.Sprintf("conf-%d", i), Name: fmt.Sprintf("JavaZone %d", 2000+i), Slug: fmt.Sprintf("javazone%d", 2000+i)}
+	}
+	return conferences
+}
+
+// slowTalkSource returns a source where fetching each conference's talks takes the given latency
+func slowTalkSource(conferences []domain.Conference, talksPerConference int, latency time.Duration) *mockTalkSource {
+	return &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return conferences, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			select {
+			case <-time.After(latency):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			talks := make([]domain.Talk, talksPerConference)
+			for i := range talks {
+				talks[i] = domain.Talk{ID: fmt.Sprintf("%s-talk-%d", conferenceID, i), ConferenceID: conferenceID, Status: "APPROVED"}
+			}
+			return talks, nil
+		},
+	}
+}
+
+func TestFetchConferenceTalks_KeepsConferenceOrder(t *testing.T) {
+	conferences := testConferences(8)
+
+	var inFlight, maxInFlight atomic.Int32
+	source := &mockTalkSource{
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				peak := maxInFlight.Load()
+				if current <= peak || maxInFlight.CompareAndSwap(peak, current) {
+					break
+				}
+			}
+
+			// Later conferences finish first
+			var index int
+			fmt.Sscanf(conferenceID, "conf-%d", &index)
+			time.Sleep(time.Duration(8-index) * 2 * time.Millisecond)
+			return []domain.Talk{{ID: conferenceID + "-talk", ConferenceID: conferenceID}}, nil
+		},
+	}
+
+	service := NewIndexerService(source, &mockSearchIndex{}, "private", "public", testPrivateMapping, testPublicMapping)
+	service.SetFetchConcurrency(3)
+
+	results, err := service.fetchConferenceTalks(context.Background(), conferences)
+	require.NoError(t, err)
+
+	require.Len(t, results, len(conferences))
+	for i, result := range results {
+		assert.Equal(t, conferences[i].ID, result.conference.ID)
+		require.Len(t, result.talks, 1)
+		assert.Equal(t, conferences[i].ID+"-talk", result.talks[0].ID)
+	}
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(3))
+	assert.Greater(t, maxInFlight.Load(), int32(1))
+}
+
+func TestFetchConferenceTalks_PerConferenceTimeout(t *testing.T) {
+	conferences := testConferences(2)
+	source := &mockTalkSource{
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			if conferenceID == "conf-0" {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return []domain.Talk{{ID: "talk-1", ConferenceID: conferenceID}}, nil
+		},
+	}
+
+	service := NewIndexerService(source, &mockSearchIndex{}, "private", "public", testPrivateMapping, testPublicMapping)
+	service.SetFetchTimeout(20 * time.Millisecond)
+
+	results, err := service.fetchConferenceTalks(context.Background(), conferences)
+	require.NoError(t, err)
+
+	require.Error(t, results[0].err)
+	assert.ErrorIs(t, results[0].err, context.DeadlineExceeded)
+	assert.Contains(t, results[0].err.Error(), "timed out after 20ms")
+	assert.NoError(t, results[1].err)
+	assert.Len(t, results[1].talks, 1)
+}
+
+func TestFetchConferenceTalks_FatalErrorCancelsRemaining(t *testing.T) {
+	conferences := testConferences(20)
+
+	var mu sync.Mutex
+	var fetched []string
+	source := &mockTalkSource{
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			mu.Lock()
+			fetched = append(fetched, conferenceID)
+			mu.Unlock()
+
+			if conferenceID == "conf-0" {
+				return nil, fmt.Errorf("moresleep: %w", domain.ErrUnauthorized)
+			}
+			select {
+			case <-time.After(50 * time.Millisecond):
+				return nil, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	}
+
+	service := NewIndexerService(source, &mockSearchIndex{}, "private", "public", testPrivateMapping, testPublicMapping)
+	service.SetFetchConcurrency(2)
+
+	_, err := service.fetchConferenceTalks(context.Background(), conferences)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Less(t, len(fetched), len(conferences))
+}
+
+func TestReindexAll_FatalFetchErrorAborts(t *testing.T) {
+	for _, fatal := range []error{domain.ErrUnauthorized, domain.ErrSourceUnavailable} {
+		t.Run(fatal.Error(), func(t *testing.T) {
+			source := &mockTalkSource{
+				getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+					return testConferences(3), nil
+				},
+				getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+					return nil, errors.Join(errors.New("moresleep request failed"), fatal)
+				},
+			}
+			index := &mockSearchIndex{}
+
+			service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+			_, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+
+			assert.ErrorIs(t, err, fatal)
+			assert.Empty(t, index.createIndexCalls)
+			assert.Empty(t, index.swapAliasCalls)
+		})
+	}
+}
+
+func TestReindexAll_CancelledWhileFetching(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return testConferences(10), nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			cancel()
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	index := &mockSearchIndex{}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	_, err := service.ReindexAll(ctx, domain.ReindexOptions{})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, index.createIndexCalls)
+}
+
+func benchmarkReindexAll(b *testing.B, concurrency int) {
+	conferences := testConferences(16)
+	source := slowTalkSource(conferences, 50, 2*time.Millisecond)
+
+	service := NewIndexerService(source, &mockSearchIndex{}, "private", "public", testPrivateMapping, testPublicMapping)
+	service.SetFetchConcurrency(concurrency)
+
+	for b.Loop() {
+		if _, err := service.ReindexAll(context.Background(), domain.ReindexOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReindexAll_Sequential(b *testing.B)   { benchmarkReindexAll(b, 1) }
+func BenchmarkReindexAll_Concurrency4(b *testing.B) { benchmarkReindexAll(b, 4) }
+func BenchmarkReindexAll_Concurrency8(b *testing.B) { benchmarkReindexAll(b, 8) }
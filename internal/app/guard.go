@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// DefaultShrinkThreshold is the largest fraction of its documents an index, or a
+// conference within it, may lose in a single reindex before the reindex is refused
+const DefaultShrinkThreshold = 0.3
+
+// SetShrinkThresholds sets the largest fraction of documents a reindex may remove
+// from a whole index and from a single conference before it is refused.
+// Zero or negative values disable the respective check.
+func (s *IndexerService) SetShrinkThresholds(index, conference float64) {
+	s.indexShrinkThreshold = index
+	s.conferenceShrinkThreshold = conference
+}
+
+// guardShrink compares the number of documents a reindex would leave in each index
+// with the number indexed now. If conferenceID is empty the reindex replaces the
+// whole index; otherwise only that conference is checked.
+// Violations are recorded in the report. They abort the operation with
+// domain.ErrIndexShrink unless it is forced or a dry run, which writes nothing.
+func (s *IndexerService) guardShrink(ctx context.Context, conferenceID string, talks []domain.Talk, opts domain.ReindexOptions, report *domain.ReindexReport) error {
+	publicTalks := make([]domain.Talk, 0, len(talks))
+	for _, talk := range talks {
+		if domain.TalkStatus(talk.Status).IsPublic() {
+			publicTalks = append(publicTalks, talk)
+		}
+	}
+
+	var violations []domain.ShrinkViolation
+	for _, target := range []struct {
+		alias string
+		talks []domain.Talk
+	}{
+		{s.privateIndex, talks},
+		{s.publicIndex, publicTalks},
+	} {
+		found, err := s.shrinkViolations(ctx, target.alias, conferenceID, countByConference(target.talks))
+		if err != nil {
+			return err
+		}
+		violations = append(violations, found...)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	report.ShrinkViolations = append(report.ShrinkViolations, violations...)
+
+	descriptions := make([]string, len(violations))
+	for i, violation := range violations {
+		descriptions[i] = violation.String()
+	}
+	summary := strings.Join(descriptions, "; ")
+
+	switch {
+	case opts.DryRun:
+		s.logger.Warn("dry run would be refused by the shrink guard", "violations", summary)
+		return nil
+	case opts.Force:
+		s.logger.Warn("shrink guard overridden by force", "violations", summary)
+		return nil
+	}
+
+	s.logger.Error("refusing reindex that would shrink indexes beyond the safety threshold", "violations", summary)
+	return fmt.Errorf("%w: %s", domain.ErrIndexShrink, summary)
+}
+
+// shrinkViolations compares new per-conference document counts for an alias with its current counts
+func (s *IndexerService) shrinkViolations(ctx context.Context, alias, conferenceID string, counts map[string]int) ([]domain.ShrinkViolation, error) {
+	if s.indexShrinkThreshold <= 0 && s.conferenceShrinkThreshold <= 0 {
+		return nil, nil
+	}
+
+	current, err := s.searchIndex.CountByConference(ctx, alias)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents in %s: %w", alias, err)
+	}
+
+	var violations []domain.ShrinkViolation
+
+	if conferenceID != "" {
+		violation := domain.ShrinkViolation{
+			Index:        alias,
+			ConferenceID: conferenceID,
+			Current:      current[conferenceID],
+			New:          counts[conferenceID],
+			Threshold:    s.conferenceShrinkThreshold,
+		}
+		if exceedsThreshold(violation) {
+			violations = append(violations, violation)
+		}
+		return violations, nil
+	}
+
+	indexViolation := domain.ShrinkViolation{
+		Index:     alias,
+		Current:   sumCounts(current),
+		New:       sumCounts(counts),
+		Threshold: s.indexShrinkThreshold,
+	}
+	if exceedsThreshold(indexViolation) {
+		violations = append(violations, indexViolation)
+	}
+
+	conferenceIDs := make([]string, 0, len(current))
+	for id := range current {
+		conferenceIDs = append(conferenceIDs, id)
+	}
+	slices.Sort(conferenceIDs)
+
+	for _, id := range conferenceIDs {
+		violation := domain.ShrinkViolation{
+			Index:        alias,
+			ConferenceID: id,
+			Current:      current[id],
+			New:          counts[id],
+			Threshold:    s.conferenceShrinkThreshold,
+		}
+		if exceedsThreshold(violation) {
+			violations = append(violations, violation)
+		}
+	}
+
+	return violations, nil
+}
+
+// exceedsThreshold reports whether a count change drops by more than its threshold.
+// A threshold of zero or less disables the check.
+func exceedsThreshold(v domain.ShrinkViolation) bool {
+	return v.Threshold > 0 && v.Drop() > v.Threshold
+}
+
+// countByConference returns the number of talks per conference ID
+func countByConference(talks []domain.Talk) map[string]int {
+	counts := make(map[string]int)
+	for _, talk := range talks {
+		counts[talk.ConferenceID]++
+	}
+	return counts
+}
+
+// sumCounts returns the total of per-conference counts
+func sumCounts(counts map[string]int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
@@ -0,0 +1,154 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shrinkSource returns a source with two conferences where conf-1 suddenly has only one approved talk
+func shrinkSource() *mockTalkSource {
+	return &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{
+				{ID: "conf-1", Name: "JavaZone 2024", Slug: "javazone2024"},
+				{ID: "conf-2", Name: "JavaZone 2023", Slug: "javazone2023"},
+			}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			if conferenceID == "conf-1" {
+				return []domain.Talk{
+					{ID: "talk-1", ConferenceID: "conf-1", Status: "APPROVED"},
+					{ID: "talk-2", ConferenceID: "conf-1", Status: "SUBMITTED"},
+					{ID: "talk-3", ConferenceID: "conf-1", Status: "SUBMITTED"},
+					{ID: "talk-4", ConferenceID: "conf-1", Status: "SUBMITTED"},
+				}, nil
+			}
+			return []domain.Talk{
+				{ID: "talk-5", ConferenceID: "conf-2", Status: "APPROVED"},
+				{ID: "talk-6", ConferenceID: "conf-2", Status: "APPROVED"},
+			}, nil
+		},
+	}
+}
+
+// shrinkIndex returns an index where both aliases hold four talks for conf-1 and two for conf-2
+func shrinkIndex() *mockSearchIndex {
+	return &mockSearchIndex{
+		countFunc: func(ctx context.Context, indexName string) (map[string]int, error) {
+			return map[string]int{"conf-1": 4, "conf-2": 2}, nil
+		},
+	}
+}
+
+func TestReindexAll_ShrinkGuard_Refuses(t *testing.T) {
+	index := shrinkIndex()
+	service := NewIndexerService(shrinkSource(), index, "private", "public", testPrivateMapping, testPublicMapping)
+
+	report, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+
+	require.ErrorIs(t, err, domain.ErrIndexShrink)
+	assert.Contains(t, err.Error(), "conference conf-1 in public would shrink from 4 to 1 documents")
+
+	// Public dropped from 6 to 3 documents overall and conf-1 from 4 to 1
+	require.NotNil(t, report)
+	assert.Equal(t, []domain.ShrinkViolation{
+		{Index: "public", Current: 6, New: 3, Threshold: DefaultShrinkThreshold},
+		{Index: "public", ConferenceID: "conf-1", Current: 4, New: 1, Threshold: DefaultShrinkThreshold},
+	}, report.ShrinkViolations)
+
+	// Nothing was built or published
+	assert.Empty(t, index.createIndexCalls)
+	assert.Empty(t, index.bulkIndexCalls)
+	assert.Empty(t, index.swapAliasCalls)
+}
+
+func TestReindexAll_ShrinkGuard_Force(t *testing.T) {
+	index := shrinkIndex()
+	service := NewIndexerService(shrinkSource(), index, "private", "public", testPrivateMapping, testPublicMapping)
+
+	report, err := service.ReindexAll(context.Background(), domain.ReindexOptions{Force: true})
+
+	require.NoError(t, err)
+	assert.Len(t, report.ShrinkViolations, 2)
+	assert.Len(t, index.swapAliasCalls, 2)
+}
+
+func TestReindexAll_ShrinkGuard_DryRunReportsViolations(t *testing.T) {
+	index := shrinkIndex()
+	index.acquireLockFunc = failOnLock(t)
+	service := NewIndexerService(shrinkSource(), index, "private", "public", testPrivateMapping, testPublicMapping)
+
+	report, err := service.ReindexAll(context.Background(), domain.ReindexOptions{DryRun: true})
+
+	require.NoError(t, err)
+	assert.Len(t, report.ShrinkViolations, 2)
+	assertNothingWritten(t, index)
+}
+
+func TestReindexAll_ShrinkGuard_Disabled(t *testing.T) {
+	index := shrinkIndex()
+	service := NewIndexerService(shrinkSource(), index, "private", "public", testPrivateMapping, testPublicMapping)
+	service.SetShrinkThresholds(0, 0)
+
+	report, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+
+	require.NoError(t, err)
+	assert.Empty(t, report.ShrinkViolations)
+}
+
+func TestReindexAll_ShrinkGuard_SkippedConference(t *testing.T) {
+	source := shrinkSource()
+	source.getTalksFunc = func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+		if conferenceID == "conf-2" {
+			return nil, assert.AnError
+		}
+		return []domain.Talk{
+			{ID: "talk-1", ConferenceID: "conf-1", Status: "APPROVED"},
+			{ID: "talk-2", ConferenceID: "conf-1", Status: "APPROVED"},
+			{ID: "talk-3", ConferenceID: "conf-1", Status: "APPROVED"},
+			{ID: "talk-4", ConferenceID: "conf-1", Status: "APPROVED"},
+		}, nil
+	}
+	index := shrinkIndex()
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	service.SetShrinkThresholds(0.5, 0.3)
+
+	// The whole index only drops by a third, but conf-2 would vanish entirely
+	report, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+
+	require.ErrorIs(t, err, domain.ErrIndexShrink)
+	assert.Equal(t, []domain.ShrinkViolation{
+		{Index: "private", ConferenceID: "conf-2", Current: 2, New: 0, Threshold: 0.3},
+		{Index: "public", ConferenceID: "conf-2", Current: 2, New: 0, Threshold: 0.3},
+	}, report.ShrinkViolations)
+}
+
+func TestReindexConference_ShrinkGuard(t *testing.T) {
+	index := shrinkIndex()
+	service := NewIndexerService(shrinkSource(), index, "private", "public", testPrivateMapping, testPublicMapping)
+
+	t.Run("refuses", func(t *testing.T) {
+		report, err := service.ReindexConference(context.Background(), "javazone2024", domain.ReindexOptions{})
+
+		require.ErrorIs(t, err, domain.ErrIndexShrink)
+		assert.Equal(t, []domain.ShrinkViolation{
+			{Index: "public", ConferenceID: "conf-1", Current: 4, New: 1, Threshold: DefaultShrinkThreshold},
+		}, report.ShrinkViolations)
+		assert.Empty(t, index.bulkIndexCalls)
+	})
+
+	t.Run("other conferences are not checked", func(t *testing.T) {
+		_, err := service.ReindexConference(context.Background(), "javazone2023", domain.ReindexOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("force", func(t *testing.T) {
+		report, err := service.ReindexConference(context.Background(), "javazone2024", domain.ReindexOptions{Force: true})
+		require.NoError(t, err)
+		assert.Len(t, report.ShrinkViolations, 1)
+	})
+}
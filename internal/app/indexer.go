@@ -2,22 +2,46 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/javaBin/talks-indexer/internal/domain"
 	"github.com/javaBin/talks-indexer/internal/ports"
 )
 
-// IndexerService handles the business logic for indexing talks
+// DefaultIndexRetention is the number of previous index generations kept after a full reindex
+const DefaultIndexRetention = 2
+
+// generationTimeFormat is the timestamp suffix used for index generation names.
+// Milliseconds are appended separately so names stay purely numeric.
+const generationTimeFormat = "20060102150405"
+
+// IndexerService handles the business logic for indexing talks.
+// The configured private and public index names are used as aliases; a full
+// reindex builds a fresh timestamped index generation behind each alias and
+// swaps the alias over once indexing has succeeded.
 type IndexerService struct {
-	source              ports.TalkSource
-	searchIndex         ports.SearchIndex
-	privateIndex        string
-	publicIndex         string
-	privateIndexMapping string
-	publicIndexMapping  string
-	logger              *slog.Logger
+	source                    ports.TalkSource
+	searchIndex               ports.SearchIndex
+	privateIndex              string
+	publicIndex               string
+	privateIndexMapping       string
+	publicIndexMapping        string
+	retention                 int
+	stateIndex                string
+	lockTTL                   time.Duration
+	fetchConcurrency          int
+	fetchTimeout              time.Duration
+	indexShrinkThreshold      float64
+	conferenceShrinkThreshold float64
+	speakers                  *speakerIndexes
+	driftPolicy               domain.MappingDriftPolicy
+	now                       func() time.Time
+	logger                    *slog.Logger
 }
 
 // NewIndexerService creates a new IndexerService with the provided dependencies
@@ -30,21 +54,67 @@ func NewIndexerService(
 	publicIndexMapping string,
 ) *IndexerService {
 	return &IndexerService{
-		source:              source,
-		searchIndex:         searchIndex,
-		privateIndex:        privateIndex,
-		publicIndex:         publicIndex,
-		privateIndexMapping: privateIndexMapping,
-		publicIndexMapping:  publicIndexMapping,
-		logger:              slog.Default().With("component", "indexer"),
+		source:                    source,
+		searchIndex:               searchIndex,
+		privateIndex:              privateIndex,
+		publicIndex:               publicIndex,
+		privateIndexMapping:       privateIndexMapping,
+		publicIndexMapping:        publicIndexMapping,
+		retention:                 DefaultIndexRetention,
+		stateIndex:                DefaultStateIndex,
+		lockTTL:                   DefaultLockTTL,
+		fetchConcurrency:          DefaultFetchConcurrency,
+		fetchTimeout:              DefaultFetchTimeout,
+		indexShrinkThreshold:      DefaultShrinkThreshold,
+		conferenceShrinkThreshold: DefaultShrinkThreshold,
+		driftPolicy:               domain.MappingDriftWarn,
+		now:                       time.Now,
+		logger:                    slog.Default().With("component", "indexer"),
+	}
+}
+
+// SetIndexRetention sets how many previous index generations are kept for rollback
+// after a full reindex. Negative values are treated as zero.
+func (s *IndexerService) SetIndexRetention(retention int) {
+	if retention < 0 {
+		retention = 0
 	}
+	s.retention = retention
 }
 
 // ReindexAll fetches all conferences and their talks, then indexes them
 // to both private (all talks) and public (only approved talks) indexes.
-func (s *IndexerService) ReindexAll(ctx context.Context) error {
-	s.logger.Info("starting full reindex of all conferences")
+// Talks are written into new index generations; the private and public aliases
+// are only swapped over once both generations are fully indexed, so readers keep
+// seeing the previous data until the new data is complete. The speaker indexes, when
+// enabled, are rebuilt from the same talks and swapped after the talk indexes.
+// Conferences are fetched in parallel by a bounded worker pool; conferences whose
+// talks cannot be fetched are skipped and listed in the report.
+// With opts.DryRun nothing is written; the report instead holds the diff between the
+// fetched talks and the current contents of both indexes.
+// The reindex is refused if an index or conference would lose more documents than
+// the shrink thresholds allow, unless opts.Force is set.
+func (s *IndexerService) ReindexAll(ctx context.Context, opts domain.ReindexOptions) (_ *domain.ReindexReport, err error) {
+	s.logger.Info("starting full reindex of all conferences", "dryRun", opts.DryRun)
+
+	ctx, release, err := s.lockUnlessDryRun(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	defer lockLost(ctx, &err)
+
+	report := domain.NewReindexReport("reindex-all", "", s.now())
+	report.DryRun = opts.DryRun
+	defer func() { report.Finish(s.now()) }()
+
+	return report, s.reindexAll(ctx, opts, report)
+}
 
+// reindexAll runs a full reindex into report while the caller holds the indexing lock.
+// Fresh generations are always created with the compiled mappings, so it also migrates
+// indexes whose mappings have drifted.
+func (s *IndexerService) reindexAll(ctx context.Context, opts domain.ReindexOptions, report *domain.ReindexReport) error {
 	// Fetch all conferences
 	conferences, err := s.source.GetConferences(ctx)
 	if err != nil {
@@ -53,47 +123,71 @@ func (s *IndexerService) ReindexAll(ctx context.Context) error {
 
 	s.logger.Info("fetched conferences", "count", len(conferences))
 
-	// Recreate both indexes
-	if err := s.recreateIndex(ctx, s.privateIndex); err != nil {
-		return fmt.Errorf("failed to recreate private index: %w", err)
-	}
-	if err := s.recreateIndex(ctx, s.publicIndex); err != nil {
-		return fmt.Errorf("failed to recreate public index: %w", err)
+	// Fetch the talks of all conferences in parallel; results keep the conference order.
+	// Cancellation stops here, before any empty generations are created.
+	results, err := s.fetchConferenceTalks(ctx, conferences)
+	if err != nil {
+		return err
 	}
 
 	// Collect all talks from all conferences
 	var allTalks []domain.Talk
 
-	for _, conf := range conferences {
-		talks, err := s.source.GetTalks(ctx, conf.ID)
-		if err != nil {
-			s.logger.Error("failed to fetch talks for conference",
-				"conferenceID", conf.ID,
-				"conferenceName", conf.Name,
-				"error", err,
-			)
+	for _, result := range results {
+		conf := result.conference
+		if result.err != nil {
+			report.Skip(conf, result.err)
 			continue
 		}
 
-		s.logger.Info("fetched talks for conference",
-			"conferenceID", conf.ID,
-			"conferenceName", conf.Name,
-			"count", len(talks),
-		)
-
-		allTalks = append(allTalks, talks...)
+		report.AddConference(domain.ConferenceReport{
+			ConferenceID:   conf.ID,
+			ConferenceName: conf.Name,
+			Slug:           conf.Slug,
+			PrivateCount:   len(result.talks),
+			PublicCount:    countPublicTalks(result.talks),
+		})
+		allTalks = append(allTalks, result.talks...)
 	}
 
 	if len(allTalks) == 0 {
 		s.logger.Warn("no talks found to index")
+	}
+
+	// Refuse to publish indexes that lost a suspicious share of their documents,
+	// which usually means the source returned incomplete data
+	if err := s.guardShrink(ctx, "", allTalks, opts, report); err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		if _, _, err := s.dryRunIndexes(ctx, "", allTalks, report); err != nil {
+			return err
+		}
+		s.logger.Info("full reindex dry run completed", "privateCount", len(allTalks))
 		return nil
 	}
 
+	// Build new generations for both indexes
+	privateGeneration := s.generationName(s.privateIndex)
+	publicGeneration := s.generationName(s.publicIndex)
+
+	if err := s.createGeneration(ctx, s.privateIndex, privateGeneration); err != nil {
+		return fmt.Errorf("failed to create private index: %w", err)
+	}
+	if err := s.createGeneration(ctx, s.publicIndex, publicGeneration); err != nil {
+		s.discardGenerations(ctx, privateGeneration)
+		return fmt.Errorf("failed to create public index: %w", err)
+	}
+
 	// Index all talks to private index (with privateData merged into data)
 	privateTalks := prepareTalksForPrivateIndex(allTalks)
-	if err := s.searchIndex.BulkIndex(ctx, s.privateIndex, privateTalks); err != nil {
+	privateResult, err := s.searchIndex.BulkIndex(ctx, privateGeneration, privateTalks)
+	if err != nil {
+		s.discardGenerations(ctx, privateGeneration, publicGeneration)
 		return fmt.Errorf("failed to index to private index: %w", err)
 	}
+	report.AddBulkResult(privateResult)
 
 	// Filter approved talks for public index (with private data removed)
 	publicTalks := filterApprovedTalksForPublic(allTalks)
@@ -104,27 +198,89 @@ func (s *IndexerService) ReindexAll(ctx context.Context) error {
 	)
 
 	// Index approved talks to public index
-	if err := s.searchIndex.BulkIndex(ctx, s.publicIndex, publicTalks); err != nil {
+	publicResult, err := s.searchIndex.BulkIndex(ctx, publicGeneration, publicTalks)
+	if err != nil {
+		s.discardGenerations(ctx, privateGeneration, publicGeneration)
 		return fmt.Errorf("failed to index to public index: %w", err)
 	}
+	report.AddBulkResult(publicResult)
+
+	s.refresh(ctx, privateGeneration, publicGeneration)
+
+	speakerGenerations, err := s.buildSpeakerGenerations(ctx, privateTalks, publicTalks, report)
+	if err != nil {
+		s.discardGenerations(ctx, privateGeneration, publicGeneration)
+		return err
+	}
+
+	// Both generations are complete - point the aliases at them
+	if err := s.searchIndex.SwapAlias(ctx, s.privateIndex, privateGeneration); err != nil {
+		s.discardGenerations(ctx, privateGeneration, publicGeneration)
+		s.discardSpeakerGenerations(ctx, speakerGenerations)
+		return fmt.Errorf("failed to swap private index alias: %w", err)
+	}
+	if err := s.searchIndex.SwapAlias(ctx, s.publicIndex, publicGeneration); err != nil {
+		// The private alias already points at the new generation, so only the public one is discarded
+		s.discardGenerations(ctx, publicGeneration)
+		s.discardSpeakerGenerations(ctx, speakerGenerations)
+		return fmt.Errorf("failed to swap public index alias: %w", err)
+	}
 
-	s.logger.Info("full reindex completed successfully",
+	s.pruneGenerations(ctx, s.privateIndex, privateGeneration)
+	s.pruneGenerations(ctx, s.publicIndex, publicGeneration)
+
+	// The talk indexes are already published, so a failure here leaves the previous
+	// speaker indexes in place and makes the run partial rather than failed
+	if err := s.publishSpeakerGenerations(ctx, speakerGenerations); err != nil {
+		s.logger.Error("failed to publish speaker indexes", "error", err)
+		report.Warn(fmt.Sprintf("speaker indexes were not updated: %v", err))
+	}
+
+	s.logger.Info("full reindex completed",
 		"privateCount", len(allTalks),
 		"publicCount", len(publicTalks),
+		"skippedConferences", len(report.Skipped),
+		"bulkFailures", len(report.BulkFailures),
+		"warnings", len(report.Warnings),
+		"privateGeneration", privateGeneration,
+		"publicGeneration", publicGeneration,
 	)
 
 	return nil
 }
 
 // ReindexConference reindexes talks for a specific conference by its slug.
-// It updates both private and public indexes for that conference's talks.
-func (s *IndexerService) ReindexConference(ctx context.Context, slug string) error {
-	s.logger.Info("starting reindex for conference", "slug", slug)
+// It updates both private and public indexes for that conference's talks and the
+// speaker profiles of their past and present speakers, and like
+// ReindexAll refuses to shrink the conference beyond its threshold unless forced.
+// With opts.DryRun nothing is written and the report holds the diff instead.
+// Indexes with drifted mappings are handled according to the mapping drift policy.
+func (s *IndexerService) ReindexConference(ctx context.Context, slug string, opts domain.ReindexOptions) (_ *domain.ReindexReport, err error) {
+	s.logger.Info("starting reindex for conference", "slug", slug, "dryRun", opts.DryRun)
+
+	ctx, release, err := s.lockUnlessDryRun(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	defer lockLost(ctx, &err)
+
+	report := domain.NewReindexReport("reindex-conference", slug, s.now())
+	report.DryRun = opts.DryRun
+	defer func() { report.Finish(s.now()) }()
+
+	rebuild, err := s.guardMappings(ctx, opts, report)
+	if err != nil {
+		return report, err
+	}
+	if rebuild {
+		return report, s.reindexAll(ctx, opts, report)
+	}
 
 	// Find the conference by slug
 	conferences, err := s.source.GetConferences(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to fetch conferences: %w", err)
+		return report, fmt.Errorf("failed to fetch conferences: %w", err)
 	}
 
 	var targetConference *domain.Conference
@@ -136,13 +292,16 @@ func (s *IndexerService) ReindexConference(ctx context.Context, slug string) err
 	}
 
 	if targetConference == nil {
-		return fmt.Errorf("conference not found with slug: %s", slug)
+		return report, fmt.Errorf("conference not found with slug: %s", slug)
 	}
 
+	reportProgress(ctx, conferenceProgress(*targetConference, domain.ProgressRunning, 0, nil))
+
 	// Fetch talks for this conference
 	talks, err := s.source.GetTalks(ctx, targetConference.ID)
 	if err != nil {
-		return fmt.Errorf("failed to fetch talks for conference %s: %w", slug, err)
+		reportProgress(ctx, conferenceProgress(*targetConference, domain.ProgressFailed, 0, err))
+		return report, fmt.Errorf("failed to fetch talks for conference %s: %w", slug, err)
 	}
 
 	s.logger.Info("fetched talks for conference",
@@ -151,46 +310,164 @@ func (s *IndexerService) ReindexConference(ctx context.Context, slug string) err
 		"count", len(talks),
 	)
 
+	if err := s.guardShrink(ctx, targetConference.ID, talks, opts, report); err != nil {
+		reportProgress(ctx, conferenceProgress(*targetConference, domain.ProgressFailed, len(talks), err))
+		return report, err
+	}
+
+	if opts.DryRun {
+		privateDiff, publicDiff, err := s.dryRunIndexes(ctx, targetConference.ID, talks, report)
+		if err != nil {
+			reportProgress(ctx, conferenceProgress(*targetConference, domain.ProgressFailed, len(talks), err))
+			return report, err
+		}
+		report.AddConference(domain.ConferenceReport{
+			ConferenceID:   targetConference.ID,
+			ConferenceName: targetConference.Name,
+			Slug:           targetConference.Slug,
+			PrivateCount:   len(talks),
+			PublicCount:    countPublicTalks(talks),
+			PrivateDeleted: len(privateDiff.Deleted),
+			PublicDeleted:  len(publicDiff.Deleted),
+		})
+		reportProgress(ctx, conferenceProgress(*targetConference, domain.ProgressDone, len(talks), nil))
+		s.logger.Info("conference reindex dry run completed", "slug", slug)
+		return report, nil
+	}
+
 	// Ensure indexes exist
 	if err := s.ensureIndexExists(ctx, s.privateIndex); err != nil {
-		return fmt.Errorf("failed to ensure private index exists: %w", err)
+		return report, fmt.Errorf("failed to ensure private index exists: %w", err)
 	}
 	if err := s.ensureIndexExists(ctx, s.publicIndex); err != nil {
-		return fmt.Errorf("failed to ensure public index exists: %w", err)
+		return report, fmt.Errorf("failed to ensure public index exists: %w", err)
+	}
+
+	// Speakers of talks about to be replaced or removed need their profiles updated too
+	previousSpeakers, err := s.indexedSpeakerIDs(ctx, targetConference.ID)
+	if err != nil {
+		return report, err
 	}
 
 	// Index all talks to private index (with privateData merged into data)
 	privateTalks := prepareTalksForPrivateIndex(talks)
-	if err := s.searchIndex.BulkIndex(ctx, s.privateIndex, privateTalks); err != nil {
-		return fmt.Errorf("failed to index to private index: %w", err)
+	privateResult, err := s.searchIndex.BulkIndex(ctx, s.privateIndex, privateTalks)
+	if err != nil {
+		return report, fmt.Errorf("failed to index to private index: %w", err)
 	}
+	report.AddBulkResult(privateResult)
 
 	// Filter approved talks for public index (with private data removed)
 	publicTalks := filterApprovedTalksForPublic(talks)
 
 	// Index approved talks to public index
-	if err := s.searchIndex.BulkIndex(ctx, s.publicIndex, publicTalks); err != nil {
-		return fmt.Errorf("failed to index to public index: %w", err)
+	publicResult, err := s.searchIndex.BulkIndex(ctx, s.publicIndex, publicTalks)
+	if err != nil {
+		return report, fmt.Errorf("failed to index to public index: %w", err)
+	}
+	report.AddBulkResult(publicResult)
+
+	// Remove talks that were deleted in the source or are no longer approved
+	privateDeleted, publicDeleted, err := s.removeStaleTalks(ctx, targetConference.ID, talks)
+	if err != nil {
+		return report, err
 	}
 
-	s.logger.Info("conference reindex completed successfully",
+	s.refresh(ctx, s.privateIndex, s.publicIndex)
+
+	if err := s.updateSpeakers(ctx, append(previousSpeakers, domain.SpeakerIDs(talks)...), report); err != nil {
+		return report, err
+	}
+
+	report.AddConference(domain.ConferenceReport{
+		ConferenceID:   targetConference.ID,
+		ConferenceName: targetConference.Name,
+		Slug:           targetConference.Slug,
+		PrivateCount:   len(talks),
+		PublicCount:    len(publicTalks),
+		PrivateDeleted: privateDeleted,
+		PublicDeleted:  publicDeleted,
+	})
+	reportProgress(ctx, conferenceProgress(*targetConference, domain.ProgressDone, len(talks), nil))
+
+	s.logger.Info("conference reindex completed",
 		"slug", slug,
 		"privateCount", len(talks),
 		"publicCount", len(publicTalks),
+		"privateDeleted", privateDeleted,
+		"publicDeleted", publicDeleted,
+		"bulkFailures", len(report.BulkFailures),
 	)
 
-	return nil
+	return report, nil
 }
 
 // ReindexTalk reindexes a specific talk by its ID.
-// It fetches the talk directly and updates both indexes.
-func (s *IndexerService) ReindexTalk(ctx context.Context, talkID string) error {
-	s.logger.Info("starting reindex for talk", "talkID", talkID)
+// It fetches the talk directly and updates both indexes. A talk that no longer
+// exists in the source is removed from both indexes, and a talk that is not
+// approved is removed from the public index. The profiles of the talk's past and
+// present speakers are updated.
+// With opts.DryRun nothing is written and the report holds the diff instead.
+// Indexes with drifted mappings are handled according to the mapping drift policy.
+func (s *IndexerService) ReindexTalk(ctx context.Context, talkID string, opts domain.ReindexOptions) (_ *domain.ReindexReport, err error) {
+	s.logger.Info("starting reindex for talk", "talkID", talkID, "dryRun", opts.DryRun)
+
+	ctx, release, err := s.lockUnlessDryRun(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	defer lockLost(ctx, &err)
+
+	report := domain.NewReindexReport("reindex-talk", talkID, s.now())
+	report.DryRun = opts.DryRun
+	defer func() { report.Finish(s.now()) }()
+
+	rebuild, err := s.guardMappings(ctx, opts, report)
+	if err != nil {
+		return report, err
+	}
+	if rebuild {
+		return report, s.reindexAll(ctx, opts, report)
+	}
 
 	// Fetch the talk directly by ID
 	targetTalk, err := s.source.GetTalk(ctx, talkID)
+	notFound := errors.Is(err, domain.ErrNotFound)
+	if err != nil && !notFound {
+		return report, fmt.Errorf("failed to fetch talk %s: %w", talkID, err)
+	}
+
+	if opts.DryRun {
+		if notFound {
+			targetTalk = nil
+		}
+		if err := s.dryRunTalk(ctx, talkID, targetTalk, report); err != nil {
+			return report, err
+		}
+		s.logger.Info("talk reindex dry run completed", "talkID", talkID)
+		return report, nil
+	}
+
+	// Speakers the talk had before this reindex need their profiles updated too
+	previousSpeakers, err := s.indexedTalkSpeakerIDs(ctx, talkID)
 	if err != nil {
-		return fmt.Errorf("failed to fetch talk %s: %w", talkID, err)
+		return report, err
+	}
+
+	if notFound {
+		privateDeleted, publicDeleted, err := s.removeTalk(ctx, talkID)
+		if err != nil {
+			return report, err
+		}
+		s.refresh(ctx, s.privateIndex, s.publicIndex)
+		if err := s.updateSpeakers(ctx, previousSpeakers, report); err != nil {
+			return report, err
+		}
+		report.PrivateDeleted = privateDeleted
+		report.PublicDeleted = publicDeleted
+		s.logger.Info("talk no longer exists, removed from indexes", "talkID", talkID)
+		return report, nil
 	}
 
 	s.logger.Info("fetched talk",
@@ -200,80 +477,211 @@ func (s *IndexerService) ReindexTalk(ctx context.Context, talkID string) error {
 
 	// Ensure indexes exist
 	if err := s.ensureIndexExists(ctx, s.privateIndex); err != nil {
-		return fmt.Errorf("failed to ensure private index exists: %w", err)
+		return report, fmt.Errorf("failed to ensure private index exists: %w", err)
 	}
 	if err := s.ensureIndexExists(ctx, s.publicIndex); err != nil {
-		return fmt.Errorf("failed to ensure public index exists: %w", err)
+		return report, fmt.Errorf("failed to ensure public index exists: %w", err)
+	}
+
+	conferenceReport := domain.ConferenceReport{
+		ConferenceID: targetTalk.ConferenceID,
+		Slug:         targetTalk.ConferenceSlug,
+		PrivateCount: 1,
 	}
 
 	// Index to private index (with privateData merged into data)
 	privateTalk := targetTalk.ToPrivate()
-	if err := s.searchIndex.BulkIndex(ctx, s.privateIndex, []domain.Talk{privateTalk}); err != nil {
-		return fmt.Errorf("failed to index to private index: %w", err)
+	privateResult, err := s.searchIndex.BulkIndex(ctx, s.privateIndex, []domain.Talk{privateTalk})
+	if err != nil {
+		return report, fmt.Errorf("failed to index to private index: %w", err)
 	}
+	report.AddBulkResult(privateResult)
 
 	// Index to public index only if the talk status is public
 	if domain.TalkStatus(targetTalk.Status).IsPublic() {
 		publicTalk := targetTalk.ToPublic()
-		if err := s.searchIndex.BulkIndex(ctx, s.publicIndex, []domain.Talk{publicTalk}); err != nil {
-			return fmt.Errorf("failed to index to public index: %w", err)
+		publicResult, err := s.searchIndex.BulkIndex(ctx, s.publicIndex, []domain.Talk{publicTalk})
+		if err != nil {
+			return report, fmt.Errorf("failed to index to public index: %w", err)
 		}
-		s.logger.Info("talk reindex completed successfully",
+		report.AddBulkResult(publicResult)
+		conferenceReport.PublicCount = 1
+		s.logger.Info("talk reindex completed",
 			"talkID", talkID,
 			"indexedToPublic", true,
 		)
 	} else {
-		s.logger.Info("talk reindex completed successfully",
+		publicDeleted, err := s.searchIndex.DeleteDocuments(ctx, s.publicIndex, []string{talkID})
+		if err != nil {
+			return report, fmt.Errorf("failed to remove talk from public index: %w", err)
+		}
+		conferenceReport.PublicDeleted = publicDeleted
+		s.logger.Info("talk reindex completed",
 			"talkID", talkID,
 			"indexedToPublic", false,
 			"status", targetTalk.Status,
 		)
 	}
 
+	s.refresh(ctx, s.privateIndex, s.publicIndex)
+
+	if err := s.updateSpeakers(ctx, append(previousSpeakers, domain.SpeakerIDs([]domain.Talk{*targetTalk})...), report); err != nil {
+		return report, err
+	}
+
+	report.AddConference(conferenceReport)
+	return report, nil
+}
+
+// ensureIndexExists makes sure the alias resolves to an index, creating a first
+// generation behind it if nothing exists yet
+func (s *IndexerService) ensureIndexExists(ctx context.Context, alias string) error {
+	exists, err := s.searchIndex.IndexExists(ctx, alias)
+	if err != nil {
+		return fmt.Errorf("failed to check if index exists: %w", err)
+	}
+
+	if !exists {
+		generation := s.generationName(alias)
+		if err := s.createGeneration(ctx, alias, generation); err != nil {
+			return err
+		}
+		if err := s.searchIndex.SwapAlias(ctx, alias, generation); err != nil {
+			return fmt.Errorf("failed to point alias %s at %s: %w", alias, generation, err)
+		}
+	}
+
 	return nil
 }
 
-// recreateIndex deletes and recreates an index with the appropriate mapping
-func (s *IndexerService) recreateIndex(ctx context.Context, indexName string) error {
-	// Delete the index if it exists
-	if err := s.searchIndex.DeleteIndex(ctx, indexName); err != nil {
-		return fmt.Errorf("failed to delete index %s: %w", indexName, err)
+// refresh makes the writes of a run visible to search once the run is done, instead of
+// refreshing on every request. A failed refresh is only logged since the writes still
+// become visible with the index's refresh interval.
+func (s *IndexerService) refresh(ctx context.Context, indexNames ...string) {
+	if err := s.searchIndex.Refresh(ctx, indexNames...); err != nil {
+		s.logger.Warn("failed to refresh indexes", "indexes", indexNames, "error", err)
 	}
+}
 
-	// Create the index with the appropriate mapping
-	mapping := s.getMappingForIndex(indexName)
-	if err := s.searchIndex.CreateIndex(ctx, indexName, mapping); err != nil {
-		return fmt.Errorf("failed to create index %s: %w", indexName, err)
+// generationName returns a new timestamped index name for the given alias
+func (s *IndexerService) generationName(alias string) string {
+	now := s.now().UTC()
+	return fmt.Sprintf("%s_%s%03d", alias, now.Format(generationTimeFormat), now.Nanosecond()/int(time.Millisecond))
+}
+
+// isGenerationOf reports whether indexName is a generation created for the given alias
+func isGenerationOf(alias, indexName string) bool {
+	suffix, ok := strings.CutPrefix(indexName, alias+"_")
+	if !ok || len(suffix) != len(generationTimeFormat)+3 {
+		return false
 	}
+	for _, r := range suffix {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
 
+// createGeneration creates a new index generation with the mapping for the given alias
+func (s *IndexerService) createGeneration(ctx context.Context, alias, generation string) error {
+	mapping := s.getMappingForIndex(alias)
+	if err := s.searchIndex.CreateIndex(ctx, generation, mapping); err != nil {
+		return fmt.Errorf("failed to create index %s: %w", generation, err)
+	}
 	return nil
 }
 
-// ensureIndexExists creates the index if it doesn't exist
-func (s *IndexerService) ensureIndexExists(ctx context.Context, indexName string) error {
-	exists, err := s.searchIndex.IndexExists(ctx, indexName)
+// discardGenerations deletes partially built generations after a failed reindex.
+// Failures are only logged since the original error is more relevant to the caller.
+func (s *IndexerService) discardGenerations(ctx context.Context, generations ...string) {
+	for _, generation := range generations {
+		if err := s.searchIndex.DeleteIndex(ctx, generation); err != nil {
+			s.logger.Error("failed to discard index generation",
+				"index", generation,
+				"error", err,
+			)
+		}
+	}
+}
+
+// pruneGenerations deletes old generations of an alias, keeping the current one
+// and the configured number of previous generations for rollback
+func (s *IndexerService) pruneGenerations(ctx context.Context, alias, current string) {
+	indices, err := s.searchIndex.ListIndices(ctx, alias+"_*")
 	if err != nil {
-		return fmt.Errorf("failed to check if index exists: %w", err)
+		s.logger.Error("failed to list index generations", "alias", alias, "error", err)
+		return
 	}
 
-	if !exists {
-		mapping := s.getMappingForIndex(indexName)
-		if err := s.searchIndex.CreateIndex(ctx, indexName, mapping); err != nil {
-			return fmt.Errorf("failed to create index %s: %w", indexName, err)
+	var previous []string
+	for _, indexName := range indices {
+		if indexName != current && isGenerationOf(alias, indexName) {
+			previous = append(previous, indexName)
 		}
 	}
 
-	return nil
+	// Newest first - the timestamp suffix sorts lexically
+	sort.Sort(sort.Reverse(sort.StringSlice(previous)))
+
+	if len(previous) <= s.retention {
+		return
+	}
+
+	for _, indexName := range previous[s.retention:] {
+		if err := s.searchIndex.DeleteIndex(ctx, indexName); err != nil {
+			s.logger.Error("failed to delete old index generation",
+				"alias", alias,
+				"index", indexName,
+				"error", err,
+			)
+			continue
+		}
+		s.logger.Info("deleted old index generation", "alias", alias, "index", indexName)
+	}
 }
 
-// getMappingForIndex returns the appropriate mapping for the given index name
-func (s *IndexerService) getMappingForIndex(indexName string) string {
-	if indexName == s.privateIndex {
+// getMappingForIndex returns the appropriate mapping for the given alias
+func (s *IndexerService) getMappingForIndex(alias string) string {
+	if s.speakers != nil {
+		switch alias {
+		case s.speakers.privateIndex:
+			return s.speakers.privateMapping
+		case s.speakers.publicIndex:
+			return s.speakers.publicMapping
+		}
+	}
+	if alias == s.privateIndex {
 		return s.privateIndexMapping
 	}
 	return s.publicIndexMapping
 }
 
+// conferenceProgress builds a progress update for a conference
+func conferenceProgress(conf domain.Conference, state domain.ProgressState, talks int, err error) domain.ConferenceProgress {
+	progress := domain.ConferenceProgress{
+		ConferenceID:   conf.ID,
+		ConferenceName: conf.Name,
+		State:          state,
+		Talks:          talks,
+	}
+	if err != nil {
+		progress.Error = err.Error()
+	}
+	return progress
+}
+
+// countPublicTalks returns the number of talks that belong in the public index
+func countPublicTalks(talks []domain.Talk) int {
+	count := 0
+	for _, talk := range talks {
+		if domain.TalkStatus(talk.Status).IsPublic() {
+			count++
+		}
+	}
+	return count
+}
+
 // prepareTalksForPrivateIndex returns talks with privateData merged into data
 func prepareTalksForPrivateIndex(talks []domain.Talk) []domain.Talk {
 	result := make([]domain.Talk, len(talks))
@@ -2,8 +2,14 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/javaBin/talks-indexer/internal/domain"
 	"github.com/stretchr/testify/assert"
@@ -45,13 +51,52 @@ func (m *mockTalkSource) GetTalk(ctx context.Context, talkID string) (*domain.Ta
 
 // mockSearchIndex is a mock implementation of ports.SearchIndex
 type mockSearchIndex struct {
-	bulkIndexFunc    func(ctx context.Context, indexName string, talks []domain.Talk) error
+	bulkIndexFunc    func(ctx context.Context, indexName string, talks []domain.Talk) (*domain.BulkResult, error)
 	deleteIndexFunc  func(ctx context.Context, indexName string) error
 	createIndexFunc  func(ctx context.Context, indexName string, mapping string) error
 	indexExistsFunc  func(ctx context.Context, indexName string) (bool, error)
+	swapAliasFunc    func(ctx context.Context, alias string, indexName string) error
+	listIndicesFunc  func(ctx context.Context, pattern string) ([]string, error)
+	lastUpdatedFunc  func(ctx context.Context, indexName string, conferenceID string) (map[string]time.Time, error)
+	deleteStaleFunc  func(ctx context.Context, indexName string, conferenceID string, keepIDs []string) (int, error)
+	deleteDocsFunc   func(ctx context.Context, indexName string, ids []string) (int, error)
+	countFunc        func(ctx context.Context, indexName string) (map[string]int, error)
+	searchFunc       func(ctx context.Context, indexName string, query domain.SearchQuery) (*domain.SearchResult, error)
+	facetsFunc       func(ctx context.Context, indexName string, query domain.SearchQuery) (*domain.FacetResult, error)
+	suggestFunc      func(ctx context.Context, indexName string, query domain.SuggestQuery) (*domain.Suggestions, error)
+	acquireLockFunc  func(ctx context.Context, indexName string, lockID string, owner string, ttl time.Duration) (bool, error)
+	speakerTalksFunc func(ctx context.Context, indexName string, speakerIDs []string) ([]domain.Talk, error)
+	checkMappingFunc func(ctx context.Context, indexName string, mapping string) (*domain.MappingDrift, error)
+	exportIndexFunc  func(ctx context.Context, indexName string, w io.Writer) (*domain.SnapshotHeader, error)
+	importIndexFunc  func(ctx context.Context, indexName string, r io.Reader) (*domain.SnapshotResult, error)
+	documents        map[string]json.RawMessage
+	lockMu           sync.Mutex
+	locks            map[string]string // lock ID to owner
+	releasedLocks    []string
 	bulkIndexCalls   []bulkIndexCall
+	speakerCalls     []speakerCall
+	deleteDocsCalls  []deleteDocsCall
+	deleteStaleCalls []deleteStaleCall
 	deleteIndexCalls []string
 	createIndexCalls []string
+	swapAliasCalls   []swapAliasCall
+	refreshCalls     [][]string
+}
+
+type deleteDocsCall struct {
+	IndexName string
+	IDs       []string
+}
+
+type deleteStaleCall struct {
+	IndexName    string
+	ConferenceID string
+	KeepIDs      []string
+}
+
+type swapAliasCall struct {
+	Alias     string
+	IndexName string
 }
 
 type bulkIndexCall struct {
@@ -59,11 +104,33 @@ type bulkIndexCall struct {
 	Talks     []domain.Talk
 }
 
-func (m *mockSearchIndex) BulkIndex(ctx context.Context, indexName string, talks []domain.Talk) error {
+func (m *mockSearchIndex) BulkIndex(ctx context.Context, indexName string, talks []domain.Talk) (*domain.BulkResult, error) {
 	m.bulkIndexCalls = append(m.bulkIndexCalls, bulkIndexCall{IndexName: indexName, Talks: talks})
 	if m.bulkIndexFunc != nil {
 		return m.bulkIndexFunc(ctx, indexName, talks)
 	}
+	return &domain.BulkResult{Indexed: len(talks)}, nil
+}
+
+type speakerCall struct {
+	IndexName string
+	Speakers  []domain.SpeakerProfile
+}
+
+func (m *mockSearchIndex) BulkIndexSpeakers(ctx context.Context, indexName string, speakers []domain.SpeakerProfile) (*domain.BulkResult, error) {
+	m.speakerCalls = append(m.speakerCalls, speakerCall{IndexName: indexName, Speakers: speakers})
+	return &domain.BulkResult{Indexed: len(speakers)}, nil
+}
+
+func (m *mockSearchIndex) FindTalksBySpeakers(ctx context.Context, indexName string, speakerIDs []string) ([]domain.Talk, error) {
+	if m.speakerTalksFunc != nil {
+		return m.speakerTalksFunc(ctx, indexName, speakerIDs)
+	}
+	return nil, nil
+}
+
+func (m *mockSearchIndex) Refresh(ctx context.Context, indexNames ...string) error {
+	m.refreshCalls = append(m.refreshCalls, indexNames)
 	return nil
 }
 
@@ -83,6 +150,13 @@ func (m *mockSearchIndex) CreateIndex(ctx context.Context, indexName string, map
 	return nil
 }
 
+func (m *mockSearchIndex) CheckMapping(ctx context.Context, indexName string, mapping string) (*domain.MappingDrift, error) {
+	if m.checkMappingFunc != nil {
+		return m.checkMappingFunc(ctx, indexName, mapping)
+	}
+	return nil, nil
+}
+
 func (m *mockSearchIndex) IndexExists(ctx context.Context, indexName string) (bool, error) {
 	if m.indexExistsFunc != nil {
 		return m.indexExistsFunc(ctx, indexName)
@@ -90,6 +164,181 @@ func (m *mockSearchIndex) IndexExists(ctx context.Context, indexName string) (bo
 	return true, nil
 }
 
+func (m *mockSearchIndex) GetAliasTargets(ctx context.Context, alias string) ([]string, error) {
+	var targets []string
+	for _, call := range m.swapAliasCalls {
+		if call.Alias == alias {
+			targets = []string{call.IndexName}
+		}
+	}
+	return targets, nil
+}
+
+func (m *mockSearchIndex) SwapAlias(ctx context.Context, alias string, indexName string) error {
+	m.swapAliasCalls = append(m.swapAliasCalls, swapAliasCall{Alias: alias, IndexName: indexName})
+	if m.swapAliasFunc != nil {
+		return m.swapAliasFunc(ctx, alias, indexName)
+	}
+	return nil
+}
+
+func (m *mockSearchIndex) ListIndices(ctx context.Context, pattern string) ([]string, error) {
+	if m.listIndicesFunc != nil {
+		return m.listIndicesFunc(ctx, pattern)
+	}
+	return []string{}, nil
+}
+
+func (m *mockSearchIndex) GetLastUpdated(ctx context.Context, indexName string, conferenceID string) (map[string]time.Time, error) {
+	if m.lastUpdatedFunc != nil {
+		return m.lastUpdatedFunc(ctx, indexName, conferenceID)
+	}
+	return map[string]time.Time{}, nil
+}
+
+func (m *mockSearchIndex) ScanDocuments(ctx context.Context, indexName string, conferenceID string) (map[string]map[string]interface{}, error) {
+	result := make(map[string]map[string]interface{})
+	for key, raw := range m.documents {
+		id, ok := strings.CutPrefix(key, indexName+"/")
+		if !ok {
+			continue
+		}
+		var source map[string]interface{}
+		if err := json.Unmarshal(raw, &source); err != nil {
+			return nil, err
+		}
+		if conferenceID != "" && source["conferenceId"] != conferenceID {
+			continue
+		}
+		result[id] = source
+	}
+	return result, nil
+}
+
+func (m *mockSearchIndex) CountByConference(ctx context.Context, indexName string) (map[string]int, error) {
+	if m.countFunc != nil {
+		return m.countFunc(ctx, indexName)
+	}
+	return map[string]int{}, nil
+}
+
+func (m *mockSearchIndex) Search(ctx context.Context, indexName string, query domain.SearchQuery) (*domain.SearchResult, error) {
+	if m.searchFunc != nil {
+		return m.searchFunc(ctx, indexName, query)
+	}
+	return &domain.SearchResult{Page: query.Page, Size: query.Size, Hits: []domain.SearchHit{}}, nil
+}
+
+func (m *mockSearchIndex) Facets(ctx context.Context, indexName string, query domain.SearchQuery) (*domain.FacetResult, error) {
+	if m.facetsFunc != nil {
+		return m.facetsFunc(ctx, indexName, query)
+	}
+	return &domain.FacetResult{Facets: []domain.Facet{}}, nil
+}
+
+func (m *mockSearchIndex) Suggest(ctx context.Context, indexName string, query domain.SuggestQuery) (*domain.Suggestions, error) {
+	if m.suggestFunc != nil {
+		return m.suggestFunc(ctx, indexName, query)
+	}
+	return &domain.Suggestions{}, nil
+}
+
+func (m *mockSearchIndex) GetDocument(ctx context.Context, indexName string, id string, v interface{}) (bool, error) {
+	raw, ok := m.documents[indexName+"/"+id]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(raw, v)
+}
+
+func (m *mockSearchIndex) PutDocument(ctx context.Context, indexName string, id string, doc interface{}) error {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if m.documents == nil {
+		m.documents = make(map[string]json.RawMessage)
+	}
+	m.documents[indexName+"/"+id] = raw
+	return nil
+}
+
+func (m *mockSearchIndex) DeleteDocuments(ctx context.Context, indexName string, ids []string) (int, error) {
+	if len(ids) > 0 {
+		m.deleteDocsCalls = append(m.deleteDocsCalls, deleteDocsCall{IndexName: indexName, IDs: ids})
+	}
+	if m.deleteDocsFunc != nil {
+		return m.deleteDocsFunc(ctx, indexName, ids)
+	}
+	return len(ids), nil
+}
+
+func (m *mockSearchIndex) DeleteConferenceTalksExcept(ctx context.Context, indexName string, conferenceID string, keepIDs []string) (int, error) {
+	m.deleteStaleCalls = append(m.deleteStaleCalls, deleteStaleCall{IndexName: indexName, ConferenceID: conferenceID, KeepIDs: keepIDs})
+	if m.deleteStaleFunc != nil {
+		return m.deleteStaleFunc(ctx, indexName, conferenceID, keepIDs)
+	}
+	return 0, nil
+}
+
+func (m *mockSearchIndex) ExportIndex(ctx context.Context, indexName string, w io.Writer) (*domain.SnapshotHeader, error) {
+	if m.exportIndexFunc != nil {
+		return m.exportIndexFunc(ctx, indexName, w)
+	}
+	return &domain.SnapshotHeader{Index: indexName}, nil
+}
+
+func (m *mockSearchIndex) ImportIndex(ctx context.Context, indexName string, r io.Reader) (*domain.SnapshotResult, error) {
+	if m.importIndexFunc != nil {
+		return m.importIndexFunc(ctx, indexName, r)
+	}
+	return &domain.SnapshotResult{Index: indexName}, nil
+}
+
+func (m *mockSearchIndex) AcquireLock(ctx context.Context, indexName string, lockID string, owner string, ttl time.Duration) (bool, error) {
+	if m.acquireLockFunc != nil {
+		return m.acquireLockFunc(ctx, indexName, lockID, owner, ttl)
+	}
+
+	m.lockMu.Lock()
+	defer m.lockMu.Unlock()
+
+	if holder, held := m.locks[lockID]; held && holder != owner {
+		return false, nil
+	}
+	if m.locks == nil {
+		m.locks = make(map[string]string)
+	}
+	m.locks[lockID] = owner
+	return true, nil
+}
+
+func (m *mockSearchIndex) ReleaseLock(ctx context.Context, indexName string, lockID string, owner string) error {
+	m.lockMu.Lock()
+	defer m.lockMu.Unlock()
+
+	if m.locks[lockID] == owner {
+		delete(m.locks, lockID)
+		m.releasedLocks = append(m.releasedLocks, lockID)
+	}
+	return nil
+}
+
+// fixedClock returns a clock function that always returns the given time
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+// hasPrefix reports whether any of the names starts with the given prefix
+func hasPrefix(names []string, prefix string) bool {
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func TestNewIndexerService(t *testing.T) {
 	source := &mockTalkSource{}
 	index := &mockSearchIndex{}
@@ -103,6 +352,7 @@ func TestNewIndexerService(t *testing.T) {
 	assert.Equal(t, "public", service.publicIndex)
 	assert.Equal(t, testPrivateMapping, service.privateIndexMapping)
 	assert.Equal(t, testPublicMapping, service.publicIndexMapping)
+	assert.Equal(t, DefaultIndexRetention, service.retention)
 }
 
 func TestReindexAll_Success(t *testing.T) {
@@ -128,28 +378,88 @@ func TestReindexAll_Success(t *testing.T) {
 	index := &mockSearchIndex{}
 
 	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
-	err := service.ReindexAll(context.Background())
+	service.now = fixedClock(time.Date(2024, 9, 4, 12, 30, 0, 0, time.UTC))
+	_, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
 
 	require.NoError(t, err)
 
-	// Verify indexes were recreated
-	assert.Contains(t, index.deleteIndexCalls, "private")
-	assert.Contains(t, index.deleteIndexCalls, "public")
-	assert.Contains(t, index.createIndexCalls, "private")
-	assert.Contains(t, index.createIndexCalls, "public")
+	// Verify new generations were created and nothing was deleted up front
+	assert.Empty(t, index.deleteIndexCalls)
+	assert.Equal(t, []string{"private_20240904123000000", "public_20240904123000000"}, index.createIndexCalls)
 
 	// Verify bulk index calls
 	require.Len(t, index.bulkIndexCalls, 2)
 
-	// First call should be private index with all talks
+	// First call should be private generation with all talks
 	privateCall := index.bulkIndexCalls[0]
-	assert.Equal(t, "private", privateCall.IndexName)
+	assert.Equal(t, "private_20240904123000000", privateCall.IndexName)
 	assert.Len(t, privateCall.Talks, 3)
 
-	// Second call should be public index with only approved talks
+	// Second call should be public generation with only approved talks
 	publicCall := index.bulkIndexCalls[1]
-	assert.Equal(t, "public", publicCall.IndexName)
+	assert.Equal(t, "public_20240904123000000", publicCall.IndexName)
 	assert.Len(t, publicCall.Talks, 2)
+
+	// Aliases should be swapped to the new generations
+	assert.Equal(t, []swapAliasCall{
+		{Alias: "private", IndexName: "private_20240904123000000"},
+		{Alias: "public", IndexName: "public_20240904123000000"},
+	}, index.swapAliasCalls)
+
+	// The new generations are refreshed once, before the swap
+	assert.Equal(t, [][]string{{"private_20240904123000000", "public_20240904123000000"}}, index.refreshCalls)
+}
+
+func TestReindexAll_Report(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{
+				{ID: "conf-1", Name: "JavaZone 2024", Slug: "javazone2024"},
+				{ID: "conf-2", Name: "JavaZone 2025", Slug: "javazone2025"},
+			}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			if conferenceID == "conf-2" {
+				return nil, errors.New("moresleep timeout")
+			}
+			return []domain.Talk{
+				{ID: "talk-1", ConferenceID: "conf-1", Status: "APPROVED"},
+				{ID: "talk-2", ConferenceID: "conf-1", Status: "SUBMITTED"},
+			}, nil
+		},
+	}
+
+	rejected := domain.BulkFailure{Index: "private_20240904123000000", DocumentID: "talk-2", Status: 400, Type: "mapper_parsing_exception"}
+	index := &mockSearchIndex{
+		bulkIndexFunc: func(ctx context.Context, indexName string, talks []domain.Talk) (*domain.BulkResult, error) {
+			if strings.HasPrefix(indexName, "private_") {
+				return &domain.BulkResult{Indexed: len(talks) - 1, Failures: []domain.BulkFailure{rejected}}, nil
+			}
+			return &domain.BulkResult{Indexed: len(talks)}, nil
+		},
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	service.now = fixedClock(time.Date(2024, 9, 4, 12, 30, 0, 0, time.UTC))
+	report, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+
+	require.NoError(t, err)
+	require.NotNil(t, report)
+
+	assert.Equal(t, "reindex-all", report.Operation)
+	assert.Equal(t, []domain.ConferenceReport{
+		{ConferenceID: "conf-1", ConferenceName: "JavaZone 2024", Slug: "javazone2024", PrivateCount: 2, PublicCount: 1},
+	}, report.Conferences)
+	assert.Equal(t, []domain.SkippedConference{
+		{ConferenceID: "conf-2", ConferenceName: "JavaZone 2025", Error: "moresleep timeout"},
+	}, report.Skipped)
+	assert.Equal(t, []domain.BulkFailure{rejected}, report.BulkFailures)
+	assert.Equal(t, 2, report.PrivateCount)
+	assert.Equal(t, 1, report.PublicCount)
+	assert.True(t, report.IsPartial())
+
+	// A partial result still replaces the previous generations
+	assert.Len(t, index.swapAliasCalls, 2)
 }
 
 func TestReindexAll_NoConferences(t *testing.T) {
@@ -162,14 +472,17 @@ func TestReindexAll_NoConferences(t *testing.T) {
 	index := &mockSearchIndex{}
 
 	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
-	err := service.ReindexAll(context.Background())
+	_, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
 
 	require.NoError(t, err)
 
-	// Verify indexes were recreated but no bulk indexing happened
-	assert.Contains(t, index.deleteIndexCalls, "private")
-	assert.Contains(t, index.deleteIndexCalls, "public")
-	assert.Empty(t, index.bulkIndexCalls)
+	// Verify empty generations were built and swapped in
+	assert.True(t, hasPrefix(index.createIndexCalls, "private_"))
+	assert.True(t, hasPrefix(index.createIndexCalls, "public_"))
+	require.Len(t, index.bulkIndexCalls, 2)
+	assert.Empty(t, index.bulkIndexCalls[0].Talks)
+	assert.Empty(t, index.bulkIndexCalls[1].Talks)
+	assert.Len(t, index.swapAliasCalls, 2)
 }
 
 func TestReindexAll_FetchConferencesError(t *testing.T) {
@@ -184,10 +497,81 @@ func TestReindexAll_FetchConferencesError(t *testing.T) {
 	index := &mockSearchIndex{}
 
 	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
-	err := service.ReindexAll(context.Background())
+	_, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to fetch conferences")
+
+	// Nothing should have been touched in the index
+	assert.Empty(t, index.createIndexCalls)
+	assert.Empty(t, index.deleteIndexCalls)
+	assert.Empty(t, index.swapAliasCalls)
+}
+
+func TestReindexAll_BulkIndexError_KeepsCurrentAlias(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Slug: "conf1"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return []domain.Talk{{ID: "talk-1", Status: "APPROVED"}}, nil
+		},
+	}
+
+	index := &mockSearchIndex{
+		bulkIndexFunc: func(ctx context.Context, indexName string, talks []domain.Talk) (*domain.BulkResult, error) {
+			if strings.HasPrefix(indexName, "public_") {
+				return nil, errors.New("bulk failed")
+			}
+			return &domain.BulkResult{Indexed: len(talks)}, nil
+		},
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	_, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to index to public index")
+
+	// Aliases must not move and the half-built generations are discarded
+	assert.Empty(t, index.swapAliasCalls)
+	assert.Equal(t, index.createIndexCalls, index.deleteIndexCalls)
+}
+
+func TestReindexAll_PrunesOldGenerations(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{}, nil
+		},
+	}
+
+	index := &mockSearchIndex{
+		listIndicesFunc: func(ctx context.Context, pattern string) ([]string, error) {
+			alias := strings.TrimSuffix(pattern, "_*")
+			return []string{
+				alias + "_20240101000000000",
+				alias + "_20240201000000000",
+				alias + "_20240301000000000",
+				alias + "_20240904123000000",
+				alias + "_backup",
+			}, nil
+		},
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	service.now = fixedClock(time.Date(2024, 9, 4, 12, 30, 0, 0, time.UTC))
+	service.SetIndexRetention(1)
+
+	_, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+	require.NoError(t, err)
+
+	// Only the newest previous generation is kept; unrelated indexes are left alone
+	assert.ElementsMatch(t, []string{
+		"private_20240101000000000",
+		"private_20240201000000000",
+		"public_20240101000000000",
+		"public_20240201000000000",
+	}, index.deleteIndexCalls)
 }
 
 func TestReindexAll_FetchTalksError_ContinuesWithOtherConferences(t *testing.T) {
@@ -213,7 +597,7 @@ func TestReindexAll_FetchTalksError_ContinuesWithOtherConferences(t *testing.T)
 	index := &mockSearchIndex{}
 
 	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
-	err := service.ReindexAll(context.Background())
+	_, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
 
 	// Should not return error, just log and continue
 	require.NoError(t, err)
@@ -248,9 +632,14 @@ func TestReindexConference_Success(t *testing.T) {
 	}
 
 	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
-	err := service.ReindexConference(context.Background(), "javazone2024")
+	report, err := service.ReindexConference(context.Background(), "javazone2024", domain.ReindexOptions{})
 
 	require.NoError(t, err)
+	assert.Equal(t, "javazone2024", report.Target)
+	assert.Equal(t, []domain.ConferenceReport{
+		{ConferenceID: "conf-1", ConferenceName: "JavaZone 2024", Slug: "javazone2024", PrivateCount: 2, PublicCount: 1},
+	}, report.Conferences)
+	assert.False(t, report.IsPartial())
 
 	// Should not recreate indexes, just ensure they exist
 	assert.Empty(t, index.deleteIndexCalls)
@@ -265,6 +654,9 @@ func TestReindexConference_Success(t *testing.T) {
 	publicCall := index.bulkIndexCalls[1]
 	assert.Equal(t, "public", publicCall.IndexName)
 	assert.Len(t, publicCall.Talks, 1) // Only approved
+
+	// Both indexes are refreshed once at the end
+	assert.Equal(t, [][]string{{"private", "public"}}, index.refreshCalls)
 }
 
 func TestReindexConference_NotFound(t *testing.T) {
@@ -281,7 +673,7 @@ func TestReindexConference_NotFound(t *testing.T) {
 	index := &mockSearchIndex{}
 
 	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
-	err := service.ReindexConference(context.Background(), "nonexistent")
+	_, err := service.ReindexConference(context.Background(), "nonexistent", domain.ReindexOptions{})
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "conference not found with slug")
@@ -308,13 +700,134 @@ func TestReindexConference_CreateIndexIfNotExists(t *testing.T) {
 	}
 
 	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
-	err := service.ReindexConference(context.Background(), "test")
+	_, err := service.ReindexConference(context.Background(), "test", domain.ReindexOptions{})
 
 	require.NoError(t, err)
 
-	// Should have created both indexes
-	assert.Contains(t, index.createIndexCalls, "private")
-	assert.Contains(t, index.createIndexCalls, "public")
+	// Should have created a generation behind each alias
+	assert.True(t, hasPrefix(index.createIndexCalls, "private_"))
+	assert.True(t, hasPrefix(index.createIndexCalls, "public_"))
+	require.Len(t, index.swapAliasCalls, 2)
+	assert.Equal(t, "private", index.swapAliasCalls[0].Alias)
+	assert.Equal(t, "public", index.swapAliasCalls[1].Alias)
+}
+
+func TestReindexConference_RemovesStaleTalks(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Slug: "javazone2024"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return []domain.Talk{
+				{ID: "talk-1", ConferenceID: "conf-1", Status: "APPROVED"},
+				{ID: "talk-2", ConferenceID: "conf-1", Status: "WITHDRAWN"},
+			}, nil
+		},
+	}
+
+	index := &mockSearchIndex{}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	_, err := service.ReindexConference(context.Background(), "javazone2024", domain.ReindexOptions{})
+
+	require.NoError(t, err)
+	require.Len(t, index.deleteStaleCalls, 2)
+
+	// Private index keeps every talk that still exists
+	assert.Equal(t, deleteStaleCall{IndexName: "private", ConferenceID: "conf-1", KeepIDs: []string{"talk-1", "talk-2"}}, index.deleteStaleCalls[0])
+
+	// Public index keeps only approved talks
+	assert.Equal(t, deleteStaleCall{IndexName: "public", ConferenceID: "conf-1", KeepIDs: []string{"talk-1"}}, index.deleteStaleCalls[1])
+}
+
+func TestReindexTalk_NotApproved_RemovedFromPublic(t *testing.T) {
+	source := &mockTalkSource{
+		getTalkFunc: func(ctx context.Context, talkID string) (*domain.Talk, error) {
+			return &domain.Talk{ID: talkID, Status: "WITHDRAWN"}, nil
+		},
+	}
+
+	index := &mockSearchIndex{}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	report, err := service.ReindexTalk(context.Background(), "talk-1", domain.ReindexOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.PrivateCount)
+	assert.Equal(t, 0, report.PublicCount)
+	assert.Equal(t, 1, report.PublicDeleted)
+	require.Len(t, index.bulkIndexCalls, 1)
+	assert.Equal(t, "private", index.bulkIndexCalls[0].IndexName)
+	assert.Equal(t, []deleteDocsCall{{IndexName: "public", IDs: []string{"talk-1"}}}, index.deleteDocsCalls)
+}
+
+func TestReindexTalk_NotApproved_NotInPublic(t *testing.T) {
+	source := &mockTalkSource{
+		getTalkFunc: func(ctx context.Context, talkID string) (*domain.Talk, error) {
+			return &domain.Talk{ID: talkID, Status: "SUBMITTED"}, nil
+		},
+	}
+
+	// The talk was never public, so there is nothing to delete
+	index := &mockSearchIndex{
+		deleteDocsFunc: func(ctx context.Context, indexName string, ids []string) (int, error) {
+			return 0, nil
+		},
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	report, err := service.ReindexTalk(context.Background(), "talk-1", domain.ReindexOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.PrivateCount)
+	assert.Equal(t, 0, report.PublicDeleted)
+}
+
+func TestReindexTalk_DeletedInSource_RemovedFromBothIndexes(t *testing.T) {
+	source := &mockTalkSource{
+		getTalkFunc: func(ctx context.Context, talkID string) (*domain.Talk, error) {
+			return nil, fmt.Errorf("failed to fetch talk: %w", domain.ErrNotFound)
+		},
+	}
+
+	// The talk was only in the private index
+	index := &mockSearchIndex{
+		deleteDocsFunc: func(ctx context.Context, indexName string, ids []string) (int, error) {
+			if indexName == "public" {
+				return 0, nil
+			}
+			return len(ids), nil
+		},
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	report, err := service.ReindexTalk(context.Background(), "talk-1", domain.ReindexOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.PrivateDeleted)
+	assert.Equal(t, 0, report.PublicDeleted)
+	assert.Empty(t, index.bulkIndexCalls)
+	assert.Equal(t, []deleteDocsCall{
+		{IndexName: "private", IDs: []string{"talk-1"}},
+		{IndexName: "public", IDs: []string{"talk-1"}},
+	}, index.deleteDocsCalls)
+}
+
+func TestReindexTalk_FetchError(t *testing.T) {
+	source := &mockTalkSource{
+		getTalkFunc: func(ctx context.Context, talkID string) (*domain.Talk, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	index := &mockSearchIndex{}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	_, err := service.ReindexTalk(context.Background(), "talk-1", domain.ReindexOptions{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to fetch talk talk-1")
+	assert.Empty(t, index.deleteDocsCalls)
 }
 
 func TestFilterApprovedTalksForPublic(t *testing.T) {
@@ -352,3 +865,11 @@ func TestFilterApprovedTalksForPublic_NoApproved(t *testing.T) {
 	assert.NotNil(t, approved)
 	assert.Len(t, approved, 0)
 }
+
+func TestIsGenerationOf(t *testing.T) {
+	assert.True(t, isGenerationOf("javazone_public", "javazone_public_20240904123000000"))
+	assert.False(t, isGenerationOf("javazone_public", "javazone_public"))
+	assert.False(t, isGenerationOf("javazone_public", "javazone_public_backup"))
+	assert.False(t, isGenerationOf("javazone_public", "javazone_private_20240904123000000"))
+	assert.False(t, isGenerationOf("javazone", "javazone_public_20240904123000000"))
+}
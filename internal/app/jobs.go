@@ -0,0 +1,387 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/javaBin/talks-indexer/internal/ports"
+)
+
+// DefaultJobHistory is the number of finished jobs kept in memory for status polling
+const DefaultJobHistory = 50
+
+// jobQueueSize is the maximum number of jobs waiting to run
+const jobQueueSize = 100
+
+// progressKey is the context key holding the progress callback of a running job
+type progressKey struct{}
+
+// withProgress returns a context that reports per-conference progress to fn
+func withProgress(ctx context.Context, fn func(domain.ConferenceProgress)) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// reportProgress reports per-conference progress to the job running the operation, if any
+func reportProgress(ctx context.Context, progress domain.ConferenceProgress) {
+	if fn, ok := ctx.Value(progressKey{}).(func(domain.ConferenceProgress)); ok {
+		fn(progress)
+	}
+}
+
+// job is a job together with the function used to cancel it while running
+type job struct {
+	domain.Job
+	cancel context.CancelFunc
+}
+
+// JobManager runs indexing operations as background jobs.
+// Jobs are executed one at a time in submission order, detached from the HTTP
+// request that submitted them, and their state can be polled by ID.
+type JobManager struct {
+	indexer ports.Indexer
+	history int
+	now     func() time.Time
+	logger  *slog.Logger
+
+	mu    sync.Mutex
+	jobs  map[string]*job
+	order []string // job IDs in submission order
+
+	queue  chan string
+	ctx    context.Context
+	stop   context.CancelFunc
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// NewJobManager creates a new JobManager and starts its worker
+func NewJobManager(indexer ports.Indexer) *JobManager {
+	ctx, stop := context.WithCancel(context.Background())
+
+	m := &JobManager{
+		indexer: indexer,
+		history: DefaultJobHistory,
+		now:     time.Now,
+		logger:  slog.Default().With("component", "jobs"),
+		jobs:    make(map[string]*job),
+		queue:   make(chan string, jobQueueSize),
+		ctx:     ctx,
+		stop:    stop,
+	}
+
+	m.wg.Add(1)
+	go m.worker()
+
+	return m
+}
+
+// Submit queues a new job and returns it in its initial state.
+// If an identical job is still queued, that job is returned instead. An identical job
+// that is already running may have read its data before the change that prompted this
+// request, so a follow-up job is queued behind it; further requests coalesce with that.
+func (m *JobManager) Submit(req domain.JobRequest) (*domain.Job, error) {
+	if !req.Type.IsValid() {
+		return nil, fmt.Errorf("%w: unknown job type %q", domain.ErrInvalidJobRequest, req.Type)
+	}
+	if req.Type.RequiresTarget() && req.Target == "" {
+		return nil, fmt.Errorf("%w: job type %s requires a target", domain.ErrInvalidJobRequest, req.Type)
+	}
+	if req.DryRun && !req.Type.SupportsDryRun() {
+		return nil, fmt.Errorf("%w: job type %s does not support dry run", domain.ErrInvalidJobRequest, req.Type)
+	}
+	if req.Force && !req.Type.SupportsForce() {
+		return nil, fmt.Errorf("%w: job type %s does not support force", domain.ErrInvalidJobRequest, req.Type)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, fmt.Errorf("job manager is stopped")
+	}
+
+	// Coalesce with an identical job that has not started yet instead of queueing a duplicate
+	for _, id := range m.order {
+		existing := m.jobs[id]
+		if existing.Type == req.Type && existing.Target == req.Target && existing.DryRun == req.DryRun && existing.Force == req.Force && existing.State == domain.JobQueued {
+			m.logger.Info("job coalesced with queued job", "jobID", existing.ID, "type", req.Type, "target", req.Target)
+			snapshot := existing.snapshot()
+			return &snapshot, nil
+		}
+	}
+
+	j := &job{Job: domain.Job{
+		ID:        newJobID(),
+		Type:      req.Type,
+		Target:    req.Target,
+		DryRun:    req.DryRun,
+		Force:     req.Force,
+		State:     domain.JobQueued,
+		Progress:  []domain.ConferenceProgress{},
+		Errors:    []string{},
+		CreatedAt: m.now().UTC(),
+	}}
+
+	select {
+	case m.queue <- j.ID:
+	default:
+		return nil, domain.ErrJobQueueFull
+	}
+
+	m.jobs[j.ID] = j
+	m.order = append(m.order, j.ID)
+	m.pruneHistory()
+
+	m.logger.Info("job queued", "jobID", j.ID, "type", j.Type, "target", j.Target, "dryRun", j.DryRun, "force", j.Force)
+
+	snapshot := j.snapshot()
+	return &snapshot, nil
+}
+
+// Get returns a snapshot of the job with the given ID
+func (m *JobManager) Get(id string) (*domain.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return nil, domain.ErrJobNotFound
+	}
+
+	snapshot := j.snapshot()
+	return &snapshot, nil
+}
+
+// List returns snapshots of all known jobs, newest first
+func (m *JobManager) List() []domain.Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]domain.Job, 0, len(m.order))
+	for i := len(m.order) - 1; i >= 0; i-- {
+		result = append(result, m.jobs[m.order[i]].snapshot())
+	}
+	return result
+}
+
+// Cancel stops a queued or running job. A queued job is cancelled immediately;
+// a running job is cancelled through its context and finishes once the current
+// operation returns.
+func (m *JobManager) Cancel(id string) (*domain.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return nil, domain.ErrJobNotFound
+	}
+	if j.State.IsFinished() {
+		return nil, domain.ErrJobFinished
+	}
+
+	switch j.State {
+	case domain.JobQueued:
+		m.finish(j, domain.JobCancelled)
+	case domain.JobRunning:
+		j.cancel()
+	}
+
+	m.logger.Info("job cancellation requested", "jobID", id)
+
+	snapshot := j.snapshot()
+	return &snapshot, nil
+}
+
+// Stop cancels any running job and waits for the worker to exit.
+// Jobs still queued are marked cancelled.
+func (m *JobManager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+	for _, j := range m.jobs {
+		if j.State == domain.JobQueued {
+			m.finish(j, domain.JobCancelled)
+		}
+	}
+	m.mu.Unlock()
+
+	m.stop()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// worker runs queued jobs one at a time until the manager is stopped
+func (m *JobManager) worker() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case id := <-m.queue:
+			m.run(id)
+		}
+	}
+}
+
+// run executes a single job and records its outcome
+func (m *JobManager) run(id string) {
+	m.mu.Lock()
+	j, ok := m.jobs[id]
+	if !ok || j.State != domain.JobQueued {
+		// Cancelled or pruned while waiting in the queue
+		m.mu.Unlock()
+		return
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	defer cancel()
+
+	startedAt := m.now().UTC()
+	j.State = domain.JobRunning
+	j.StartedAt = &startedAt
+	j.cancel = cancel
+	m.mu.Unlock()
+
+	m.logger.Info("job started", "jobID", id, "type", j.Type, "target", j.Target)
+
+	ctx = withProgress(ctx, func(progress domain.ConferenceProgress) {
+		m.updateProgress(id, progress)
+	})
+
+	report, err := m.execute(ctx, j.Job)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j.Report = report
+	if report != nil {
+		// Skipped conferences are already listed through progress; rejected documents are not
+		for _, failure := range report.BulkFailures {
+			j.Errors = append(j.Errors, fmt.Sprintf("document %s in %s: %s", failure.DocumentID, failure.Index, failure.Reason))
+		}
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		if err != nil && !errors.Is(err, context.Canceled) {
+			j.Errors = append(j.Errors, err.Error())
+		}
+		m.finish(j, domain.JobCancelled)
+	case err != nil:
+		j.Errors = append(j.Errors, err.Error())
+		m.finish(j, domain.JobFailed)
+	default:
+		m.finish(j, domain.JobSucceeded)
+	}
+
+	m.logger.Info("job finished",
+		"jobID", id,
+		"state", j.State,
+		"errors", len(j.Errors),
+		"duration", j.FinishedAt.Sub(startedAt),
+	)
+}
+
+// execute dispatches the job to the matching indexer operation
+func (m *JobManager) execute(ctx context.Context, j domain.Job) (*domain.ReindexReport, error) {
+	opts := domain.ReindexOptions{DryRun: j.DryRun, Force: j.Force}
+
+	switch j.Type {
+	case domain.JobReindexAll:
+		return m.indexer.ReindexAll(ctx, opts)
+	case domain.JobReindexConference:
+		return m.indexer.ReindexConference(ctx, j.Target, opts)
+	case domain.JobReindexTalk:
+		return m.indexer.ReindexTalk(ctx, j.Target, opts)
+	case domain.JobSync:
+		return m.indexer.SyncChanges(ctx, opts)
+	default:
+		return nil, fmt.Errorf("%w: unknown job type %q", domain.ErrInvalidJobRequest, j.Type)
+	}
+}
+
+// updateProgress records the progress of one conference within a running job
+func (m *JobManager) updateProgress(id string, progress domain.ConferenceProgress) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return
+	}
+
+	if progress.State == domain.ProgressFailed && progress.Error != "" {
+		j.Errors = append(j.Errors, fmt.Sprintf("conference %s: %s", progress.ConferenceID, progress.Error))
+	}
+
+	for i := range j.Progress {
+		if j.Progress[i].ConferenceID == progress.ConferenceID {
+			j.Progress[i] = progress
+			return
+		}
+	}
+	j.Progress = append(j.Progress, progress)
+}
+
+// finish moves a job to a terminal state. The caller must hold m.mu.
+func (m *JobManager) finish(j *job, state domain.JobState) {
+	finishedAt := m.now().UTC()
+	j.State = state
+	j.FinishedAt = &finishedAt
+	j.cancel = nil
+}
+
+// pruneHistory drops the oldest finished jobs beyond the history limit.
+// The caller must hold m.mu.
+func (m *JobManager) pruneHistory() {
+	finished := 0
+	for _, id := range m.order {
+		if m.jobs[id].State.IsFinished() {
+			finished++
+		}
+	}
+
+	kept := m.order[:0]
+	for _, id := range m.order {
+		if finished > m.history && m.jobs[id].State.IsFinished() {
+			delete(m.jobs, id)
+			finished--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	m.order = kept
+}
+
+// snapshot returns a copy of the job that is safe to hand out without the lock
+func (j *job) snapshot() domain.Job {
+	snapshot := j.Job
+	snapshot.Progress = append([]domain.ConferenceProgress{}, j.Progress...)
+	snapshot.Errors = append([]string{}, j.Errors...)
+	return snapshot
+}
+
+// newJobID returns a random job identifier
+func newJobID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
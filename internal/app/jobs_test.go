@@ -0,0 +1,404 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIndexer is a mock implementation of ports.Indexer
+type mockIndexer struct {
+	reindexAllFunc        func(ctx context.Context, opts domain.ReindexOptions) (*domain.ReindexReport, error)
+	reindexConferenceFunc func(ctx context.Context, slug string, opts domain.ReindexOptions) (*domain.ReindexReport, error)
+	reindexTalkFunc       func(ctx context.Context, talkID string, opts domain.ReindexOptions) (*domain.ReindexReport, error)
+	syncChangesFunc       func(ctx context.Context, opts domain.ReindexOptions) (*domain.ReindexReport, error)
+	unmappedFieldsFunc    func(ctx context.Context) (*domain.UnmappedFieldReport, error)
+}
+
+func (m *mockIndexer) ReindexAll(ctx context.Context, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
+	if m.reindexAllFunc != nil {
+		return m.reindexAllFunc(ctx, opts)
+	}
+	return domain.NewReindexReport("reindex-all", "", time.Now()), nil
+}
+
+func (m *mockIndexer) ReindexConference(ctx context.Context, slug string, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
+	if m.reindexConferenceFunc != nil {
+		return m.reindexConferenceFunc(ctx, slug, opts)
+	}
+	return domain.NewReindexReport("reindex-conference", slug, time.Now()), nil
+}
+
+func (m *mockIndexer) ReindexTalk(ctx context.Context, talkID string, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
+	if m.reindexTalkFunc != nil {
+		return m.reindexTalkFunc(ctx, talkID, opts)
+	}
+	return domain.NewReindexReport("reindex-talk", talkID, time.Now()), nil
+}
+
+func (m *mockIndexer) SyncChanges(ctx context.Context, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
+	if m.syncChangesFunc != nil {
+		return m.syncChangesFunc(ctx, opts)
+	}
+	report := domain.NewReindexReport("sync", "", time.Now())
+	report.Sync = &domain.SyncResult{}
+	return report, nil
+}
+
+func (m *mockIndexer) UnmappedFields(ctx context.Context) (*domain.UnmappedFieldReport, error) {
+	if m.unmappedFieldsFunc != nil {
+		return m.unmappedFieldsFunc(ctx)
+	}
+	return &domain.UnmappedFieldReport{}, nil
+}
+
+// blockingIndexer returns an indexer whose full reindex blocks until cancelled,
+// signalling on started once it is running
+func blockingIndexer(started chan<- struct{}) *mockIndexer {
+	return &mockIndexer{
+		reindexAllFunc: func(ctx context.Context, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
+			started <- struct{}{}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+}
+
+// waitForJob polls the job until it reaches a terminal state
+func waitForJob(t *testing.T, m *JobManager, id string) *domain.Job {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := m.Get(id)
+		require.NoError(t, err)
+		if job.State.IsFinished() {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish in time", id)
+	return nil
+}
+
+func TestJobManager_Submit_Invalid(t *testing.T) {
+	m := NewJobManager(&mockIndexer{})
+	defer m.Stop(context.Background())
+
+	_, err := m.Submit(domain.JobRequest{Type: "rebuild-everything"})
+	assert.ErrorIs(t, err, domain.ErrInvalidJobRequest)
+
+	_, err = m.Submit(domain.JobRequest{Type: domain.JobReindexConference})
+	assert.ErrorIs(t, err, domain.ErrInvalidJobRequest)
+
+	_, err = m.Submit(domain.JobRequest{Type: domain.JobSync, DryRun: true})
+	assert.ErrorIs(t, err, domain.ErrInvalidJobRequest)
+
+	_, err = m.Submit(domain.JobRequest{Type: domain.JobReindexTalk, Target: "talk-1", Force: true})
+	assert.ErrorIs(t, err, domain.ErrInvalidJobRequest)
+
+	assert.Empty(t, m.List())
+}
+
+func TestJobManager_RunsJobWithProgress(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{
+				{ID: "conf-1", Name: "JavaZone 2024", Slug: "javazone2024"},
+				{ID: "conf-2", Name: "JavaZone 2025", Slug: "javazone2025"},
+			}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			if conferenceID == "conf-2" {
+				return nil, errors.New("moresleep timeout")
+			}
+			return []domain.Talk{
+				{ID: "talk-1", ConferenceID: "conf-1", Status: "APPROVED"},
+				{ID: "talk-2", ConferenceID: "conf-1", Status: "SUBMITTED"},
+			}, nil
+		},
+	}
+	service := NewIndexerService(source, &mockSearchIndex{}, "private", "public", testPrivateMapping, testPublicMapping)
+
+	m := NewJobManager(service)
+	defer m.Stop(context.Background())
+
+	submitted, err := m.Submit(domain.JobRequest{Type: domain.JobReindexAll})
+	require.NoError(t, err)
+	assert.NotEmpty(t, submitted.ID)
+	assert.Equal(t, domain.JobQueued, submitted.State)
+
+	job := waitForJob(t, m, submitted.ID)
+
+	assert.Equal(t, domain.JobSucceeded, job.State)
+	require.NotNil(t, job.StartedAt)
+	require.NotNil(t, job.FinishedAt)
+	assert.Equal(t, []domain.ConferenceProgress{
+		{ConferenceID: "conf-1", ConferenceName: "JavaZone 2024", State: domain.ProgressDone, Talks: 2},
+		{ConferenceID: "conf-2", ConferenceName: "JavaZone 2025", State: domain.ProgressFailed, Error: "moresleep timeout"},
+	}, job.Progress)
+	assert.Equal(t, []string{"conference conf-2: moresleep timeout"}, job.Errors)
+	require.NotNil(t, job.Report)
+	assert.Equal(t, 2, job.Report.PrivateCount)
+	assert.Len(t, job.Report.Skipped, 1)
+}
+
+func TestJobManager_FailedJob(t *testing.T) {
+	m := NewJobManager(&mockIndexer{
+		reindexConferenceFunc: func(ctx context.Context, slug string, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
+			return nil, errors.New("conference not found with slug: " + slug)
+		},
+	})
+	defer m.Stop(context.Background())
+
+	submitted, err := m.Submit(domain.JobRequest{Type: domain.JobReindexConference, Target: "missing"})
+	require.NoError(t, err)
+
+	job := waitForJob(t, m, submitted.ID)
+
+	assert.Equal(t, domain.JobFailed, job.State)
+	assert.Equal(t, "missing", job.Target)
+	assert.Equal(t, []string{"conference not found with slug: missing"}, job.Errors)
+}
+
+func TestJobManager_CancelRunningJob(t *testing.T) {
+	started := make(chan struct{}, 1)
+	m := NewJobManager(blockingIndexer(started))
+	defer m.Stop(context.Background())
+
+	submitted, err := m.Submit(domain.JobRequest{Type: domain.JobReindexAll})
+	require.NoError(t, err)
+	<-started
+
+	_, err = m.Cancel(submitted.ID)
+	require.NoError(t, err)
+
+	job := waitForJob(t, m, submitted.ID)
+	assert.Equal(t, domain.JobCancelled, job.State)
+	assert.Empty(t, job.Errors)
+
+	_, err = m.Cancel(submitted.ID)
+	assert.ErrorIs(t, err, domain.ErrJobFinished)
+}
+
+func TestJobManager_CancelQueuedJob(t *testing.T) {
+	started := make(chan struct{}, 1)
+	talkCalled := false
+	indexer := blockingIndexer(started)
+	indexer.reindexTalkFunc = func(ctx context.Context, talkID string, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
+		talkCalled = true
+		return nil, nil
+	}
+
+	m := NewJobManager(indexer)
+
+	first, err := m.Submit(domain.JobRequest{Type: domain.JobReindexAll})
+	require.NoError(t, err)
+	<-started
+
+	second, err := m.Submit(domain.JobRequest{Type: domain.JobReindexTalk, Target: "talk-1"})
+	require.NoError(t, err)
+
+	cancelled, err := m.Cancel(second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobCancelled, cancelled.State)
+	assert.NotNil(t, cancelled.FinishedAt)
+
+	_, err = m.Cancel(first.ID)
+	require.NoError(t, err)
+	waitForJob(t, m, first.ID)
+
+	require.NoError(t, m.Stop(context.Background()))
+	assert.False(t, talkCalled)
+}
+
+func TestJobManager_CoalescesDuplicateJobs(t *testing.T) {
+	started := make(chan struct{}, 1)
+	m := NewJobManager(blockingIndexer(started))
+	defer m.Stop(context.Background())
+
+	first, err := m.Submit(domain.JobRequest{Type: domain.JobReindexAll})
+	require.NoError(t, err)
+	<-started
+
+	// The running job may have read its data already, so one follow-up job is queued
+	followUp, err := m.Submit(domain.JobRequest{Type: domain.JobReindexAll})
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, followUp.ID)
+	assert.Equal(t, domain.JobQueued, followUp.State)
+
+	duplicate, err := m.Submit(domain.JobRequest{Type: domain.JobReindexAll})
+	require.NoError(t, err)
+	assert.Equal(t, followUp.ID, duplicate.ID)
+
+	other, err := m.Submit(domain.JobRequest{Type: domain.JobReindexConference, Target: "javazone2024"})
+	require.NoError(t, err)
+	assert.NotEqual(t, followUp.ID, other.ID)
+
+	// A dry run is a different job from a real reindex of the same target
+	dryRun, err := m.Submit(domain.JobRequest{Type: domain.JobReindexAll, DryRun: true})
+	require.NoError(t, err)
+	assert.NotEqual(t, followUp.ID, dryRun.ID)
+	assert.True(t, dryRun.DryRun)
+
+	assert.Len(t, m.List(), 4)
+
+	_, err = m.Cancel(first.ID)
+	require.NoError(t, err)
+	waitForJob(t, m, first.ID)
+
+	// Once the follow-up is running an identical request queues another job
+	<-started
+	again, err := m.Submit(domain.JobRequest{Type: domain.JobReindexAll})
+	require.NoError(t, err)
+	assert.NotEqual(t, followUp.ID, again.ID)
+
+	_, _ = m.Cancel(followUp.ID)
+	_, _ = m.Cancel(again.ID)
+}
+
+func TestJobManager_RequeuesTalkChangedWhileRunning(t *testing.T) {
+	running := make(chan struct{})
+	unblock := make(chan struct{})
+	calls := make(chan string, 5)
+	m := NewJobManager(&mockIndexer{
+		reindexTalkFunc: func(ctx context.Context, talkID string, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
+			calls <- talkID
+			if len(calls) == 1 {
+				// The first run has read the talk and is still writing it
+				close(running)
+				<-unblock
+			}
+			return domain.NewReindexReport("reindex-talk", talkID, time.Now()), nil
+		},
+	})
+	defer m.Stop(context.Background())
+
+	first, err := m.Submit(domain.JobRequest{Type: domain.JobReindexTalk, Target: "talk-1"})
+	require.NoError(t, err)
+	<-running
+
+	// A change notification for the same talk arrives while it is being reindexed
+	second, err := m.Submit(domain.JobRequest{Type: domain.JobReindexTalk, Target: "talk-1"})
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, second.ID)
+	assert.Equal(t, domain.JobQueued, second.State)
+
+	close(unblock)
+	assert.Equal(t, domain.JobSucceeded, waitForJob(t, m, first.ID).State)
+	assert.Equal(t, domain.JobSucceeded, waitForJob(t, m, second.ID).State)
+
+	// The talk is read again after the change
+	assert.Len(t, calls, 2)
+}
+
+func TestJobManager_PassesDryRunToIndexer(t *testing.T) {
+	var received domain.ReindexOptions
+	m := NewJobManager(&mockIndexer{
+		reindexConferenceFunc: func(ctx context.Context, slug string, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
+			received = opts
+			report := domain.NewReindexReport("reindex-conference", slug, time.Now())
+			report.DryRun = opts.DryRun
+			return report, nil
+		},
+	})
+	defer m.Stop(context.Background())
+
+	submitted, err := m.Submit(domain.JobRequest{Type: domain.JobReindexConference, Target: "javazone2024", DryRun: true})
+	require.NoError(t, err)
+
+	job := waitForJob(t, m, submitted.ID)
+	assert.Equal(t, domain.JobSucceeded, job.State)
+	assert.True(t, received.DryRun)
+	require.NotNil(t, job.Report)
+	assert.True(t, job.Report.DryRun)
+}
+
+func TestJobManager_PassesForceToIndexer(t *testing.T) {
+	var received domain.ReindexOptions
+	m := NewJobManager(&mockIndexer{
+		reindexAllFunc: func(ctx context.Context, opts domain.ReindexOptions) (*domain.ReindexReport, error) {
+			received = opts
+			return domain.NewReindexReport("reindex-all", "", time.Now()), nil
+		},
+	})
+	defer m.Stop(context.Background())
+
+	submitted, err := m.Submit(domain.JobRequest{Type: domain.JobReindexAll, Force: true})
+	require.NoError(t, err)
+	assert.True(t, submitted.Force)
+
+	job := waitForJob(t, m, submitted.ID)
+	assert.Equal(t, domain.JobSucceeded, job.State)
+	assert.True(t, received.Force)
+	assert.False(t, received.DryRun)
+}
+
+func TestJobManager_GetUnknownJob(t *testing.T) {
+	m := NewJobManager(&mockIndexer{})
+	defer m.Stop(context.Background())
+
+	_, err := m.Get("does-not-exist")
+	assert.ErrorIs(t, err, domain.ErrJobNotFound)
+
+	_, err = m.Cancel("does-not-exist")
+	assert.ErrorIs(t, err, domain.ErrJobNotFound)
+}
+
+func TestJobManager_ListNewestFirst(t *testing.T) {
+	m := NewJobManager(&mockIndexer{})
+	defer m.Stop(context.Background())
+
+	first, err := m.Submit(domain.JobRequest{Type: domain.JobSync})
+	require.NoError(t, err)
+	second, err := m.Submit(domain.JobRequest{Type: domain.JobReindexAll})
+	require.NoError(t, err)
+
+	waitForJob(t, m, second.ID)
+
+	jobs := m.List()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, second.ID, jobs[0].ID)
+	assert.Equal(t, first.ID, jobs[1].ID)
+}
+
+func TestJobManager_PrunesFinishedJobs(t *testing.T) {
+	m := NewJobManager(&mockIndexer{})
+	defer m.Stop(context.Background())
+	m.history = 2
+
+	var last *domain.Job
+	for i := 0; i < 4; i++ {
+		job, err := m.Submit(domain.JobRequest{Type: domain.JobSync})
+		require.NoError(t, err)
+		waitForJob(t, m, job.ID)
+		last = job
+	}
+
+	jobs := m.List()
+	require.Len(t, jobs, 3)
+	assert.Equal(t, last.ID, jobs[0].ID)
+}
+
+func TestJobManager_StopCancelsRunningJob(t *testing.T) {
+	started := make(chan struct{}, 1)
+	m := NewJobManager(blockingIndexer(started))
+
+	submitted, err := m.Submit(domain.JobRequest{Type: domain.JobReindexAll})
+	require.NoError(t, err)
+	<-started
+
+	require.NoError(t, m.Stop(context.Background()))
+
+	job, err := m.Get(submitted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobCancelled, job.State)
+
+	_, err = m.Submit(domain.JobRequest{Type: domain.JobSync})
+	assert.Error(t, err)
+}
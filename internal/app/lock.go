@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// DefaultLockTTL is how long an indexing lock is valid without being renewed
+const DefaultLockTTL = 5 * time.Minute
+
+// lockReleaseTimeout bounds how long releasing a lock may take once an operation is done
+const lockReleaseTimeout = 10 * time.Second
+
+// SetLockTTL sets how long an indexing lock stays valid without renewal.
+// Running operations renew their lock well before it expires, so the TTL only
+// matters when a replica dies while holding it.
+func (s *IndexerService) SetLockTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+	s.lockTTL = ttl
+}
+
+// acquireLock takes the indexing lock for this service's private/public index pair.
+// The lock is stored in the state index so it is shared between replicas, and is
+// renewed in the background until the returned release function is called.
+// The operation must run with the returned context, which is cancelled with
+// domain.ErrLockLost as its cause if the lock is taken over or expires because it
+// could not be renewed; see lockLost.
+// It returns domain.ErrReindexInProgress if another operation holds the lock.
+func (s *IndexerService) acquireLock(ctx context.Context) (context.Context, func(), error) {
+	lockID := s.lockID()
+	owner := newLockOwner()
+
+	acquired, err := s.searchIndex.AcquireLock(ctx, s.stateIndex, lockID, owner, s.lockTTL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to acquire indexing lock: %w", err)
+	}
+	if !acquired {
+		s.logger.Warn("indexing lock is held by another operation", "lock", lockID)
+		return nil, nil, fmt.Errorf("%w for indexes %s and %s", domain.ErrReindexInProgress, s.privateIndex, s.publicIndex)
+	}
+
+	s.logger.Debug("acquired indexing lock", "lock", lockID, "owner", owner)
+
+	lockCtx, cancel := context.WithCancelCause(ctx)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go s.renewLock(context.WithoutCancel(ctx), lockID, owner, cancel, stop, done)
+
+	release := func() {
+		close(stop)
+		<-done
+		cancel(nil)
+
+		releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(ctx), lockReleaseTimeout)
+		defer cancelRelease()
+
+		if err := s.searchIndex.ReleaseLock(releaseCtx, s.stateIndex, lockID, owner); err != nil {
+			s.logger.Error("failed to release indexing lock", "lock", lockID, "error", err)
+			return
+		}
+		s.logger.Debug("released indexing lock", "lock", lockID, "owner", owner)
+	}
+
+	return lockCtx, release, nil
+}
+
+// lockUnlessDryRun takes the indexing lock for operations that write. A dry run only
+// reads the indexes, so it keeps its context, gets a no-op release and may run
+// alongside other operations.
+func (s *IndexerService) lockUnlessDryRun(ctx context.Context, opts domain.ReindexOptions) (context.Context, func(), error) {
+	if opts.DryRun {
+		return ctx, func() {}, nil
+	}
+	return s.acquireLock(ctx)
+}
+
+// renewLock extends the lock at a third of its TTL until stop is closed. If the lock is
+// taken over, or a renewal fails and the lock would expire before the next attempt,
+// the operation is cancelled with domain.ErrLockLost and renewal stops.
+func (s *IndexerService) renewLock(ctx context.Context, lockID, owner string, lost context.CancelCauseFunc, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	interval := s.lockTTL / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	expires := time.Now().Add(s.lockTTL)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			attempted := time.Now()
+			renewed, err := s.searchIndex.AcquireLock(ctx, s.stateIndex, lockID, owner, s.lockTTL)
+			if err != nil {
+				s.logger.Error("failed to renew indexing lock", "lock", lockID, "error", err)
+				if time.Until(expires) > interval {
+					continue
+				}
+				lost(fmt.Errorf("%w: lock %s expires before it can be renewed: %w", domain.ErrLockLost, lockID, err))
+				return
+			}
+			if !renewed {
+				s.logger.Error("indexing lock was taken over by another operation", "lock", lockID)
+				lost(fmt.Errorf("%w: lock %s was taken over by another operation", domain.ErrLockLost, lockID))
+				return
+			}
+			expires = attempted.Add(s.lockTTL)
+		}
+	}
+}
+
+// lockLost reports an operation that failed after its lock was lost as failing with
+// domain.ErrLockLost, since its own error is then usually just the cancellation.
+// It is deferred with the error the operation returns.
+func lockLost(ctx context.Context, err *error) {
+	if *err == nil {
+		return
+	}
+	if cause := context.Cause(ctx); errors.Is(cause, domain.ErrLockLost) && !errors.Is(*err, domain.ErrLockLost) {
+		*err = fmt.Errorf("%w: %w", cause, *err)
+	}
+}
+
+// lockID returns the state document ID of the lock guarding this service's index pair
+func (s *IndexerService) lockID() string {
+	return "lock-" + s.privateIndex + "-" + s.publicIndex
+}
+
+// newLockOwner returns a unique owner ID for a single lock acquisition
+func newLockOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return hostname + "-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,201 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexerService_LockHeldByAnotherOperation(t *testing.T) {
+	index := &mockSearchIndex{
+		locks: map[string]string{"lock-private-public": "other-replica"},
+	}
+	fetched := false
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			fetched = true
+			return nil, nil
+		},
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+
+	operations := map[string]func() error{
+		"ReindexAll": func() error {
+			_, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+			return err
+		},
+		"ReindexConference": func() error {
+			_, err := service.ReindexConference(context.Background(), "javazone2024", domain.ReindexOptions{})
+			return err
+		},
+		"ReindexTalk": func() error {
+			_, err := service.ReindexTalk(context.Background(), "talk-1", domain.ReindexOptions{})
+			return err
+		},
+		"SyncChanges": func() error {
+			_, err := service.SyncChanges(context.Background(), domain.ReindexOptions{})
+			return err
+		},
+	}
+
+	for name, operation := range operations {
+		t.Run(name, func(t *testing.T) {
+			err := operation()
+			assert.ErrorIs(t, err, domain.ErrReindexInProgress)
+		})
+	}
+
+	// Nothing may be read or written while another operation holds the lock
+	assert.False(t, fetched)
+	assert.Empty(t, index.createIndexCalls)
+	assert.Empty(t, index.bulkIndexCalls)
+	assert.Equal(t, "other-replica", index.locks["lock-private-public"])
+}
+
+func TestIndexerService_ReleasesLock(t *testing.T) {
+	index := &mockSearchIndex{}
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return nil, errors.New("moresleep unavailable")
+		},
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+
+	// The lock is released after both failed and successful runs so the next run can proceed
+	_, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+	require.Error(t, err)
+
+	_, err = service.ReindexAll(context.Background(), domain.ReindexOptions{})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrReindexInProgress)
+
+	assert.Equal(t, []string{"lock-private-public", "lock-private-public"}, index.releasedLocks)
+	assert.Empty(t, index.locks)
+}
+
+func TestIndexerService_LockScopedToIndexPair(t *testing.T) {
+	index := &mockSearchIndex{
+		locks: map[string]string{"lock-private-public": "other-replica"},
+	}
+
+	service := NewIndexerService(&mockTalkSource{}, index, "staging_private", "staging_public", testPrivateMapping, testPublicMapping)
+
+	_, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+	require.NoError(t, err)
+}
+
+func TestIndexerService_LockError(t *testing.T) {
+	index := &mockSearchIndex{
+		acquireLockFunc: func(ctx context.Context, indexName string, lockID string, owner string, ttl time.Duration) (bool, error) {
+			assert.Equal(t, DefaultStateIndex, indexName)
+			assert.Equal(t, DefaultLockTTL, ttl)
+			return false, errors.New("cluster unavailable")
+		},
+	}
+
+	service := NewIndexerService(&mockTalkSource{}, index, "private", "public", testPrivateMapping, testPublicMapping)
+
+	_, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to acquire indexing lock")
+	assert.NotErrorIs(t, err, domain.ErrReindexInProgress)
+}
+
+func TestIndexerService_RenewsLock(t *testing.T) {
+	renewals := make(chan string, 10)
+	index := &mockSearchIndex{
+		acquireLockFunc: func(ctx context.Context, indexName string, lockID string, owner string, ttl time.Duration) (bool, error) {
+			renewals <- owner
+			return true, nil
+		},
+	}
+
+	service := NewIndexerService(&mockTalkSource{}, index, "private", "public", testPrivateMapping, testPublicMapping)
+	service.SetLockTTL(30 * time.Millisecond)
+
+	_, release, err := service.acquireLock(context.Background())
+	require.NoError(t, err)
+	owner := <-renewals
+
+	select {
+	case renewed := <-renewals:
+		assert.Equal(t, owner, renewed)
+	case <-time.After(time.Second):
+		t.Fatal("lock was not renewed")
+	}
+
+	release()
+}
+
+func TestIndexerService_AbortsWhenLockTakenOver(t *testing.T) {
+	index := &mockSearchIndex{}
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Slug: "javazone2024"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			// Another replica takes over the lock while the talks are fetched
+			index.lockMu.Lock()
+			index.locks["lock-private-public"] = "other-replica"
+			index.lockMu.Unlock()
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Second):
+				return nil, errors.New("operation was not cancelled")
+			}
+		},
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	service.SetLockTTL(30 * time.Millisecond)
+
+	_, err := service.ReindexConference(context.Background(), "javazone2024", domain.ReindexOptions{})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrLockLost)
+	assert.Contains(t, err.Error(), "taken over by another operation")
+
+	// Nothing is written once the lock is lost, and the new holder keeps it
+	assert.Empty(t, index.bulkIndexCalls)
+	assert.Empty(t, index.deleteStaleCalls)
+	assert.Equal(t, "other-replica", index.locks["lock-private-public"])
+}
+
+func TestIndexerService_LockLostWhenRenewalFails(t *testing.T) {
+	var calls atomic.Int32
+	index := &mockSearchIndex{
+		acquireLockFunc: func(ctx context.Context, indexName string, lockID string, owner string, ttl time.Duration) (bool, error) {
+			if calls.Add(1) == 1 {
+				return true, nil
+			}
+			return false, errors.New("cluster unavailable")
+		},
+	}
+
+	service := NewIndexerService(&mockTalkSource{}, index, "private", "public", testPrivateMapping, testPublicMapping)
+	service.SetLockTTL(30 * time.Millisecond)
+
+	ctx, release, err := service.acquireLock(context.Background())
+	require.NoError(t, err)
+	defer release()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("operation was not cancelled when the lock could not be renewed")
+	}
+
+	assert.ErrorIs(t, context.Cause(ctx), domain.ErrLockLost)
+	assert.Contains(t, context.Cause(ctx).Error(), "cluster unavailable")
+	// The first failed renewal is retried while the lock is still valid
+	assert.GreaterOrEqual(t, calls.Load(), int32(3))
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// removeStaleTalks deletes documents of a conference that should no longer be indexed:
+// private documents whose talk no longer exists in the source, and public documents
+// whose talk no longer exists or is no longer approved.
+// It returns the number of documents deleted from the private and public indexes.
+func (s *IndexerService) removeStaleTalks(ctx context.Context, conferenceID string, talks []domain.Talk) (int, int, error) {
+	allIDs := make([]string, 0, len(talks))
+	approvedIDs := make([]string, 0, len(talks))
+	for _, talk := range talks {
+		allIDs = append(allIDs, talk.ID)
+		if domain.TalkStatus(talk.Status).IsPublic() {
+			approvedIDs = append(approvedIDs, talk.ID)
+		}
+	}
+
+	privateDeleted, err := s.searchIndex.DeleteConferenceTalksExcept(ctx, s.privateIndex, conferenceID, allIDs)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to remove stale talks from private index: %w", err)
+	}
+
+	publicDeleted, err := s.searchIndex.DeleteConferenceTalksExcept(ctx, s.publicIndex, conferenceID, approvedIDs)
+	if err != nil {
+		return privateDeleted, 0, fmt.Errorf("failed to remove stale talks from public index: %w", err)
+	}
+
+	return privateDeleted, publicDeleted, nil
+}
+
+// removeTalk deletes a talk that no longer exists in the source from both indexes and
+// returns how many copies were deleted from each
+func (s *IndexerService) removeTalk(ctx context.Context, talkID string) (privateDeleted, publicDeleted int, err error) {
+	privateDeleted, err = s.searchIndex.DeleteDocuments(ctx, s.privateIndex, []string{talkID})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to remove talk from private index: %w", err)
+	}
+	publicDeleted, err = s.searchIndex.DeleteDocuments(ctx, s.publicIndex, []string{talkID})
+	if err != nil {
+		return privateDeleted, 0, fmt.Errorf("failed to remove talk from public index: %w", err)
+	}
+	return privateDeleted, publicDeleted, nil
+}
@@ -0,0 +1,208 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/javaBin/talks-indexer/internal/ports"
+)
+
+// DefaultScheduleJitter is the upper bound of the random delay added to each scheduled run
+const DefaultScheduleJitter = 30 * time.Second
+
+// scheduleEntry is a single recurring job and its run history
+type scheduleEntry struct {
+	status   domain.ScheduleStatus
+	schedule *Schedule
+}
+
+// Scheduler submits indexing jobs on recurring cron schedules.
+// Each run is delayed by a random jitter so replicas sharing a schedule do not
+// all start at the same instant, and a run is skipped while the job started by
+// the previous run is still queued or running.
+type Scheduler struct {
+	jobs   ports.JobManager
+	jitter time.Duration
+	now    func() time.Time
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	entries []*scheduleEntry
+
+	stop    context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// NewScheduler creates a new Scheduler that submits jobs to the given job manager
+func NewScheduler(jobs ports.JobManager) *Scheduler {
+	return &Scheduler{
+		jobs:   jobs,
+		jitter: DefaultScheduleJitter,
+		now:    time.Now,
+		logger: slog.Default().With("component", "scheduler"),
+	}
+}
+
+// SetJitter sets the upper bound of the random delay added to each run.
+// Negative values are treated as zero.
+func (s *Scheduler) SetJitter(jitter time.Duration) {
+	if jitter < 0 {
+		jitter = 0
+	}
+	s.jitter = jitter
+}
+
+// Add registers a job type to run on the given cron expression.
+// Schedules must be added before Start.
+func (s *Scheduler) Add(name string, jobType domain.JobType, expr string) error {
+	if !jobType.IsValid() || jobType.RequiresTarget() {
+		return fmt.Errorf("%w: job type %q cannot be scheduled", domain.ErrInvalidJobRequest, jobType)
+	}
+
+	schedule, err := ParseSchedule(expr)
+	if err != nil {
+		return fmt.Errorf("failed to add schedule %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, &scheduleEntry{
+		status: domain.ScheduleStatus{
+			Name:       name,
+			Expression: expr,
+			JobType:    jobType,
+		},
+		schedule: schedule,
+	})
+	return nil
+}
+
+// Start runs every schedule in the background until Stop is called
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+
+	ctx, stop := context.WithCancel(context.Background())
+	s.stop = stop
+
+	for _, entry := range s.entries {
+		s.wg.Add(1)
+		go s.loop(ctx, entry)
+		s.logger.Info("schedule started", "name", entry.status.Name, "expression", entry.status.Expression, "jobType", entry.status.JobType)
+	}
+}
+
+// Stop stops scheduling new runs and waits for the schedule loops to exit.
+// Jobs that were already submitted keep running in the job manager.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.stop()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Status returns every schedule with its last and next run times
+func (s *Scheduler) Status() []domain.ScheduleStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]domain.ScheduleStatus, 0, len(s.entries))
+	for _, entry := range s.entries {
+		result = append(result, entry.status)
+	}
+	return result
+}
+
+// loop waits for each scheduled time of an entry and runs it until ctx is cancelled
+func (s *Scheduler) loop(ctx context.Context, entry *scheduleEntry) {
+	defer s.wg.Done()
+
+	for {
+		next := entry.schedule.Next(s.now())
+		if next.IsZero() {
+			s.logger.Error("schedule has no upcoming runs", "name", entry.status.Name, "expression", entry.status.Expression)
+			return
+		}
+		next = next.Add(s.randomJitter())
+
+		s.mu.Lock()
+		entry.status.NextRun = &next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.run(entry)
+		}
+	}
+}
+
+// run submits the entry's job unless the job from its previous run is still unfinished
+func (s *Scheduler) run(entry *scheduleEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if previous := entry.status.LastJobID; previous != "" {
+		job, err := s.jobs.Get(previous)
+		if err == nil && !job.State.IsFinished() {
+			entry.status.SkippedRuns++
+			s.logger.Warn("skipping scheduled run, previous job still running",
+				"name", entry.status.Name,
+				"jobID", previous,
+				"state", job.State,
+			)
+			return
+		}
+	}
+
+	now := s.now().UTC()
+	entry.status.LastRun = &now
+
+	job, err := s.jobs.Submit(domain.JobRequest{Type: entry.status.JobType})
+	if err != nil {
+		entry.status.LastError = err.Error()
+		s.logger.Error("failed to submit scheduled job", "name", entry.status.Name, "error", err)
+		return
+	}
+
+	entry.status.LastJobID = job.ID
+	entry.status.LastError = ""
+	s.logger.Info("scheduled job submitted", "name", entry.status.Name, "jobID", job.ID, "jobType", job.Type)
+}
+
+// randomJitter returns a random delay below the configured jitter
+func (s *Scheduler) randomJitter() time.Duration {
+	if s.jitter <= 0 {
+		return 0
+	}
+	return rand.N(s.jitter)
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Add(t *testing.T) {
+	s := NewScheduler(NewJobManager(&mockIndexer{}))
+
+	require.NoError(t, s.Add("sync", domain.JobSync, "*/5 * * * *"))
+	assert.Error(t, s.Add("talk", domain.JobReindexTalk, "*/5 * * * *"))
+	assert.Error(t, s.Add("broken", domain.JobReindexAll, "every night"))
+
+	status := s.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, "sync", status[0].Name)
+	assert.Equal(t, domain.JobSync, status[0].JobType)
+	assert.Nil(t, status[0].LastRun)
+}
+
+func TestScheduler_RunsAndReportsTimes(t *testing.T) {
+	jobs := NewJobManager(&mockIndexer{})
+	defer jobs.Stop(context.Background())
+
+	s := NewScheduler(jobs)
+	s.SetJitter(0)
+	s.entries = append(s.entries, &scheduleEntry{
+		status:   domain.ScheduleStatus{Name: "sync", Expression: "@every 10ms", JobType: domain.JobSync},
+		schedule: &Schedule{every: 10 * time.Millisecond},
+	})
+
+	s.Start()
+
+	require.Eventually(t, func() bool {
+		return s.Status()[0].LastJobID != ""
+	}, 5*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, s.Stop(context.Background()))
+
+	status := s.Status()[0]
+	assert.NotNil(t, status.LastRun)
+	assert.NotNil(t, status.NextRun)
+	assert.Empty(t, status.LastError)
+
+	job, err := jobs.Get(status.LastJobID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobSync, job.Type)
+}
+
+func TestScheduler_SkipsWhilePreviousRunIsGoing(t *testing.T) {
+	started := make(chan struct{}, 1)
+	jobs := NewJobManager(blockingIndexer(started))
+	defer jobs.Stop(context.Background())
+
+	s := NewScheduler(jobs)
+	entry := &scheduleEntry{
+		status:   domain.ScheduleStatus{Name: "nightly", Expression: "@daily", JobType: domain.JobReindexAll},
+		schedule: &Schedule{every: time.Hour},
+	}
+
+	s.run(entry)
+	<-started
+	first := entry.status.LastJobID
+	require.NotEmpty(t, first)
+
+	// The first job is still running, so the next run is skipped
+	s.run(entry)
+	assert.Equal(t, first, entry.status.LastJobID)
+	assert.Equal(t, 1, entry.status.SkippedRuns)
+
+	_, err := jobs.Cancel(first)
+	require.NoError(t, err)
+	waitForJob(t, jobs, first)
+
+	s.run(entry)
+	assert.NotEqual(t, first, entry.status.LastJobID)
+	<-started
+	_, _ = jobs.Cancel(entry.status.LastJobID)
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/javaBin/talks-indexer/internal/ports"
+)
+
+// SearchService searches the talks in the public and private indexes
+type SearchService struct {
+	searchIndex  ports.SearchIndex
+	privateIndex string
+	publicIndex  string
+	logger       *slog.Logger
+}
+
+// NewSearchService creates a new SearchService reading from the given index aliases
+func NewSearchService(searchIndex ports.SearchIndex, privateIndex, publicIndex string) *SearchService {
+	return &SearchService{
+		searchIndex:  searchIndex,
+		privateIndex: privateIndex,
+		publicIndex:  publicIndex,
+		logger:       slog.Default().With("component", "search"),
+	}
+}
+
+// SearchPublic searches the public index, which only holds approved talks
+func (s *SearchService) SearchPublic(ctx context.Context, query domain.SearchQuery) (*domain.SearchResult, error) {
+	return s.search(ctx, s.publicIndex, query)
+}
+
+// SearchPrivate searches the private index, which holds every talk including private data
+func (s *SearchService) SearchPrivate(ctx context.Context, query domain.SearchQuery) (*domain.SearchResult, error) {
+	return s.search(ctx, s.privateIndex, query)
+}
+
+// FacetsPublic counts the approved talks matching a search per facet value
+func (s *SearchService) FacetsPublic(ctx context.Context, query domain.SearchQuery) (*domain.FacetResult, error) {
+	return s.facets(ctx, s.publicIndex, query)
+}
+
+// FacetsPrivate counts every talk matching a search per facet value
+func (s *SearchService) FacetsPrivate(ctx context.Context, query domain.SearchQuery) (*domain.FacetResult, error) {
+	return s.facets(ctx, s.privateIndex, query)
+}
+
+// SuggestPublic returns type-ahead completions from the approved talks.
+// Invalid queries are rejected with domain.ErrInvalidSearch.
+func (s *SearchService) SuggestPublic(ctx context.Context, query domain.SuggestQuery) (*domain.Suggestions, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+	return s.searchIndex.Suggest(ctx, s.publicIndex, query)
+}
+
+// search applies the default pagination, validates the query and runs it against the index.
+// Invalid queries are rejected with domain.ErrInvalidSearch.
+func (s *SearchService) search(ctx context.Context, indexName string, query domain.SearchQuery) (*domain.SearchResult, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	result, err := s.searchIndex.Search(ctx, indexName, query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("search completed",
+		"index", indexName,
+		"text", query.Text,
+		"page", query.Page,
+		"total", result.Total,
+	)
+	return result, nil
+}
+
+// facets aggregates the facets of the talks matching the query's text and filters.
+// Pagination does not apply to facets and is not validated.
+func (s *SearchService) facets(ctx context.Context, indexName string, query domain.SearchQuery) (*domain.FacetResult, error) {
+	result, err := s.searchIndex.Facets(ctx, indexName, query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("facets aggregated",
+		"index", indexName,
+		"text", query.Text,
+		"total", result.Total,
+	)
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchService_Indexes(t *testing.T) {
+	var searched []string
+	index := &mockSearchIndex{
+		searchFunc: func(ctx context.Context, indexName string, query domain.SearchQuery) (*domain.SearchResult, error) {
+			searched = append(searched, indexName)
+			return &domain.SearchResult{Total: 1, Page: query.Page, Size: query.Size}, nil
+		},
+	}
+	service := NewSearchService(index, "private", "public")
+
+	_, err := service.SearchPublic(context.Background(), domain.SearchQuery{Text: "go"})
+	require.NoError(t, err)
+	_, err = service.SearchPrivate(context.Background(), domain.SearchQuery{Text: "go"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"public", "private"}, searched)
+}
+
+func TestSearchService_Pagination(t *testing.T) {
+	var received domain.SearchQuery
+	index := &mockSearchIndex{
+		searchFunc: func(ctx context.Context, indexName string, query domain.SearchQuery) (*domain.SearchResult, error) {
+			received = query
+			return &domain.SearchResult{Page: query.Page, Size: query.Size}, nil
+		},
+	}
+	service := NewSearchService(index, "private", "public")
+
+	t.Run("defaults", func(t *testing.T) {
+		result, err := service.SearchPublic(context.Background(), domain.SearchQuery{})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, received.Page)
+		assert.Equal(t, domain.DefaultSearchSize, received.Size)
+		assert.Equal(t, 0, received.From())
+		assert.Equal(t, 1, result.Page)
+	})
+
+	t.Run("offset of later pages", func(t *testing.T) {
+		_, err := service.SearchPublic(context.Background(), domain.SearchQuery{Page: 4, Size: 25})
+		require.NoError(t, err)
+		assert.Equal(t, 75, received.From())
+	})
+
+	invalid := []struct {
+		name  string
+		query domain.SearchQuery
+	}{
+		{"negative page", domain.SearchQuery{Page: -1}},
+		{"size too large", domain.SearchQuery{Size: domain.MaxSearchSize + 1}},
+		{"beyond result window", domain.SearchQuery{Page: 200, Size: 100}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.SearchPublic(context.Background(), tt.query)
+			assert.ErrorIs(t, err, domain.ErrInvalidSearch)
+		})
+	}
+}
+
+func TestSearchService_Facets(t *testing.T) {
+	var aggregated []string
+	index := &mockSearchIndex{
+		facetsFunc: func(ctx context.Context, indexName string, query domain.SearchQuery) (*domain.FacetResult, error) {
+			aggregated = append(aggregated, indexName)
+			return &domain.FacetResult{Total: 3}, nil
+		},
+	}
+	service := NewSearchService(index, "private", "public")
+
+	// Pagination beyond the search window does not matter for facets
+	query := domain.SearchQuery{Text: "go", Page: 1000, Size: 100}
+
+	result, err := service.FacetsPublic(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Total)
+
+	_, err = service.FacetsPrivate(context.Background(), query)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"public", "private"}, aggregated)
+}
+
+func TestSearchService_SuggestPublic(t *testing.T) {
+	var received domain.SuggestQuery
+	var suggested string
+	index := &mockSearchIndex{
+		suggestFunc: func(ctx context.Context, indexName string, query domain.SuggestQuery) (*domain.Suggestions, error) {
+			suggested = indexName
+			received = query
+			return &domain.Suggestions{Titles: []domain.Suggestion{{Text: "Go in production", TalkID: "talk-1"}}}, nil
+		},
+	}
+	service := NewSearchService(index, "private", "public")
+
+	result, err := service.SuggestPublic(context.Background(), domain.SuggestQuery{Text: "go"})
+	require.NoError(t, err)
+	assert.Equal(t, "public", suggested)
+	assert.Equal(t, domain.DefaultSuggestSize, received.Size)
+	assert.Equal(t, "talk-1", result.Titles[0].TalkID)
+
+	_, err = service.SuggestPublic(context.Background(), domain.SuggestQuery{})
+	assert.ErrorIs(t, err, domain.ErrInvalidSearch)
+
+	_, err = service.SuggestPublic(context.Background(), domain.SuggestQuery{Text: "go", Size: domain.MaxSuggestSize + 1})
+	assert.ErrorIs(t, err, domain.ErrInvalidSearch)
+}
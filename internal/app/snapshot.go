@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// ExportSnapshot writes a snapshot of the index behind one of the indexer's aliases to w
+func (s *IndexerService) ExportSnapshot(ctx context.Context, alias string, w io.Writer) (*domain.SnapshotHeader, error) {
+	if err := s.checkSnapshotAlias(alias); err != nil {
+		return nil, err
+	}
+
+	header, err := s.searchIndex.ExportIndex(ctx, alias, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export %s: %w", alias, err)
+	}
+	return header, nil
+}
+
+// RestoreSnapshot restores a snapshot into a new generation of one of the indexer's
+// aliases and points the alias at it, without contacting moresleep. The generation
+// keeps the definition it was exported with, so a snapshot taken before a mapping
+// change shows up as drift until the next full reindex. If any document is rejected
+// the generation is discarded and the alias is left untouched.
+func (s *IndexerService) RestoreSnapshot(ctx context.Context, alias string, r io.Reader) (_ *domain.SnapshotResult, err error) {
+	if err := s.checkSnapshotAlias(alias); err != nil {
+		return nil, err
+	}
+
+	ctx, release, err := s.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	defer lockLost(ctx, &err)
+
+	generation := s.generationName(alias)
+	result, err := s.searchIndex.ImportIndex(ctx, generation, r)
+	if err != nil {
+		s.discardGenerations(ctx, generation)
+		return nil, fmt.Errorf("failed to restore %s: %w", alias, err)
+	}
+	result.Alias = alias
+
+	if len(result.Failures) > 0 {
+		s.discardGenerations(ctx, generation)
+		return result, fmt.Errorf("failed to restore %s: %d of %d documents were rejected", alias, len(result.Failures), result.Documents)
+	}
+
+	if err := s.searchIndex.SwapAlias(ctx, alias, generation); err != nil {
+		s.discardGenerations(ctx, generation)
+		return result, fmt.Errorf("failed to point alias %s at %s: %w", alias, generation, err)
+	}
+	s.pruneGenerations(ctx, alias, generation)
+
+	s.logger.Info("restored snapshot",
+		"alias", alias,
+		"index", generation,
+		"sourceIndex", result.SourceIndex,
+		"exportedAt", result.ExportedAt,
+		"documents", result.Indexed,
+	)
+	return result, nil
+}
+
+// checkSnapshotAlias rejects aliases the indexer does not manage, so a restore cannot
+// create generations or move aliases that nothing else prunes
+func (s *IndexerService) checkSnapshotAlias(alias string) error {
+	aliases := s.aliases()
+	if !slices.Contains(aliases, alias) {
+		return fmt.Errorf("unknown index %q, expected one of %s", alias, strings.Join(aliases, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportSnapshot(t *testing.T) {
+	t.Run("exports the index behind the alias", func(t *testing.T) {
+		var exported string
+		index := &mockSearchIndex{
+			exportIndexFunc: func(ctx context.Context, indexName string, w io.Writer) (*domain.SnapshotHeader, error) {
+				exported = indexName
+				_, err := io.WriteString(w, "snapshot\n")
+				return &domain.SnapshotHeader{Index: indexName, Documents: 3}, err
+			},
+		}
+
+		service := NewIndexerService(&mockTalkSource{}, index, "private", "public", testPrivateMapping, testPublicMapping)
+		var out bytes.Buffer
+		header, err := service.ExportSnapshot(context.Background(), "public", &out)
+
+		require.NoError(t, err)
+		assert.Equal(t, "public", exported)
+		assert.Equal(t, 3, header.Documents)
+		assert.Equal(t, "snapshot\n", out.String())
+	})
+
+	t.Run("rejects unknown aliases", func(t *testing.T) {
+		index := &mockSearchIndex{
+			exportIndexFunc: func(ctx context.Context, indexName string, w io.Writer) (*domain.SnapshotHeader, error) {
+				t.Fatal("unexpected export")
+				return nil, nil
+			},
+		}
+
+		service := NewIndexerService(&mockTalkSource{}, index, "private", "public", testPrivateMapping, testPublicMapping)
+		_, err := service.ExportSnapshot(context.Background(), "other", io.Discard)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected one of private, public")
+	})
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	clock := fixedClock(time.Date(2024, 9, 4, 12, 30, 0, 0, time.UTC))
+
+	t.Run("imports into a new generation and swaps the alias", func(t *testing.T) {
+		var imported, read string
+		index := &mockSearchIndex{
+			importIndexFunc: func(ctx context.Context, indexName string, r io.Reader) (*domain.SnapshotResult, error) {
+				imported = indexName
+				data, err := io.ReadAll(r)
+				read = string(data)
+				return &domain.SnapshotResult{Index: indexName, Documents: 2, Indexed: 2}, err
+			},
+			listIndicesFunc: func(ctx context.Context, pattern string) ([]string, error) {
+				return []string{"private_20240101000000000", "private_20240201000000000", "private_20240904123000000"}, nil
+			},
+		}
+
+		service := NewIndexerService(&mockTalkSource{}, index, "private", "public", testPrivateMapping, testPublicMapping)
+		service.now = clock
+		service.SetIndexRetention(1)
+		result, err := service.RestoreSnapshot(context.Background(), "private", strings.NewReader("snapshot\n"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "private_20240904123000000", imported)
+		assert.Equal(t, "snapshot\n", read)
+		assert.Equal(t, "private", result.Alias)
+		assert.Equal(t, 2, result.Indexed)
+		assert.Equal(t, []swapAliasCall{{Alias: "private", IndexName: "private_20240904123000000"}}, index.swapAliasCalls)
+		assert.Equal(t, []string{"private_20240101000000000"}, index.deleteIndexCalls)
+		assert.Empty(t, index.locks, "lock should be released")
+	})
+
+	t.Run("discards the generation when documents are rejected", func(t *testing.T) {
+		index := &mockSearchIndex{
+			importIndexFunc: func(ctx context.Context, indexName string, r io.Reader) (*domain.SnapshotResult, error) {
+				return &domain.SnapshotResult{
+					Index:     indexName,
+					Documents: 2,
+					Indexed:   1,
+					Failures:  []domain.BulkFailure{{DocumentID: "talk-2", Status: 400, Type: "mapper_parsing_exception"}},
+				}, nil
+			},
+		}
+
+		service := NewIndexerService(&mockTalkSource{}, index, "private", "public", testPrivateMapping, testPublicMapping)
+		service.now = clock
+		result, err := service.RestoreSnapshot(context.Background(), "public", strings.NewReader(""))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 of 2 documents were rejected")
+		require.NotNil(t, result)
+		assert.Len(t, result.Failures, 1)
+		assert.Empty(t, index.swapAliasCalls)
+		assert.Equal(t, []string{"public_20240904123000000"}, index.deleteIndexCalls)
+	})
+
+	t.Run("discards the generation when the import fails", func(t *testing.T) {
+		index := &mockSearchIndex{
+			importIndexFunc: func(ctx context.Context, indexName string, r io.Reader) (*domain.SnapshotResult, error) {
+				return nil, errors.New("not a snapshot")
+			},
+		}
+
+		service := NewIndexerService(&mockTalkSource{}, index, "private", "public", testPrivateMapping, testPublicMapping)
+		service.now = clock
+		_, err := service.RestoreSnapshot(context.Background(), "public", strings.NewReader(""))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a snapshot")
+		assert.Empty(t, index.swapAliasCalls)
+		assert.Equal(t, []string{"public_20240904123000000"}, index.deleteIndexCalls)
+	})
+
+	t.Run("fails while another operation holds the lock", func(t *testing.T) {
+		index := &mockSearchIndex{
+			acquireLockFunc: func(ctx context.Context, indexName string, lockID string, owner string, ttl time.Duration) (bool, error) {
+				return false, nil
+			},
+			importIndexFunc: func(ctx context.Context, indexName string, r io.Reader) (*domain.SnapshotResult, error) {
+				t.Fatal("unexpected import")
+				return nil, nil
+			},
+		}
+
+		service := NewIndexerService(&mockTalkSource{}, index, "private", "public", testPrivateMapping, testPublicMapping)
+		_, err := service.RestoreSnapshot(context.Background(), "public", strings.NewReader(""))
+
+		assert.ErrorIs(t, err, domain.ErrReindexInProgress)
+	})
+}
@@ -0,0 +1,246 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// speakerIndexes names the speaker index aliases maintained next to the talk indexes
+type speakerIndexes struct {
+	privateIndex   string
+	publicIndex    string
+	privateMapping string
+	publicMapping  string
+}
+
+// SetSpeakerIndexes enables the speaker indexes: one profile document per speaker,
+// aggregated across all their talks and conferences, built from the private and
+// public talks respectively. Like the talk indexes the names are used as aliases.
+// Without speaker indexes only talks are indexed.
+func (s *IndexerService) SetSpeakerIndexes(privateIndex, publicIndex, privateMapping, publicMapping string) {
+	s.speakers = &speakerIndexes{
+		privateIndex:   privateIndex,
+		publicIndex:    publicIndex,
+		privateMapping: privateMapping,
+		publicMapping:  publicMapping,
+	}
+}
+
+// speakerGenerations holds the new generations of the speaker indexes built by a full reindex
+type speakerGenerations struct {
+	private string
+	public  string
+}
+
+// buildSpeakerGenerations indexes the speaker profiles of the prepared private and
+// public talks into new generations of the speaker indexes. It returns nil if speaker
+// indexes are not enabled. On failure the generations are discarded.
+func (s *IndexerService) buildSpeakerGenerations(ctx context.Context, privateTalks, publicTalks []domain.Talk, report *domain.ReindexReport) (*speakerGenerations, error) {
+	if s.speakers == nil {
+		return nil, nil
+	}
+
+	generations := &speakerGenerations{
+		private: s.generationName(s.speakers.privateIndex),
+		public:  s.generationName(s.speakers.publicIndex),
+	}
+
+	if err := s.createGeneration(ctx, s.speakers.privateIndex, generations.private); err != nil {
+		return nil, fmt.Errorf("failed to create private speakers index: %w", err)
+	}
+	if err := s.createGeneration(ctx, s.speakers.publicIndex, generations.public); err != nil {
+		s.discardGenerations(ctx, generations.private)
+		return nil, fmt.Errorf("failed to create public speakers index: %w", err)
+	}
+
+	privateProfiles := domain.BuildSpeakerProfiles(privateTalks)
+	privateResult, err := s.searchIndex.BulkIndexSpeakers(ctx, generations.private, privateProfiles)
+	if err != nil {
+		s.discardGenerations(ctx, generations.private, generations.public)
+		return nil, fmt.Errorf("failed to index to private speakers index: %w", err)
+	}
+	report.AddBulkResult(privateResult)
+
+	publicProfiles := domain.BuildSpeakerProfiles(publicTalks)
+	publicResult, err := s.searchIndex.BulkIndexSpeakers(ctx, generations.public, publicProfiles)
+	if err != nil {
+		s.discardGenerations(ctx, generations.private, generations.public)
+		return nil, fmt.Errorf("failed to index to public speakers index: %w", err)
+	}
+	report.AddBulkResult(publicResult)
+
+	s.refresh(ctx, generations.private, generations.public)
+
+	s.logger.Info("indexed speakers",
+		"privateCount", len(privateProfiles),
+		"publicCount", len(publicProfiles),
+	)
+	return generations, nil
+}
+
+// publishSpeakerGenerations points the speaker aliases at the generations built by a
+// full reindex and prunes old generations
+func (s *IndexerService) publishSpeakerGenerations(ctx context.Context, generations *speakerGenerations) error {
+	if generations == nil {
+		return nil
+	}
+
+	if err := s.searchIndex.SwapAlias(ctx, s.speakers.privateIndex, generations.private); err != nil {
+		s.discardGenerations(ctx, generations.private, generations.public)
+		return fmt.Errorf("failed to swap private speakers index alias: %w", err)
+	}
+	if err := s.searchIndex.SwapAlias(ctx, s.speakers.publicIndex, generations.public); err != nil {
+		s.discardGenerations(ctx, generations.public)
+		return fmt.Errorf("failed to swap public speakers index alias: %w", err)
+	}
+
+	s.pruneGenerations(ctx, s.speakers.privateIndex, generations.private)
+	s.pruneGenerations(ctx, s.speakers.publicIndex, generations.public)
+	return nil
+}
+
+// discardSpeakerGenerations deletes speaker generations that will not be published
+func (s *IndexerService) discardSpeakerGenerations(ctx context.Context, generations *speakerGenerations) {
+	if generations != nil {
+		s.discardGenerations(ctx, generations.private, generations.public)
+	}
+}
+
+// indexedSpeakerIDs returns the IDs of the speakers of talks as currently indexed in
+// the private index, so speakers removed from a talk can be updated as well.
+// It returns nil if speaker indexes are not enabled.
+func (s *IndexerService) indexedSpeakerIDs(ctx context.Context, conferenceID string) ([]string, error) {
+	if s.speakers == nil {
+		return nil, nil
+	}
+
+	sources, err := s.searchIndex.ScanDocuments(ctx, s.privateIndex, conferenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexed speakers: %w", err)
+	}
+
+	var ids []string
+	for _, source := range sources {
+		ids = append(ids, sourceSpeakerIDs(source)...)
+	}
+	return ids, nil
+}
+
+// indexedTalkSpeakerIDs returns the IDs of the speakers of a single talk as currently
+// indexed in the private index. It returns nil if speaker indexes are not enabled.
+func (s *IndexerService) indexedTalkSpeakerIDs(ctx context.Context, talkID string) ([]string, error) {
+	if s.speakers == nil {
+		return nil, nil
+	}
+
+	var source map[string]interface{}
+	found, err := s.searchIndex.GetDocument(ctx, s.privateIndex, talkID, &source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexed speakers of talk %s: %w", talkID, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return sourceSpeakerIDs(source), nil
+}
+
+// updateSpeakers rebuilds the profiles of the given speakers from the talk indexes,
+// which must already be refreshed. Speakers left without talks in an index are removed
+// from the matching speakers index. It does nothing unless speaker indexes are enabled.
+func (s *IndexerService) updateSpeakers(ctx context.Context, speakerIDs []string, report *domain.ReindexReport) error {
+	if s.speakers == nil {
+		return nil
+	}
+
+	speakerIDs = uniqueStrings(speakerIDs)
+	if len(speakerIDs) == 0 {
+		return nil
+	}
+
+	for _, target := range []struct {
+		talkIndex    string
+		speakerIndex string
+	}{
+		{s.privateIndex, s.speakers.privateIndex},
+		{s.publicIndex, s.speakers.publicIndex},
+	} {
+		if err := s.ensureIndexExists(ctx, target.speakerIndex); err != nil {
+			return fmt.Errorf("failed to ensure speakers index exists: %w", err)
+		}
+
+		// The talks of the public index are already public projections, so private
+		// speaker data never reaches the public speakers index
+		talks, err := s.searchIndex.FindTalksBySpeakers(ctx, target.talkIndex, speakerIDs)
+		if err != nil {
+			return fmt.Errorf("failed to find talks of speakers in %s: %w", target.talkIndex, err)
+		}
+
+		// Co-speakers of the found talks may have other talks that were not found,
+		// so only the requested speakers are rebuilt
+		wanted := make(map[string]bool, len(speakerIDs))
+		for _, id := range speakerIDs {
+			wanted[id] = true
+		}
+		var profiles []domain.SpeakerProfile
+		for _, profile := range domain.BuildSpeakerProfiles(talks) {
+			if wanted[profile.ID] {
+				profiles = append(profiles, profile)
+				delete(wanted, profile.ID)
+			}
+		}
+
+		result, err := s.searchIndex.BulkIndexSpeakers(ctx, target.speakerIndex, profiles)
+		if err != nil {
+			return fmt.Errorf("failed to index to %s: %w", target.speakerIndex, err)
+		}
+		report.AddBulkResult(result)
+
+		var removed []string
+		for _, id := range speakerIDs {
+			if wanted[id] {
+				removed = append(removed, id)
+			}
+		}
+		if _, err := s.searchIndex.DeleteDocuments(ctx, target.speakerIndex, removed); err != nil {
+			return fmt.Errorf("failed to remove speakers without talks from %s: %w", target.speakerIndex, err)
+		}
+
+		s.logger.Info("updated speakers",
+			"index", target.speakerIndex,
+			"updated", len(profiles),
+			"removed", len(removed),
+		)
+	}
+
+	s.refresh(ctx, s.speakers.privateIndex, s.speakers.publicIndex)
+	return nil
+}
+
+// sourceSpeakerIDs returns the speaker IDs of an indexed talk source
+func sourceSpeakerIDs(source map[string]interface{}) []string {
+	speakers, _ := source["speakers"].([]interface{})
+
+	var ids []string
+	for _, speaker := range speakers {
+		fields, _ := speaker.(map[string]interface{})
+		if id, ok := fields["id"].(string); ok && id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// uniqueStrings returns the distinct values in their first order of appearance
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,315 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSpeakerPrivateMapping = `{"mappings":{"speakers":"private"}}`
+	testSpeakerPublicMapping  = `{"mappings":{"speakers":"public"}}`
+)
+
+// speakerTalks returns talks of one conference shared by speakers sp-1 and sp-2,
+// where only sp-1 has an approved talk
+func speakerTalks() []domain.Talk {
+	kari := domain.Speaker{
+		ID:          "sp-1",
+		Name:        "Kari Nordmann",
+		Data:        map[string]interface{}{"bio": "Kotlin enthusiast"},
+		PrivateData: map[string]interface{}{"residence": "Oslo"},
+	}
+	ola := domain.Speaker{ID: "sp-2", Name: "Ola Nordmann"}
+
+	return []domain.Talk{
+		{ID: "talk-1", ConferenceID: "conf-1", ConferenceSlug: "javazone2024", Status: "APPROVED", Speakers: domain.Speakers{kari}, Data: map[string]interface{}{"title": "Talk 1"}},
+		{ID: "talk-2", ConferenceID: "conf-1", ConferenceSlug: "javazone2024", Status: "SUBMITTED", Speakers: domain.Speakers{kari, ola}, Data: map[string]interface{}{"title": "Talk 2"}},
+	}
+}
+
+func newSpeakerService(source *mockTalkSource, index *mockSearchIndex) *IndexerService {
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	service.SetSpeakerIndexes("speakers_private", "speakers_public", testSpeakerPrivateMapping, testSpeakerPublicMapping)
+	return service
+}
+
+func TestReindexAll_BuildsSpeakerIndexes(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Name: "JavaZone 2024", Slug: "javazone2024"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return speakerTalks(), nil
+		},
+	}
+	var mappings []string
+	index := &mockSearchIndex{
+		createIndexFunc: func(ctx context.Context, indexName string, mapping string) error {
+			mappings = append(mappings, mapping)
+			return nil
+		},
+	}
+
+	service := newSpeakerService(source, index)
+	service.now = fixedClock(time.Date(2024, 9, 4, 12, 30, 0, 0, time.UTC))
+
+	_, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"private_20240904123000000",
+		"public_20240904123000000",
+		"speakers_private_20240904123000000",
+		"speakers_public_20240904123000000",
+	}, index.createIndexCalls)
+	assert.Equal(t, []string{testPrivateMapping, testPublicMapping, testSpeakerPrivateMapping, testSpeakerPublicMapping}, mappings)
+
+	require.Len(t, index.speakerCalls, 2)
+
+	// Every speaker with their talks of any status and private data
+	private := index.speakerCalls[0]
+	assert.Equal(t, "speakers_private_20240904123000000", private.IndexName)
+	require.Len(t, private.Speakers, 2)
+	assert.Equal(t, "sp-1", private.Speakers[0].ID)
+	assert.Equal(t, "Oslo", private.Speakers[0].Data["residence"])
+	assert.Equal(t, []domain.SpeakerTalk{
+		{ID: "talk-1", Title: "Talk 1", Status: "APPROVED", ConferenceID: "conf-1", ConferenceSlug: "javazone2024"},
+		{ID: "talk-2", Title: "Talk 2", Status: "SUBMITTED", ConferenceID: "conf-1", ConferenceSlug: "javazone2024"},
+	}, private.Speakers[0].Talks)
+	assert.Equal(t, []domain.SpeakerConference{{ID: "conf-1", Slug: "javazone2024"}}, private.Speakers[0].Conferences)
+
+	// Only speakers of approved talks, with those talks and without private data
+	public := index.speakerCalls[1]
+	assert.Equal(t, "speakers_public_20240904123000000", public.IndexName)
+	require.Len(t, public.Speakers, 1)
+	assert.Equal(t, "sp-1", public.Speakers[0].ID)
+	assert.NotContains(t, public.Speakers[0].Data, "residence")
+	assert.Equal(t, "Kotlin enthusiast", public.Speakers[0].Data["bio"])
+	require.Len(t, public.Speakers[0].Talks, 1)
+	assert.Equal(t, "talk-1", public.Speakers[0].Talks[0].ID)
+
+	// The speaker aliases are swapped after the talk aliases
+	assert.Equal(t, []swapAliasCall{
+		{Alias: "private", IndexName: "private_20240904123000000"},
+		{Alias: "public", IndexName: "public_20240904123000000"},
+		{Alias: "speakers_private", IndexName: "speakers_private_20240904123000000"},
+		{Alias: "speakers_public", IndexName: "speakers_public_20240904123000000"},
+	}, index.swapAliasCalls)
+}
+
+func TestReindexAll_SpeakerIndexFailureKeepsAliases(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Slug: "javazone2024"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return speakerTalks(), nil
+		},
+	}
+	index := &mockSearchIndex{
+		createIndexFunc: func(ctx context.Context, indexName string, mapping string) error {
+			if mapping == testSpeakerPublicMapping {
+				return assert.AnError
+			}
+			return nil
+		},
+	}
+
+	service := newSpeakerService(source, index)
+	service.now = fixedClock(time.Date(2024, 9, 4, 12, 30, 0, 0, time.UTC))
+
+	_, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+	require.ErrorIs(t, err, assert.AnError)
+
+	assert.Empty(t, index.swapAliasCalls)
+	assert.ElementsMatch(t, []string{
+		"private_20240904123000000",
+		"public_20240904123000000",
+		"speakers_private_20240904123000000",
+	}, index.deleteIndexCalls)
+}
+
+func TestReindexConference_UpdatesAffectedSpeakers(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Slug: "javazone2024"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return speakerTalks(), nil
+		},
+	}
+
+	// sp-1 also has a talk in another conference, found through the talk indexes
+	otherTalk := domain.Talk{ID: "talk-9", ConferenceID: "conf-0", ConferenceSlug: "javazone2023", Status: "APPROVED",
+		Speakers: domain.Speakers{{ID: "sp-1", Name: "Kari Nordmann"}}}
+	var searched map[string][]string
+	index := &mockSearchIndex{
+		indexExistsFunc: func(ctx context.Context, indexName string) (bool, error) {
+			return true, nil
+		},
+		speakerTalksFunc: func(ctx context.Context, indexName string, speakerIDs []string) ([]domain.Talk, error) {
+			searched[indexName] = speakerIDs
+			if indexName == "private" {
+				return append(prepareTalksForPrivateIndex(speakerTalks()), otherTalk), nil
+			}
+			return append(filterApprovedTalksForPublic(speakerTalks()), otherTalk), nil
+		},
+	}
+	searched = make(map[string][]string)
+
+	// A speaker who was removed from the conference's talks since the last reindex
+	require.NoError(t, index.PutDocument(context.Background(), "private", "talk-old", domain.Talk{
+		ID: "talk-old", ConferenceID: "conf-1", Speakers: domain.Speakers{{ID: "sp-old"}},
+	}))
+
+	service := newSpeakerService(source, index)
+
+	_, err := service.ReindexConference(context.Background(), "javazone2024", domain.ReindexOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"sp-old", "sp-1", "sp-2"}, searched["private"])
+	assert.Equal(t, []string{"sp-old", "sp-1", "sp-2"}, searched["public"])
+
+	require.Len(t, index.speakerCalls, 2)
+	private := index.speakerCalls[0]
+	assert.Equal(t, "speakers_private", private.IndexName)
+	require.Len(t, private.Speakers, 2)
+	assert.Equal(t, "sp-1", private.Speakers[0].ID)
+	assert.Len(t, private.Speakers[0].Talks, 3)
+	assert.Len(t, private.Speakers[0].Conferences, 2)
+
+	public := index.speakerCalls[1]
+	assert.Equal(t, "speakers_public", public.IndexName)
+	require.Len(t, public.Speakers, 1)
+	assert.Len(t, public.Speakers[0].Talks, 2)
+
+	// Speakers without talks are removed; sp-2 has no approved talk
+	assert.Contains(t, index.deleteDocsCalls, deleteDocsCall{IndexName: "speakers_private", IDs: []string{"sp-old"}})
+	assert.Contains(t, index.deleteDocsCalls, deleteDocsCall{IndexName: "speakers_public", IDs: []string{"sp-old", "sp-2"}})
+
+	// Speakers are rebuilt after the talk indexes are refreshed
+	assert.Equal(t, [][]string{{"private", "public"}, {"speakers_private", "speakers_public"}}, index.refreshCalls)
+}
+
+func TestReindexTalk_RemovedTalkUpdatesSpeakers(t *testing.T) {
+	source := &mockTalkSource{
+		getTalkFunc: func(ctx context.Context, talkID string) (*domain.Talk, error) {
+			return nil, domain.ErrNotFound
+		},
+	}
+	index := &mockSearchIndex{
+		indexExistsFunc: func(ctx context.Context, indexName string) (bool, error) {
+			return true, nil
+		},
+	}
+	require.NoError(t, index.PutDocument(context.Background(), "private", "talk-1", domain.Talk{
+		ID: "talk-1", Speakers: domain.Speakers{{ID: "sp-1"}},
+	}))
+
+	service := newSpeakerService(source, index)
+
+	_, err := service.ReindexTalk(context.Background(), "talk-1", domain.ReindexOptions{})
+	require.NoError(t, err)
+
+	assert.Contains(t, index.deleteDocsCalls, deleteDocsCall{IndexName: "speakers_private", IDs: []string{"sp-1"}})
+	assert.Contains(t, index.deleteDocsCalls, deleteDocsCall{IndexName: "speakers_public", IDs: []string{"sp-1"}})
+}
+
+func TestReindexConference_WithoutSpeakerIndexes(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Slug: "javazone2024"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return speakerTalks(), nil
+		},
+	}
+	index := &mockSearchIndex{
+		indexExistsFunc: func(ctx context.Context, indexName string) (bool, error) {
+			return true, nil
+		},
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+
+	_, err := service.ReindexConference(context.Background(), "javazone2024", domain.ReindexOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, index.speakerCalls)
+}
+
+func TestSyncChanges_UpdatesSpeakersOfChangedTalks(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Slug: "javazone2024"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return []domain.Talk{
+				{ID: "changed", ConferenceID: "conf-1", Status: "APPROVED", LastUpdated: timePtr(base.Add(time.Hour)),
+					Speakers: domain.Speakers{{ID: "sp-1"}}},
+				{ID: "unchanged", ConferenceID: "conf-1", Status: "APPROVED", LastUpdated: timePtr(base),
+					Speakers: domain.Speakers{{ID: "sp-2"}}},
+			}, nil
+		},
+	}
+
+	var searched []string
+	index := &mockSearchIndex{
+		lastUpdatedFunc: func(ctx context.Context, indexName string, conferenceID string) (map[string]time.Time, error) {
+			return map[string]time.Time{"changed": base, "unchanged": base, "deleted": base}, nil
+		},
+		speakerTalksFunc: func(ctx context.Context, indexName string, speakerIDs []string) ([]domain.Talk, error) {
+			searched = speakerIDs
+			return nil, nil
+		},
+	}
+	require.NoError(t, index.PutDocument(context.Background(), "private", "deleted", domain.Talk{
+		ID: "deleted", ConferenceID: "conf-1", Speakers: domain.Speakers{{ID: "sp-3"}},
+	}))
+
+	service := newSpeakerService(source, index)
+
+	_, err := service.SyncChanges(context.Background(), domain.ReindexOptions{})
+	require.NoError(t, err)
+
+	// Speakers of the changed and deleted talks are updated, those of unchanged talks are not
+	assert.ElementsMatch(t, []string{"sp-1", "sp-3"}, searched)
+}
+
+func TestReindexAll_SpeakerAliasFailureIsPartial(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Slug: "javazone2024"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return speakerTalks(), nil
+		},
+	}
+	index := &mockSearchIndex{
+		swapAliasFunc: func(ctx context.Context, alias string, indexName string) error {
+			if alias == "speakers_public" {
+				return assert.AnError
+			}
+			return nil
+		},
+	}
+
+	service := newSpeakerService(source, index)
+	service.now = fixedClock(time.Date(2024, 9, 4, 12, 30, 0, 0, time.UTC))
+
+	report, err := service.ReindexAll(context.Background(), domain.ReindexOptions{})
+
+	// The talk aliases were already swapped, so the run is reported as partial
+	require.NoError(t, err)
+	assert.True(t, report.IsPartial())
+	require.Len(t, report.Warnings, 1)
+	assert.Contains(t, report.Warnings[0], "speaker indexes were not updated")
+	assert.Equal(t, 2, report.PrivateCount)
+	assert.Contains(t, index.deleteIndexCalls, "speakers_public_20240904123000000")
+}
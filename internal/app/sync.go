@@ -0,0 +1,330 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// DefaultStateIndex is the index used to store indexer bookkeeping such as sync watermarks
+const DefaultStateIndex = "talks_indexer_state"
+
+// SetStateIndex sets the index used to store sync watermarks and other indexer state
+func (s *IndexerService) SetStateIndex(stateIndex string) {
+	s.stateIndex = stateIndex
+}
+
+// SyncChanges performs an incremental sync of all conferences.
+// Each conference has a stored watermark holding the newest lastUpdated value and
+// the talk count seen at the previous sync; conferences where neither has changed
+// are skipped without touching the index. For the remaining conferences only talks
+// that are new, or whose lastUpdated is newer than the indexed copy, are written,
+// and indexed talks that no longer exist in the source are deleted.
+// Like a conference reindex, a conference whose talk list shrank beyond its threshold
+// is refused before anything is written to it, unless opts.Force is set.
+// A fetch error that means the remaining fetches would fail as well, such as rejected
+// credentials or an unavailable source, stops the sync after the speakers of the
+// conferences synced so far are updated; other fetch errors skip the conference.
+// The sync counts are returned in the report's Sync field.
+// Indexes with drifted mappings are handled according to the mapping drift policy.
+func (s *IndexerService) SyncChanges(ctx context.Context, opts domain.ReindexOptions) (_ *domain.ReindexReport, err error) {
+	if opts.DryRun {
+		return nil, fmt.Errorf("incremental sync does not support dry runs")
+	}
+
+	s.logger.Info("starting incremental sync of all conferences", "force", opts.Force)
+
+	ctx, release, err := s.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	defer lockLost(ctx, &err)
+
+	report := domain.NewReindexReport("sync", "", s.now())
+	report.Sync = &domain.SyncResult{}
+	defer func() { report.Finish(s.now()) }()
+
+	rebuild, err := s.guardMappings(ctx, opts, report)
+	if err != nil {
+		return report, err
+	}
+	if rebuild {
+		return report, s.reindexAll(ctx, opts, report)
+	}
+
+	conferences, err := s.source.GetConferences(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to fetch conferences: %w", err)
+	}
+
+	if err := s.ensureIndexExists(ctx, s.privateIndex); err != nil {
+		return report, fmt.Errorf("failed to ensure private index exists: %w", err)
+	}
+	if err := s.ensureIndexExists(ctx, s.publicIndex); err != nil {
+		return report, fmt.Errorf("failed to ensure public index exists: %w", err)
+	}
+
+	var changedSpeakers []string
+	var fatalErr error
+	for _, conf := range conferences {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		reportProgress(ctx, conferenceProgress(conf, domain.ProgressRunning, 0, nil))
+
+		talks, err := s.source.GetTalks(ctx, conf.ID)
+		if err != nil {
+			s.logger.Error("failed to fetch talks for conference",
+				"conferenceID", conf.ID,
+				"conferenceName", conf.Name,
+				"error", err,
+			)
+			reportProgress(ctx, conferenceProgress(conf, domain.ProgressFailed, 0, err))
+			if isFatalFetchError(err) {
+				fatalErr = err
+				break
+			}
+			report.Skip(conf, err)
+			continue
+		}
+
+		report.Sync.Conferences++
+
+		speakerIDs, err := s.syncConference(ctx, conf, talks, opts, report)
+		if err != nil {
+			reportProgress(ctx, conferenceProgress(conf, domain.ProgressFailed, len(talks), err))
+			return report, err
+		}
+		changedSpeakers = append(changedSpeakers, speakerIDs...)
+
+		reportProgress(ctx, conferenceProgress(conf, domain.ProgressDone, len(talks), nil))
+	}
+
+	s.refresh(ctx, s.privateIndex, s.publicIndex)
+
+	// Conferences synced before a fatal error have advanced their watermarks, so their
+	// speakers are updated now rather than left for a sync that would skip them
+	if err := s.updateSpeakers(ctx, changedSpeakers, report); err != nil {
+		return report, err
+	}
+	if fatalErr != nil {
+		return report, fatalErr
+	}
+
+	result := report.Sync
+	s.logger.Info("incremental sync completed",
+		"conferences", result.Conferences,
+		"skippedConferences", result.SkippedConferences,
+		"created", result.Created,
+		"updated", result.Updated,
+		"unchanged", result.Unchanged,
+		"deleted", result.Deleted,
+		"failedConferences", len(report.Skipped),
+		"bulkFailures", len(report.BulkFailures),
+	)
+
+	return report, nil
+}
+
+// syncConference writes the changed talks of a single conference and advances its watermark.
+// The watermark is left where it was if Elasticsearch rejected any document, so the
+// next sync does not skip the conference and writes the rejected talks again.
+// It returns the IDs of the speakers whose profiles the changes affect.
+func (s *IndexerService) syncConference(ctx context.Context, conf domain.Conference, talks []domain.Talk, opts domain.ReindexOptions, report *domain.ReindexReport) ([]string, error) {
+	result := report.Sync
+	conferenceReport := domain.ConferenceReport{
+		ConferenceID:   conf.ID,
+		ConferenceName: conf.Name,
+		Slug:           conf.Slug,
+	}
+
+	newest := newestLastUpdated(talks)
+
+	watermark, err := s.getWatermark(ctx, conf.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if watermark != nil && watermark.TalkCount == len(talks) && !newest.After(watermark.LastUpdated) {
+		s.logger.Debug("conference unchanged since last sync", "conferenceID", conf.ID)
+		result.SkippedConferences++
+		result.Unchanged += len(talks)
+		report.AddConference(conferenceReport)
+		return nil, nil
+	}
+
+	// Refuse to remove a suspicious share of the conference's talks, which usually
+	// means the source returned incomplete data
+	if err := s.guardShrink(ctx, conf.ID, talks, opts, report); err != nil {
+		return nil, err
+	}
+
+	indexed, err := s.searchIndex.GetLastUpdated(ctx, s.privateIndex, conf.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexed talks for conference %s: %w", conf.ID, err)
+	}
+
+	var changed []domain.Talk
+	for _, talk := range talks {
+		previous, exists := indexed[talk.ID]
+		switch {
+		case !exists:
+			result.Created++
+			changed = append(changed, talk)
+		case talk.LastUpdated == nil || talk.LastUpdated.After(previous):
+			// Talks without a timestamp cannot be compared, so they are always rewritten
+			result.Updated++
+			changed = append(changed, talk)
+		default:
+			result.Unchanged++
+		}
+	}
+
+	removed := talkIDsNotIn(indexed, talks)
+
+	// Speakers of changed and removed talks, before and after the change, need their
+	// profiles updated
+	var speakerIDs []string
+	rejected := 0
+	if len(changed) > 0 || len(removed) > 0 {
+		previous, err := s.indexedSpeakerIDs(ctx, conf.ID)
+		if err != nil {
+			return nil, err
+		}
+		speakerIDs = append(previous, domain.SpeakerIDs(changed)...)
+	}
+
+	if len(changed) > 0 {
+		privateResult, err := s.searchIndex.BulkIndex(ctx, s.privateIndex, prepareTalksForPrivateIndex(changed))
+		if err != nil {
+			return nil, fmt.Errorf("failed to index to private index: %w", err)
+		}
+		report.AddBulkResult(privateResult)
+
+		publicTalks := filterApprovedTalksForPublic(changed)
+		publicResult, err := s.searchIndex.BulkIndex(ctx, s.publicIndex, publicTalks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to index to public index: %w", err)
+		}
+		report.AddBulkResult(publicResult)
+		rejected = len(privateResult.Failures) + len(publicResult.Failures)
+
+		// Changed talks that are no longer approved must leave the public index
+		var unpublished []string
+		for _, talk := range changed {
+			if !domain.TalkStatus(talk.Status).IsPublic() {
+				unpublished = append(unpublished, talk.ID)
+			}
+		}
+		unpublishedDeleted, err := s.searchIndex.DeleteDocuments(ctx, s.publicIndex, unpublished)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove unpublished talks from public index: %w", err)
+		}
+
+		conferenceReport.PrivateCount = len(changed)
+		conferenceReport.PublicCount = len(publicTalks)
+		conferenceReport.PublicDeleted = unpublishedDeleted
+	}
+
+	// Talks that disappeared from the source are removed from both indexes
+	if len(removed) > 0 {
+		privateDeleted, err := s.searchIndex.DeleteDocuments(ctx, s.privateIndex, removed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove deleted talks from private index: %w", err)
+		}
+		publicDeleted, err := s.searchIndex.DeleteDocuments(ctx, s.publicIndex, removed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove deleted talks from public index: %w", err)
+		}
+		result.Deleted += privateDeleted
+		conferenceReport.PrivateDeleted = privateDeleted
+		conferenceReport.PublicDeleted += publicDeleted
+	}
+
+	report.AddConference(conferenceReport)
+
+	s.logger.Info("synced conference",
+		"conferenceID", conf.ID,
+		"conferenceName", conf.Name,
+		"changed", len(changed),
+		"deleted", len(removed),
+	)
+
+	if rejected > 0 {
+		s.logger.Warn("not advancing sync watermark, documents were rejected",
+			"conferenceID", conf.ID,
+			"rejected", rejected,
+		)
+		return speakerIDs, nil
+	}
+
+	err = s.putWatermark(ctx, domain.SyncWatermark{
+		ConferenceID: conf.ID,
+		LastUpdated:  newest,
+		TalkCount:    len(talks),
+		SyncedAt:     s.now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return speakerIDs, nil
+}
+
+// getWatermark loads the stored sync watermark for a conference, or nil if it has never been synced
+func (s *IndexerService) getWatermark(ctx context.Context, conferenceID string) (*domain.SyncWatermark, error) {
+	var watermark domain.SyncWatermark
+	found, err := s.searchIndex.GetDocument(ctx, s.stateIndex, watermarkID(conferenceID), &watermark)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync watermark for conference %s: %w", conferenceID, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &watermark, nil
+}
+
+// putWatermark stores the sync watermark for a conference
+func (s *IndexerService) putWatermark(ctx context.Context, watermark domain.SyncWatermark) error {
+	if err := s.searchIndex.PutDocument(ctx, s.stateIndex, watermarkID(watermark.ConferenceID), watermark); err != nil {
+		return fmt.Errorf("failed to store sync watermark for conference %s: %w", watermark.ConferenceID, err)
+	}
+	return nil
+}
+
+// watermarkID returns the state document ID holding a conference's sync watermark
+func watermarkID(conferenceID string) string {
+	return "sync-" + conferenceID
+}
+
+// newestLastUpdated returns the most recent lastUpdated value among the talks
+func newestLastUpdated(talks []domain.Talk) time.Time {
+	var newest time.Time
+	for _, talk := range talks {
+		if talk.LastUpdated != nil && talk.LastUpdated.After(newest) {
+			newest = *talk.LastUpdated
+		}
+	}
+	return newest
+}
+
+// talkIDsNotIn returns the IDs of indexed documents that are missing from the talks
+func talkIDsNotIn(indexed map[string]time.Time, talks []domain.Talk) []string {
+	present := make(map[string]bool, len(talks))
+	for _, talk := range talks {
+		present[talk.ID] = true
+	}
+
+	var missing []string
+	for id := range indexed {
+		if !present[id] {
+			missing = append(missing, id)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
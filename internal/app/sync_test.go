@@ -0,0 +1,347 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timePtr returns a pointer to the given time
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestSyncChanges_WritesOnlyNewAndUpdatedTalks(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Slug: "javazone2024"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return []domain.Talk{
+				{ID: "new", ConferenceID: "conf-1", Status: "APPROVED", LastUpdated: timePtr(base.Add(time.Hour))},
+				{ID: "updated", ConferenceID: "conf-1", Status: "SUBMITTED", LastUpdated: timePtr(base.Add(2 * time.Hour))},
+				{ID: "unchanged", ConferenceID: "conf-1", Status: "APPROVED", LastUpdated: timePtr(base)},
+			}, nil
+		},
+	}
+
+	index := &mockSearchIndex{
+		lastUpdatedFunc: func(ctx context.Context, indexName string, conferenceID string) (map[string]time.Time, error) {
+			assert.Equal(t, "private", indexName)
+			assert.Equal(t, "conf-1", conferenceID)
+			return map[string]time.Time{
+				"updated":   base,
+				"unchanged": base,
+			}, nil
+		},
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	report, err := service.SyncChanges(context.Background(), domain.ReindexOptions{})
+
+	require.NoError(t, err)
+	result := report.Sync
+	assert.Equal(t, &domain.SyncResult{Conferences: 1, Created: 1, Updated: 1, Unchanged: 1}, result)
+
+	require.Len(t, index.bulkIndexCalls, 2)
+	assert.Equal(t, "private", index.bulkIndexCalls[0].IndexName)
+	assert.Len(t, index.bulkIndexCalls[0].Talks, 2)
+	assert.Equal(t, "public", index.bulkIndexCalls[1].IndexName)
+	require.Len(t, index.bulkIndexCalls[1].Talks, 1)
+	assert.Equal(t, "new", index.bulkIndexCalls[1].Talks[0].ID)
+
+	// The watermark should record the newest timestamp and talk count
+	watermark, err := service.getWatermark(context.Background(), "conf-1")
+	require.NoError(t, err)
+	require.NotNil(t, watermark)
+	assert.Equal(t, base.Add(2*time.Hour), watermark.LastUpdated)
+	assert.Equal(t, 3, watermark.TalkCount)
+}
+
+func TestSyncChanges_SkipsConferenceAtWatermark(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return []domain.Talk{
+				{ID: "talk-1", ConferenceID: "conf-1", LastUpdated: timePtr(base)},
+				{ID: "talk-2", ConferenceID: "conf-1", LastUpdated: timePtr(base.Add(-time.Hour))},
+			}, nil
+		},
+	}
+
+	index := &mockSearchIndex{
+		lastUpdatedFunc: func(ctx context.Context, indexName string, conferenceID string) (map[string]time.Time, error) {
+			t.Fatal("index should not be queried for an unchanged conference")
+			return nil, nil
+		},
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	require.NoError(t, service.putWatermark(context.Background(), domain.SyncWatermark{
+		ConferenceID: "conf-1",
+		LastUpdated:  base,
+		TalkCount:    2,
+	}))
+
+	report, err := service.SyncChanges(context.Background(), domain.ReindexOptions{})
+
+	require.NoError(t, err)
+	result := report.Sync
+	assert.Equal(t, &domain.SyncResult{Conferences: 1, SkippedConferences: 1, Unchanged: 2}, result)
+	assert.Empty(t, index.bulkIndexCalls)
+}
+
+func TestSyncChanges_TalkCountChangeForcesComparison(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return []domain.Talk{
+				{ID: "talk-1", ConferenceID: "conf-1", LastUpdated: timePtr(base)},
+			}, nil
+		},
+	}
+
+	index := &mockSearchIndex{}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	require.NoError(t, service.putWatermark(context.Background(), domain.SyncWatermark{
+		ConferenceID: "conf-1",
+		LastUpdated:  base,
+		TalkCount:    2,
+	}))
+
+	report, err := service.SyncChanges(context.Background(), domain.ReindexOptions{})
+
+	require.NoError(t, err)
+	result := report.Sync
+	assert.Equal(t, 0, result.SkippedConferences)
+	assert.Equal(t, 1, result.Created)
+}
+
+func TestSyncChanges_ResendsRejectedTalks(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return []domain.Talk{
+				{ID: "talk-1", ConferenceID: "conf-1", Status: "APPROVED", LastUpdated: timePtr(base)},
+				{ID: "talk-2", ConferenceID: "conf-1", Status: "APPROVED", LastUpdated: timePtr(base)},
+			}, nil
+		},
+	}
+
+	// The private index holds the talks that were accepted; talk-2 is rejected once
+	indexed := map[string]time.Time{}
+	rejectTalk2 := true
+	index := &mockSearchIndex{
+		lastUpdatedFunc: func(ctx context.Context, indexName string, conferenceID string) (map[string]time.Time, error) {
+			return indexed, nil
+		},
+		bulkIndexFunc: func(ctx context.Context, indexName string, talks []domain.Talk) (*domain.BulkResult, error) {
+			result := &domain.BulkResult{}
+			for _, talk := range talks {
+				if talk.ID == "talk-2" && rejectTalk2 {
+					result.Failures = append(result.Failures, domain.BulkFailure{Index: indexName, DocumentID: talk.ID, Status: 400, Type: "mapper_parsing_exception"})
+					continue
+				}
+				result.Indexed++
+				if indexName == "private" {
+					indexed[talk.ID] = *talk.LastUpdated
+				}
+			}
+			return result, nil
+		},
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+
+	report, err := service.SyncChanges(context.Background(), domain.ReindexOptions{})
+	require.NoError(t, err)
+	assert.True(t, report.IsPartial())
+
+	// The watermark is not advanced past the rejected talk
+	watermark, err := service.getWatermark(context.Background(), "conf-1")
+	require.NoError(t, err)
+	assert.Nil(t, watermark)
+
+	rejectTalk2 = false
+	index.bulkIndexCalls = nil
+
+	report, err = service.SyncChanges(context.Background(), domain.ReindexOptions{})
+	require.NoError(t, err)
+	assert.False(t, report.IsPartial())
+	assert.Equal(t, &domain.SyncResult{Conferences: 1, Created: 1, Unchanged: 1}, report.Sync)
+
+	require.NotEmpty(t, index.bulkIndexCalls)
+	require.Len(t, index.bulkIndexCalls[0].Talks, 1)
+	assert.Equal(t, "talk-2", index.bulkIndexCalls[0].Talks[0].ID)
+
+	watermark, err = service.getWatermark(context.Background(), "conf-1")
+	require.NoError(t, err)
+	require.NotNil(t, watermark)
+	assert.Equal(t, 2, watermark.TalkCount)
+}
+
+func TestSyncChanges_RemovesDeletedAndUnpublishedTalks(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return []domain.Talk{
+				{ID: "withdrawn", ConferenceID: "conf-1", Status: "WITHDRAWN", LastUpdated: timePtr(base.Add(time.Hour))},
+			}, nil
+		},
+	}
+
+	index := &mockSearchIndex{
+		lastUpdatedFunc: func(ctx context.Context, indexName string, conferenceID string) (map[string]time.Time, error) {
+			return map[string]time.Time{
+				"withdrawn": base,
+				"deleted":   base,
+			}, nil
+		},
+	}
+
+	service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+	report, err := service.SyncChanges(context.Background(), domain.ReindexOptions{})
+
+	require.NoError(t, err)
+	result := report.Sync
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Deleted)
+	assert.Equal(t, []deleteDocsCall{
+		{IndexName: "public", IDs: []string{"withdrawn"}},
+		{IndexName: "private", IDs: []string{"deleted"}},
+		{IndexName: "public", IDs: []string{"deleted"}},
+	}, index.deleteDocsCalls)
+}
+
+func TestSyncChanges_ShrinkGuard(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	newIndex := func() *mockSearchIndex {
+		return &mockSearchIndex{
+			lastUpdatedFunc: func(ctx context.Context, indexName string, conferenceID string) (map[string]time.Time, error) {
+				return map[string]time.Time{"talk-1": base, "talk-2": base, "talk-3": base}, nil
+			},
+			countFunc: func(ctx context.Context, indexName string) (map[string]int, error) {
+				return map[string]int{"conf-1": 3}, nil
+			},
+		}
+	}
+
+	// moresleep returns an empty talk list for a conference that has talks indexed
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1"}}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			return []domain.Talk{}, nil
+		},
+	}
+
+	t.Run("refused", func(t *testing.T) {
+		index := newIndex()
+		service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+
+		report, err := service.SyncChanges(context.Background(), domain.ReindexOptions{})
+
+		require.ErrorIs(t, err, domain.ErrIndexShrink)
+		require.NotNil(t, report)
+		require.Len(t, report.ShrinkViolations, 2)
+		assert.Equal(t, "conf-1", report.ShrinkViolations[0].ConferenceID)
+
+		// The indexed talks are left in place and the conference is synced again next time
+		assert.Empty(t, index.deleteDocsCalls)
+		assert.Empty(t, index.bulkIndexCalls)
+		watermark, err := service.getWatermark(context.Background(), "conf-1")
+		require.NoError(t, err)
+		assert.Nil(t, watermark)
+	})
+
+	t.Run("forced", func(t *testing.T) {
+		index := newIndex()
+		service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+
+		report, err := service.SyncChanges(context.Background(), domain.ReindexOptions{Force: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, report.Sync.Deleted)
+		assert.Len(t, report.ShrinkViolations, 2)
+		assert.Equal(t, []deleteDocsCall{
+			{IndexName: "private", IDs: []string{"talk-1", "talk-2", "talk-3"}},
+			{IndexName: "public", IDs: []string{"talk-1", "talk-2", "talk-3"}},
+		}, index.deleteDocsCalls)
+	})
+}
+
+func TestSyncChanges_RejectsDryRun(t *testing.T) {
+	index := &mockSearchIndex{}
+	service := NewIndexerService(&mockTalkSource{}, index, "private", "public", testPrivateMapping, testPublicMapping)
+
+	_, err := service.SyncChanges(context.Background(), domain.ReindexOptions{DryRun: true})
+
+	require.Error(t, err)
+	assert.Empty(t, index.locks)
+}
+
+func TestSyncChanges_FetchConferencesError(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return nil, errors.New("connection error")
+		},
+	}
+
+	service := NewIndexerService(source, &mockSearchIndex{}, "private", "public", testPrivateMapping, testPublicMapping)
+	_, err := service.SyncChanges(context.Background(), domain.ReindexOptions{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to fetch conferences")
+}
+
+func TestSyncChanges_FatalFetchErrorAborts(t *testing.T) {
+	for _, fatal := range []error{domain.ErrUnauthorized, domain.ErrSourceUnavailable} {
+		t.Run(fatal.Error(), func(t *testing.T) {
+			var fetched []string
+			source := &mockTalkSource{
+				getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+					return testConferences(3), nil
+				},
+				getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+					fetched = append(fetched, conferenceID)
+					return nil, errors.Join(errors.New("moresleep request failed"), fatal)
+				},
+			}
+			index := &mockSearchIndex{}
+
+			service := NewIndexerService(source, index, "private", "public", testPrivateMapping, testPublicMapping)
+			report, err := service.SyncChanges(context.Background(), domain.ReindexOptions{})
+
+			assert.ErrorIs(t, err, fatal)
+			assert.Len(t, fetched, 1, "remaining conferences should not be fetched")
+			assert.Empty(t, report.Skipped)
+			assert.Empty(t, index.bulkIndexCalls)
+		})
+	}
+}
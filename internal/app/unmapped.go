@@ -0,0 +1,200 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// UnmappedFields fetches the talks of all conferences and lists the fields of their
+// private and public documents that have no explicit mapping in the compiled mappings.
+// Conferences whose talks cannot be fetched are skipped and listed in the report.
+// Nothing is written, so no lock is taken.
+func (s *IndexerService) UnmappedFields(ctx context.Context) (*domain.UnmappedFieldReport, error) {
+	conferences, err := s.source.GetConferences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch conferences: %w", err)
+	}
+
+	results, err := s.fetchConferenceTalks(ctx, conferences)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.UnmappedFieldReport{
+		Private: []domain.UnmappedField{},
+		Public:  []domain.UnmappedField{},
+		Skipped: []domain.SkippedConference{},
+	}
+
+	var talks []domain.Talk
+	for _, result := range results {
+		if result.err != nil {
+			report.Skipped = append(report.Skipped, domain.SkippedConference{
+				ConferenceID:   result.conference.ID,
+				ConferenceName: result.conference.Name,
+				Error:          result.err.Error(),
+			})
+			continue
+		}
+		talks = append(talks, result.talks...)
+	}
+	report.Talks = len(talks)
+
+	if report.Private, err = unmappedFields(s.privateIndexMapping, prepareTalksForPrivateIndex(talks)); err != nil {
+		return nil, fmt.Errorf("failed to inspect private documents: %w", err)
+	}
+	if report.Public, err = unmappedFields(s.publicIndexMapping, filterApprovedTalksForPublic(talks)); err != nil {
+		return nil, fmt.Errorf("failed to inspect public documents: %w", err)
+	}
+
+	s.logger.Info("inspected unmapped fields",
+		"talks", report.Talks,
+		"privateUnmapped", len(report.Private),
+		"publicUnmapped", len(report.Public),
+	)
+
+	return report, nil
+}
+
+// fieldUsage collects the value types and talk count of one unmapped field
+type fieldUsage struct {
+	types map[string]bool
+	talks int
+}
+
+// unmappedFields returns the fields of the documents that are not explicitly mapped
+func unmappedFields(mapping string, docs []domain.Talk) ([]domain.UnmappedField, error) {
+	mapped, err := mappedFields(mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]*fieldUsage)
+	for _, doc := range docs {
+		source, err := documentSource(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		seen := make(map[string]string)
+		collectUnmapped("", source, mapped, seen)
+		for path, kind := range seen {
+			u, ok := usage[path]
+			if !ok {
+				u = &fieldUsage{types: make(map[string]bool)}
+				usage[path] = u
+			}
+			u.types[kind] = true
+			u.talks++
+		}
+	}
+
+	fields := make([]domain.UnmappedField, 0, len(usage))
+	for path, u := range usage {
+		types := make([]string, 0, len(u.types))
+		for kind := range u.types {
+			types = append(types, kind)
+		}
+		sort.Strings(types)
+		fields = append(fields, domain.UnmappedField{Path: path, Types: types, Talks: u.talks})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+
+	return fields, nil
+}
+
+// collectUnmapped walks a document source and records the dotted path and value type of
+// every field missing from mapped. Unmapped objects are recorded as a whole. Arrays of
+// objects are walked element by element under the array's path, like Elasticsearch does.
+// Null values and empty arrays are skipped since they are never mapped.
+func collectUnmapped(prefix string, value map[string]interface{}, mapped map[string]bool, seen map[string]string) {
+	for key, v := range value {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		kind := valueKind(v)
+		if kind == "" {
+			continue
+		}
+		if !mapped[path] {
+			if _, ok := seen[path]; !ok {
+				seen[path] = kind
+			}
+			continue
+		}
+
+		switch v := v.(type) {
+		case map[string]interface{}:
+			collectUnmapped(path, v, mapped, seen)
+		case []interface{}:
+			for _, element := range v {
+				if object, ok := element.(map[string]interface{}); ok {
+					collectUnmapped(path, object, mapped, seen)
+				}
+			}
+		}
+	}
+}
+
+// valueKind names the JSON type of a value. Arrays take the type of their first
+// non-null element; an empty result means the value is not mapped at all.
+func valueKind(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		for _, element := range v {
+			if kind := valueKind(element); kind != "" {
+				return kind
+			}
+		}
+	}
+	return ""
+}
+
+// indexMapping is the part of an index definition that lists the explicit fields
+type indexMapping struct {
+	Mappings mappingProperties `json:"mappings"`
+}
+
+// mappingProperties is an object field, or the root of a mapping, with its subfields
+type mappingProperties struct {
+	Properties map[string]mappingProperties `json:"properties"`
+}
+
+// mappedFields returns the dotted paths of every explicitly mapped field, including
+// object and nested fields
+func mappedFields(mapping string) (map[string]bool, error) {
+	var definition indexMapping
+	if err := json.Unmarshal([]byte(mapping), &definition); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping: %w", err)
+	}
+
+	fields := make(map[string]bool)
+	addMappedFields("", definition.Mappings, fields)
+	return fields, nil
+}
+
+// addMappedFields adds the paths of the properties below prefix to fields
+func addMappedFields(prefix string, object mappingProperties, fields map[string]bool) {
+	for name, property := range object.Properties {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		fields[path] = true
+		addMappedFields(path, property, fields)
+	}
+}
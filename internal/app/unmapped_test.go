@@ -0,0 +1,109 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testUnmappedMapping = `{
+  "mappings": {
+    "properties": {
+      "id": {"type": "keyword"},
+      "conferenceId": {"type": "keyword"},
+      "conferenceSlug": {"type": "keyword"},
+      "conferenceName": {"type": "keyword"},
+      "status": {"type": "keyword"},
+      "data": {
+        "properties": {
+          "title": {"type": "text"},
+          "feedback": {"properties": {"count": {"type": "integer"}}}
+        }
+      },
+      "speakers": {
+        "type": "nested",
+        "properties": {
+          "id": {"type": "keyword"},
+          "name": {"type": "text"},
+          "data": {"properties": {"bio": {"type": "text"}}}
+        }
+      }
+    }
+  }
+}`
+
+func TestUnmappedFields(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{
+				{ID: "conf-1", Name: "JavaZone 2024", Slug: "javazone2024"},
+				{ID: "conf-2", Name: "JavaZone 2025", Slug: "javazone2025"},
+			}, nil
+		},
+		getTalksFunc: func(ctx context.Context, conferenceID string) ([]domain.Talk, error) {
+			if conferenceID == "conf-2" {
+				return nil, errors.New("moresleep timeout")
+			}
+			return []domain.Talk{
+				{
+					ID: "talk-1", ConferenceID: "conf-1", Status: "APPROVED",
+					Data: map[string]interface{}{
+						"title":    "Go",
+						"sponsor":  "Acme",
+						"feedback": map[string]interface{}{"count": 3.0, "rating": 4.5},
+					},
+					PrivateData: map[string]interface{}{"reviewer": map[string]interface{}{"name": "Kari"}},
+					Speakers: domain.Speakers{{
+						ID: "sp-1", Name: "Ola",
+						Data: map[string]interface{}{"bio": "Gopher", "mastodon": "@ola"},
+					}},
+				},
+				{
+					ID: "talk-2", ConferenceID: "conf-1", Status: "SUBMITTED",
+					Data: map[string]interface{}{"title": "Kotlin", "sponsor": true, "tags": []interface{}{}},
+				},
+			}, nil
+		},
+	}
+
+	service := NewIndexerService(source, &mockSearchIndex{}, "private", "public", testUnmappedMapping, testUnmappedMapping)
+	report, err := service.UnmappedFields(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.Talks)
+	assert.Equal(t, []domain.SkippedConference{
+		{ConferenceID: "conf-2", ConferenceName: "JavaZone 2025", Error: "moresleep timeout"},
+	}, report.Skipped)
+
+	// Private data is merged into data, and unmapped objects are listed as a whole
+	assert.Equal(t, []domain.UnmappedField{
+		{Path: "data.feedback.rating", Types: []string{"number"}, Talks: 1},
+		{Path: "data.reviewer", Types: []string{"object"}, Talks: 1},
+		{Path: "data.sponsor", Types: []string{"boolean", "string"}, Talks: 2},
+		{Path: "speakers.data.mastodon", Types: []string{"string"}, Talks: 1},
+	}, report.Private)
+
+	// Only approved talks without private data reach the public index
+	assert.Equal(t, []domain.UnmappedField{
+		{Path: "data.feedback.rating", Types: []string{"number"}, Talks: 1},
+		{Path: "data.sponsor", Types: []string{"string"}, Talks: 1},
+		{Path: "speakers.data.mastodon", Types: []string{"string"}, Talks: 1},
+	}, report.Public)
+}
+
+func TestUnmappedFields_InvalidMapping(t *testing.T) {
+	source := &mockTalkSource{
+		getConferencesFunc: func(ctx context.Context) ([]domain.Conference, error) {
+			return []domain.Conference{{ID: "conf-1", Slug: "javazone2024"}}, nil
+		},
+	}
+
+	service := NewIndexerService(source, &mockSearchIndex{}, "private", "public", "not json", testUnmappedMapping)
+	_, err := service.UnmappedFields(context.Background())
+
+	assert.ErrorContains(t, err, "failed to parse mapping")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
@@ -27,6 +28,12 @@ func (m Mode) IsDevelopment() bool {
 	return m == ModeDevelopment
 }
 
+// Talk sources selectable with TALK_SOURCE
+const (
+	TalkSourceMoresleep = "moresleep"
+	TalkSourceFile      = "file"
+)
+
 // Config holds all application configuration loaded from environment variables
 type Config struct {
 	Mode                  Mode   `env:"MODE" envDefault:"production"`
@@ -40,6 +47,89 @@ type Config struct {
 	PrivateIndex          string `env:"PRIVATE_INDEX" envDefault:"javazone_private"`
 	PublicIndex           string `env:"PUBLIC_INDEX" envDefault:"javazone_public"`
 
+	// IndexRetention is the number of previous index generations kept after a full reindex
+	IndexRetention int `env:"INDEX_RETENTION" envDefault:"2"`
+
+	// StateIndex holds indexer bookkeeping such as incremental sync watermarks
+	StateIndex string `env:"STATE_INDEX" envDefault:"talks_indexer_state"`
+
+	// SpeakersPrivateIndex and SpeakersPublicIndex hold one profile document per speaker
+	SpeakersPrivateIndex string `env:"SPEAKERS_PRIVATE_INDEX" envDefault:"javazone_speakers_private"`
+	SpeakersPublicIndex  string `env:"SPEAKERS_PUBLIC_INDEX" envDefault:"javazone_speakers_public"`
+
+	// LockTTL is how long an indexing lock stays valid if its holder stops renewing it
+	LockTTL time.Duration `env:"LOCK_TTL" envDefault:"5m"`
+
+	// FetchConcurrency is the number of conferences a full reindex fetches from moresleep in parallel
+	FetchConcurrency int `env:"FETCH_CONCURRENCY" envDefault:"4"`
+
+	// FetchTimeout bounds how long fetching the talks of a single conference may take
+	FetchTimeout time.Duration `env:"FETCH_TIMEOUT" envDefault:"2m"`
+
+	// TalkSource selects where talks are read from: "moresleep" for the live API or
+	// "file" for a frozen dataset in the moresleep wire format under TalkSourceDir
+	TalkSource    string `env:"TALK_SOURCE" envDefault:"moresleep"`
+	TalkSourceDir string `env:"TALK_SOURCE_DIR"`
+
+	// ConferenceCacheTTL is how long the conference list is cached before it is refreshed in the background
+	ConferenceCacheTTL time.Duration `env:"CONFERENCE_CACHE_TTL" envDefault:"5m"`
+
+	// MoresleepTimeout bounds a single request attempt to moresleep
+	MoresleepTimeout time.Duration `env:"MORESLEEP_TIMEOUT" envDefault:"30s"`
+
+	// MoresleepMaxRetries and MoresleepRetryBackoff control how failed moresleep requests
+	// are retried; the backoff doubles on every retry with jitter, up to MoresleepMaxBackoff
+	MoresleepMaxRetries   int           `env:"MORESLEEP_MAX_RETRIES" envDefault:"3"`
+	MoresleepRetryBackoff time.Duration `env:"MORESLEEP_RETRY_BACKOFF" envDefault:"500ms"`
+	MoresleepMaxBackoff   time.Duration `env:"MORESLEEP_MAX_BACKOFF" envDefault:"10s"`
+
+	// MoresleepBreakerThreshold is the number of consecutive failed moresleep requests that
+	// opens the circuit breaker for MoresleepBreakerCooldown (0 disables the breaker)
+	MoresleepBreakerThreshold int           `env:"MORESLEEP_BREAKER_THRESHOLD" envDefault:"5"`
+	MoresleepBreakerCooldown  time.Duration `env:"MORESLEEP_BREAKER_COOLDOWN" envDefault:"30s"`
+
+	// BulkMaxDocs and BulkMaxBytes bound the number of documents and the payload size of one bulk request
+	BulkMaxDocs  int `env:"BULK_MAX_DOCS" envDefault:"500"`
+	BulkMaxBytes int `env:"BULK_MAX_BYTES" envDefault:"5242880"`
+
+	// BulkConcurrency is the number of bulk requests sent in parallel
+	BulkConcurrency int `env:"BULK_CONCURRENCY" envDefault:"1"`
+
+	// BulkMaxRetries is how many times documents rejected with 429 are retried, with
+	// exponential backoff starting at BulkRetryBackoff
+	BulkMaxRetries   int           `env:"BULK_MAX_RETRIES" envDefault:"3"`
+	BulkRetryBackoff time.Duration `env:"BULK_RETRY_BACKOFF" envDefault:"500ms"`
+
+	// IndexShrinkThreshold is the largest fraction of its documents an index may lose in one
+	// reindex before the reindex is refused unless forced, e.g. 0.3 for 30%. Zero disables the check.
+	IndexShrinkThreshold float64 `env:"INDEX_SHRINK_THRESHOLD" envDefault:"0.3"`
+
+	// ConferenceShrinkThreshold is the same limit applied to each conference within an index
+	ConferenceShrinkThreshold float64 `env:"CONFERENCE_SHRINK_THRESHOLD" envDefault:"0.3"`
+
+	// MappingDriftPolicy decides what happens when an index was created with an older
+	// mapping: "warn" keeps writing, "fail" refuses to, "rebuild" runs a full reindex
+	MappingDriftPolicy string `env:"MAPPING_DRIFT_POLICY" envDefault:"warn"`
+
+	// ReindexSchedule is a cron expression for recurring full reindexes, e.g. "0 3 * * *".
+	// Empty disables scheduled reindexing.
+	ReindexSchedule string `env:"REINDEX_SCHEDULE"`
+
+	// SyncSchedule is a cron expression for recurring incremental syncs, e.g. "*/5 * * * *".
+	// Empty disables scheduled syncing.
+	SyncSchedule string `env:"SYNC_SCHEDULE"`
+
+	// ScheduleJitter is the upper bound of the random delay added to each scheduled run
+	ScheduleJitter time.Duration `env:"SCHEDULE_JITTER" envDefault:"30s"`
+
+	// WebhookSecret is the shared secret moresleep signs webhook deliveries with.
+	// Empty disables the webhook endpoint.
+	WebhookSecret string `env:"WEBHOOK_SECRET"`
+
+	// WebhookDedupeWindow is how long webhook delivery IDs are remembered so retried
+	// deliveries do not queue the same work twice
+	WebhookDedupeWindow time.Duration `env:"WEBHOOK_DEDUPE_WINDOW" envDefault:"10m"`
+
 	// OIDC Configuration (only used in production mode)
 	OIDCIssuerURL    string `env:"OIDC_ISSUER_URL"`
 	OIDCClientID     string `env:"OIDC_CLIENT_ID"`
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,35 +21,118 @@ func TestLoad(t *testing.T) {
 			name:    "load with defaults",
 			envVars: map[string]string{},
 			expected: &Config{
-				Port:              8080,
-				MoresleepURL:      "http://localhost:8082",
-				MoresleepUser:     "",
-				MoresleepPassword: "",
-				ElasticsearchURL:  "http://localhost:9200",
-				PrivateIndex:      "javazone_private",
-				PublicIndex:       "javazone_public",
+				Port:                      8080,
+				MoresleepURL:              "http://localhost:8082",
+				MoresleepUser:             "",
+				MoresleepPassword:         "",
+				TalkSource:                "moresleep",
+				ElasticsearchURL:          "http://localhost:9200",
+				PrivateIndex:              "javazone_private",
+				PublicIndex:               "javazone_public",
+				IndexRetention:            2,
+				StateIndex:                "talks_indexer_state",
+				SpeakersPrivateIndex:      "javazone_speakers_private",
+				SpeakersPublicIndex:       "javazone_speakers_public",
+				LockTTL:                   5 * time.Minute,
+				FetchConcurrency:          4,
+				FetchTimeout:              2 * time.Minute,
+				ConferenceCacheTTL:        5 * time.Minute,
+				MoresleepTimeout:          30 * time.Second,
+				MoresleepMaxRetries:       3,
+				MoresleepRetryBackoff:     500 * time.Millisecond,
+				MoresleepMaxBackoff:       10 * time.Second,
+				MoresleepBreakerThreshold: 5,
+				MoresleepBreakerCooldown:  30 * time.Second,
+				WebhookDedupeWindow:       10 * time.Minute,
+				BulkMaxDocs:               500,
+				BulkMaxBytes:              5242880,
+				BulkConcurrency:           1,
+				BulkMaxRetries:            3,
+				BulkRetryBackoff:          500 * time.Millisecond,
+				IndexShrinkThreshold:      0.3,
+				ConferenceShrinkThreshold: 0.3,
+				MappingDriftPolicy:        "warn",
+				ScheduleJitter:            30 * time.Second,
 			},
 			wantErr: false,
 		},
 		{
 			name: "load with custom values",
 			envVars: map[string]string{
-				"PORT":               "9090",
-				"MORESLEEP_URL":      "https://api.example.com",
-				"MORESLEEP_USER":     "testuser",
-				"MORESLEEP_PASSWORD": "testpass",
-				"ELASTICSEARCH_URL":  "https://es.example.com:9200",
-				"PRIVATE_INDEX":      "custom_private",
-				"PUBLIC_INDEX":       "custom_public",
+				"PORT":                        "9090",
+				"MORESLEEP_URL":               "https://api.example.com",
+				"MORESLEEP_USER":              "testuser",
+				"MORESLEEP_PASSWORD":          "testpass",
+				"TALK_SOURCE":                 "file",
+				"TALK_SOURCE_DIR":             "/data/talks",
+				"ELASTICSEARCH_URL":           "https://es.example.com:9200",
+				"PRIVATE_INDEX":               "custom_private",
+				"PUBLIC_INDEX":                "custom_public",
+				"INDEX_RETENTION":             "5",
+				"STATE_INDEX":                 "custom_state",
+				"SPEAKERS_PRIVATE_INDEX":      "custom_speakers_private",
+				"SPEAKERS_PUBLIC_INDEX":       "custom_speakers_public",
+				"LOCK_TTL":                    "90s",
+				"FETCH_CONCURRENCY":           "8",
+				"FETCH_TIMEOUT":               "45s",
+				"CONFERENCE_CACHE_TTL":        "1m",
+				"MORESLEEP_TIMEOUT":           "10s",
+				"MORESLEEP_MAX_RETRIES":       "5",
+				"MORESLEEP_RETRY_BACKOFF":     "1s",
+				"MORESLEEP_MAX_BACKOFF":       "30s",
+				"MORESLEEP_BREAKER_THRESHOLD": "0",
+				"MORESLEEP_BREAKER_COOLDOWN":  "2m",
+				"WEBHOOK_SECRET":              "hook-secret",
+				"WEBHOOK_DEDUPE_WINDOW":       "1h",
+				"BULK_MAX_DOCS":               "200",
+				"BULK_MAX_BYTES":              "1048576",
+				"BULK_CONCURRENCY":            "3",
+				"BULK_MAX_RETRIES":            "5",
+				"BULK_RETRY_BACKOFF":          "2s",
+				"INDEX_SHRINK_THRESHOLD":      "0.2",
+				"CONFERENCE_SHRINK_THRESHOLD": "0",
+				"MAPPING_DRIFT_POLICY":        "rebuild",
+				"REINDEX_SCHEDULE":            "0 3 * * *",
+				"SYNC_SCHEDULE":               "*/5 * * * *",
+				"SCHEDULE_JITTER":             "1m",
 			},
 			expected: &Config{
-				Port:              9090,
-				MoresleepURL:      "https://api.example.com",
-				MoresleepUser:     "testuser",
-				MoresleepPassword: "testpass",
-				ElasticsearchURL:  "https://es.example.com:9200",
-				PrivateIndex:      "custom_private",
-				PublicIndex:       "custom_public",
+				Port:                      9090,
+				MoresleepURL:              "https://api.example.com",
+				MoresleepUser:             "testuser",
+				MoresleepPassword:         "testpass",
+				TalkSource:                "file",
+				TalkSourceDir:             "/data/talks",
+				ElasticsearchURL:          "https://es.example.com:9200",
+				PrivateIndex:              "custom_private",
+				PublicIndex:               "custom_public",
+				IndexRetention:            5,
+				StateIndex:                "custom_state",
+				SpeakersPrivateIndex:      "custom_speakers_private",
+				SpeakersPublicIndex:       "custom_speakers_public",
+				LockTTL:                   90 * time.Second,
+				FetchConcurrency:          8,
+				FetchTimeout:              45 * time.Second,
+				ConferenceCacheTTL:        time.Minute,
+				MoresleepTimeout:          10 * time.Second,
+				MoresleepMaxRetries:       5,
+				MoresleepRetryBackoff:     time.Second,
+				MoresleepMaxBackoff:       30 * time.Second,
+				MoresleepBreakerThreshold: 0,
+				MoresleepBreakerCooldown:  2 * time.Minute,
+				WebhookSecret:             "hook-secret",
+				WebhookDedupeWindow:       time.Hour,
+				BulkMaxDocs:               200,
+				BulkMaxBytes:              1048576,
+				BulkConcurrency:           3,
+				BulkMaxRetries:            5,
+				BulkRetryBackoff:          2 * time.Second,
+				IndexShrinkThreshold:      0.2,
+				ConferenceShrinkThreshold: 0,
+				MappingDriftPolicy:        "rebuild",
+				ReindexSchedule:           "0 3 * * *",
+				SyncSchedule:              "*/5 * * * *",
+				ScheduleJitter:            time.Minute,
 			},
 			wantErr: false,
 		},
@@ -59,13 +143,38 @@ func TestLoad(t *testing.T) {
 				"MORESLEEP_USER": "admin",
 			},
 			expected: &Config{
-				Port:              3000,
-				MoresleepURL:      "http://localhost:8082",
-				MoresleepUser:     "admin",
-				MoresleepPassword: "",
-				ElasticsearchURL:  "http://localhost:9200",
-				PrivateIndex:      "javazone_private",
-				PublicIndex:       "javazone_public",
+				Port:                      3000,
+				MoresleepURL:              "http://localhost:8082",
+				MoresleepUser:             "admin",
+				MoresleepPassword:         "",
+				TalkSource:                "moresleep",
+				ElasticsearchURL:          "http://localhost:9200",
+				PrivateIndex:              "javazone_private",
+				PublicIndex:               "javazone_public",
+				IndexRetention:            2,
+				StateIndex:                "talks_indexer_state",
+				SpeakersPrivateIndex:      "javazone_speakers_private",
+				SpeakersPublicIndex:       "javazone_speakers_public",
+				LockTTL:                   5 * time.Minute,
+				FetchConcurrency:          4,
+				FetchTimeout:              2 * time.Minute,
+				ConferenceCacheTTL:        5 * time.Minute,
+				MoresleepTimeout:          30 * time.Second,
+				MoresleepMaxRetries:       3,
+				MoresleepRetryBackoff:     500 * time.Millisecond,
+				MoresleepMaxBackoff:       10 * time.Second,
+				MoresleepBreakerThreshold: 5,
+				MoresleepBreakerCooldown:  30 * time.Second,
+				WebhookDedupeWindow:       10 * time.Minute,
+				BulkMaxDocs:               500,
+				BulkMaxBytes:              5242880,
+				BulkConcurrency:           1,
+				BulkMaxRetries:            3,
+				BulkRetryBackoff:          500 * time.Millisecond,
+				IndexShrinkThreshold:      0.3,
+				ConferenceShrinkThreshold: 0.3,
+				MappingDriftPolicy:        "warn",
+				ScheduleJitter:            30 * time.Second,
 			},
 			wantErr: false,
 		},
@@ -102,9 +211,38 @@ func TestLoad(t *testing.T) {
 				assert.Equal(t, tt.expected.MoresleepURL, cfg.MoresleepURL)
 				assert.Equal(t, tt.expected.MoresleepUser, cfg.MoresleepUser)
 				assert.Equal(t, tt.expected.MoresleepPassword, cfg.MoresleepPassword)
+				assert.Equal(t, tt.expected.TalkSource, cfg.TalkSource)
+				assert.Equal(t, tt.expected.TalkSourceDir, cfg.TalkSourceDir)
 				assert.Equal(t, tt.expected.ElasticsearchURL, cfg.ElasticsearchURL)
 				assert.Equal(t, tt.expected.PrivateIndex, cfg.PrivateIndex)
 				assert.Equal(t, tt.expected.PublicIndex, cfg.PublicIndex)
+				assert.Equal(t, tt.expected.IndexRetention, cfg.IndexRetention)
+				assert.Equal(t, tt.expected.StateIndex, cfg.StateIndex)
+				assert.Equal(t, tt.expected.SpeakersPrivateIndex, cfg.SpeakersPrivateIndex)
+				assert.Equal(t, tt.expected.SpeakersPublicIndex, cfg.SpeakersPublicIndex)
+				assert.Equal(t, tt.expected.LockTTL, cfg.LockTTL)
+				assert.Equal(t, tt.expected.FetchConcurrency, cfg.FetchConcurrency)
+				assert.Equal(t, tt.expected.FetchTimeout, cfg.FetchTimeout)
+				assert.Equal(t, tt.expected.ConferenceCacheTTL, cfg.ConferenceCacheTTL)
+				assert.Equal(t, tt.expected.MoresleepTimeout, cfg.MoresleepTimeout)
+				assert.Equal(t, tt.expected.MoresleepMaxRetries, cfg.MoresleepMaxRetries)
+				assert.Equal(t, tt.expected.MoresleepRetryBackoff, cfg.MoresleepRetryBackoff)
+				assert.Equal(t, tt.expected.MoresleepMaxBackoff, cfg.MoresleepMaxBackoff)
+				assert.Equal(t, tt.expected.MoresleepBreakerThreshold, cfg.MoresleepBreakerThreshold)
+				assert.Equal(t, tt.expected.MoresleepBreakerCooldown, cfg.MoresleepBreakerCooldown)
+				assert.Equal(t, tt.expected.WebhookSecret, cfg.WebhookSecret)
+				assert.Equal(t, tt.expected.WebhookDedupeWindow, cfg.WebhookDedupeWindow)
+				assert.Equal(t, tt.expected.BulkMaxDocs, cfg.BulkMaxDocs)
+				assert.Equal(t, tt.expected.BulkMaxBytes, cfg.BulkMaxBytes)
+				assert.Equal(t, tt.expected.BulkConcurrency, cfg.BulkConcurrency)
+				assert.Equal(t, tt.expected.BulkMaxRetries, cfg.BulkMaxRetries)
+				assert.Equal(t, tt.expected.BulkRetryBackoff, cfg.BulkRetryBackoff)
+				assert.Equal(t, tt.expected.IndexShrinkThreshold, cfg.IndexShrinkThreshold)
+				assert.Equal(t, tt.expected.ConferenceShrinkThreshold, cfg.ConferenceShrinkThreshold)
+				assert.Equal(t, tt.expected.MappingDriftPolicy, cfg.MappingDriftPolicy)
+				assert.Equal(t, tt.expected.ReindexSchedule, cfg.ReindexSchedule)
+				assert.Equal(t, tt.expected.SyncSchedule, cfg.SyncSchedule)
+				assert.Equal(t, tt.expected.ScheduleJitter, cfg.ScheduleJitter)
 			}
 		})
 	}
@@ -180,7 +318,36 @@ func clearConfigEnv() {
 	os.Unsetenv("MORESLEEP_URL")
 	os.Unsetenv("MORESLEEP_USER")
 	os.Unsetenv("MORESLEEP_PASSWORD")
+	os.Unsetenv("TALK_SOURCE")
+	os.Unsetenv("TALK_SOURCE_DIR")
 	os.Unsetenv("ELASTICSEARCH_URL")
 	os.Unsetenv("PRIVATE_INDEX")
 	os.Unsetenv("PUBLIC_INDEX")
+	os.Unsetenv("INDEX_RETENTION")
+	os.Unsetenv("STATE_INDEX")
+	os.Unsetenv("SPEAKERS_PRIVATE_INDEX")
+	os.Unsetenv("SPEAKERS_PUBLIC_INDEX")
+	os.Unsetenv("LOCK_TTL")
+	os.Unsetenv("FETCH_CONCURRENCY")
+	os.Unsetenv("FETCH_TIMEOUT")
+	os.Unsetenv("CONFERENCE_CACHE_TTL")
+	os.Unsetenv("MORESLEEP_TIMEOUT")
+	os.Unsetenv("MORESLEEP_MAX_RETRIES")
+	os.Unsetenv("MORESLEEP_RETRY_BACKOFF")
+	os.Unsetenv("MORESLEEP_MAX_BACKOFF")
+	os.Unsetenv("MORESLEEP_BREAKER_THRESHOLD")
+	os.Unsetenv("MORESLEEP_BREAKER_COOLDOWN")
+	os.Unsetenv("WEBHOOK_SECRET")
+	os.Unsetenv("WEBHOOK_DEDUPE_WINDOW")
+	os.Unsetenv("BULK_MAX_DOCS")
+	os.Unsetenv("BULK_MAX_BYTES")
+	os.Unsetenv("BULK_CONCURRENCY")
+	os.Unsetenv("BULK_MAX_RETRIES")
+	os.Unsetenv("BULK_RETRY_BACKOFF")
+	os.Unsetenv("INDEX_SHRINK_THRESHOLD")
+	os.Unsetenv("CONFERENCE_SHRINK_THRESHOLD")
+	os.Unsetenv("REINDEX_SCHEDULE")
+	os.Unsetenv("SYNC_SCHEDULE")
+	os.Unsetenv("SCHEDULE_JITTER")
+	os.Unsetenv("MAPPING_DRIFT_POLICY")
 }
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// BreakerState is the state of a circuit breaker guarding an external dependency
+type BreakerState string
+
+const (
+	// BreakerClosed lets requests through; failures are being counted
+	BreakerClosed BreakerState = "closed"
+
+	// BreakerOpen fails requests immediately until the cooldown has passed
+	BreakerOpen BreakerState = "open"
+
+	// BreakerHalfOpen lets a single probe request through to test whether the dependency recovered
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerStatus describes the circuit breaker of an external dependency
+type BreakerStatus struct {
+	Name                string       `json:"name"`
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	OpenedAt            *time.Time   `json:"openedAt,omitempty"`
+	RetryAt             *time.Time   `json:"retryAt,omitempty"`
+	LastError           string       `json:"lastError,omitempty"`
+}
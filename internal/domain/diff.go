@@ -0,0 +1,39 @@
+package domain
+
+// ReindexOptions controls how a reindex operation is carried out
+type ReindexOptions struct {
+	// DryRun computes what the operation would change without writing anything
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Force publishes the new data even if an index would shrink beyond the safety threshold
+	Force bool `json:"force,omitempty"`
+}
+
+// FieldChange is a single field that differs between the indexed and the new document.
+// Nested fields use dotted paths, e.g. "data.title". A nil Before means the field is
+// new; a nil After means it would be removed.
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// DocumentChange lists the field changes of a document that would be updated
+type DocumentChange struct {
+	ID     string        `json:"id"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// IndexDiff describes how a reindex would change the documents of one index
+type IndexDiff struct {
+	Index     string           `json:"index"`
+	Added     []string         `json:"added"`
+	Updated   []DocumentChange `json:"updated"`
+	Deleted   []string         `json:"deleted"`
+	Unchanged int              `json:"unchanged"`
+}
+
+// HasChanges returns true if applying the diff would modify the index
+func (d IndexDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Updated) > 0 || len(d.Deleted) > 0
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "errors"
+
+// ErrNotFound is returned by a TalkSource when the requested talk or conference does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrUnauthorized is returned by a TalkSource when it rejects the configured credentials.
+// Every further request would fail the same way, so operations abort instead of skipping.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ErrSourceUnavailable is returned by a TalkSource while its circuit breaker is open because
+// the source kept failing. Like ErrUnauthorized, operations abort instead of skipping.
+var ErrSourceUnavailable = errors.New("source unavailable")
+
+// ErrInvalidJobRequest is returned when a job request has an unknown type or is missing its target
+var ErrInvalidJobRequest = errors.New("invalid job request")
+
+// ErrJobNotFound is returned when no job exists with the given ID
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrJobFinished is returned when trying to cancel a job that has already finished
+var ErrJobFinished = errors.New("job already finished")
+
+// ErrJobQueueFull is returned when too many jobs are waiting to run
+var ErrJobQueueFull = errors.New("job queue is full")
+
+// ErrReindexInProgress is returned when another indexing operation holds the lock for the same indexes
+var ErrReindexInProgress = errors.New("indexing already in progress")
+
+// ErrLockLost is returned when an operation is aborted because its indexing lock expired or was taken over
+var ErrLockLost = errors.New("indexing lock lost")
+
+// ErrIndexShrink is returned when a reindex would drop more documents than the safety threshold allows
+var ErrIndexShrink = errors.New("index would shrink beyond the safety threshold")
+
+// ErrMappingDrift is returned when an index's mapping is out of date and the drift policy is fail
+var ErrMappingDrift = errors.New("index mapping is out of date")
+
+// ErrInvalidSearch is returned when a search query has invalid parameters
+var ErrInvalidSearch = errors.New("invalid search")
@@ -0,0 +1,31 @@
+package domain
+
+import "fmt"
+
+// ShrinkViolation describes an index, or a single conference within it, whose
+// document count would drop by more than the configured safety threshold
+type ShrinkViolation struct {
+	Index        string  `json:"index"`
+	ConferenceID string  `json:"conferenceId,omitempty"`
+	Current      int     `json:"current"`
+	New          int     `json:"new"`
+	Threshold    float64 `json:"threshold"`
+}
+
+// Drop returns the fraction of the current documents that would be removed
+func (v ShrinkViolation) Drop() float64 {
+	if v.Current == 0 {
+		return 0
+	}
+	return float64(v.Current-v.New) / float64(v.Current)
+}
+
+// String describes the violation for error messages and logs
+func (v ShrinkViolation) String() string {
+	target := v.Index
+	if v.ConferenceID != "" {
+		target = fmt.Sprintf("conference %s in %s", v.ConferenceID, v.Index)
+	}
+	return fmt.Sprintf("%s would shrink from %d to %d documents (%.0f%%, threshold %.0f%%)",
+		target, v.Current, v.New, v.Drop()*100, v.Threshold*100)
+}
@@ -0,0 +1,96 @@
+package domain
+
+import "time"
+
+// JobType identifies the kind of work a background job performs
+type JobType string
+
+const (
+	JobReindexAll        JobType = "reindex-all"
+	JobReindexConference JobType = "reindex-conference"
+	JobReindexTalk       JobType = "reindex-talk"
+	JobSync              JobType = "sync"
+)
+
+// RequiresTarget returns true if the job type needs a conference slug or talk ID
+func (t JobType) RequiresTarget() bool {
+	return t == JobReindexConference || t == JobReindexTalk
+}
+
+// IsValid returns true if the job type is one of the known types
+func (t JobType) IsValid() bool {
+	switch t {
+	case JobReindexAll, JobReindexConference, JobReindexTalk, JobSync:
+		return true
+	}
+	return false
+}
+
+// SupportsDryRun returns true if the job type can run without writing to the indexes
+func (t JobType) SupportsDryRun() bool {
+	return t == JobReindexAll || t == JobReindexConference || t == JobReindexTalk
+}
+
+// SupportsForce returns true if the job type is guarded by the shrink thresholds and can override them
+func (t JobType) SupportsForce() bool {
+	return t == JobReindexAll || t == JobReindexConference || t == JobSync
+}
+
+// JobState represents the lifecycle state of a background job
+type JobState string
+
+const (
+	JobQueued    JobState = "queued"
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+	JobCancelled JobState = "cancelled"
+)
+
+// IsFinished returns true if the job has reached a terminal state
+func (s JobState) IsFinished() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCancelled
+}
+
+// ProgressState represents how far processing of a single conference has come
+type ProgressState string
+
+const (
+	ProgressPending ProgressState = "pending"
+	ProgressRunning ProgressState = "running"
+	ProgressDone    ProgressState = "done"
+	ProgressFailed  ProgressState = "failed"
+)
+
+// ConferenceProgress tracks the processing of one conference within a job
+type ConferenceProgress struct {
+	ConferenceID   string        `json:"conferenceId"`
+	ConferenceName string        `json:"conferenceName,omitempty"`
+	State          ProgressState `json:"state"`
+	Talks          int           `json:"talks"`
+	Error          string        `json:"error,omitempty"`
+}
+
+// JobRequest describes the work to be queued as a background job
+type JobRequest struct {
+	Type   JobType `json:"type"`
+	Target string  `json:"target,omitempty"`
+	DryRun bool    `json:"dryRun,omitempty"`
+	Force  bool    `json:"force,omitempty"`
+}
+
+// Job is a background indexing operation and its current status
+type Job struct {
+	ID         string               `json:"id"`
+	Type       JobType              `json:"type"`
+	Target     string               `json:"target,omitempty"`
+	DryRun     bool                 `json:"dryRun,omitempty"`
+	Force      bool                 `json:"force,omitempty"`
+	State      JobState             `json:"state"`
+	Progress   []ConferenceProgress `json:"progress"`
+	Errors     []string             `json:"errors"`
+	Report     *ReindexReport       `json:"report,omitempty"`
+	CreatedAt  time.Time            `json:"createdAt"`
+	StartedAt  *time.Time           `json:"startedAt,omitempty"`
+	FinishedAt *time.Time           `json:"finishedAt,omitempty"`
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "fmt"
+
+// MappingDriftPolicy decides what happens when an index was created with a mapping
+// that differs from the one compiled into the indexer
+type MappingDriftPolicy string
+
+const (
+	// MappingDriftWarn logs the drift and keeps writing into the existing index
+	MappingDriftWarn MappingDriftPolicy = "warn"
+	// MappingDriftFail refuses to write into the existing index
+	MappingDriftFail MappingDriftPolicy = "fail"
+	// MappingDriftRebuild replaces the drifted indexes with a full reindex
+	MappingDriftRebuild MappingDriftPolicy = "rebuild"
+)
+
+// ParseMappingDriftPolicy parses a policy name, defaulting to MappingDriftWarn when empty
+func ParseMappingDriftPolicy(value string) (MappingDriftPolicy, error) {
+	switch policy := MappingDriftPolicy(value); policy {
+	case "":
+		return MappingDriftWarn, nil
+	case MappingDriftWarn, MappingDriftFail, MappingDriftRebuild:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown mapping drift policy %q (want warn, fail or rebuild)", value)
+	}
+}
+
+// MappingDrift describes an index whose stored mapping hash differs from the compiled mapping.
+// Actual is empty for indexes created before mapping hashes were stored.
+type MappingDrift struct {
+	Alias    string `json:"alias"`
+	Index    string `json:"index"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// UnmappedField is a field seen in the source data that has no explicit mapping in an
+// index, so its type is decided by the index's dynamic templates
+type UnmappedField struct {
+	Path  string   `json:"path"`
+	Types []string `json:"types"`
+	Talks int      `json:"talks"`
+}
+
+// UnmappedFieldReport lists the fields of the private and public talk documents that
+// have no explicit mapping, sorted by path. Fields inside an unmapped object are not
+// listed separately.
+type UnmappedFieldReport struct {
+	Talks   int                 `json:"talks"`
+	Private []UnmappedField     `json:"private"`
+	Public  []UnmappedField     `json:"public"`
+	Skipped []SkippedConference `json:"skipped"`
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BulkFailure describes a single document that Elasticsearch rejected in a bulk request
+type BulkFailure struct {
+	Index      string `json:"index"`
+	DocumentID string `json:"documentId"`
+	Status     int    `json:"status"`
+	Type       string `json:"type"`
+	Reason     string `json:"reason"`
+}
+
+// BulkResult summarizes a bulk indexing request.
+// Stale counts documents skipped because a newer version of them is already indexed.
+type BulkResult struct {
+	Indexed  int           `json:"indexed"`
+	Stale    int           `json:"stale,omitempty"`
+	Failures []BulkFailure `json:"failures,omitempty"`
+}
+
+// ConferenceReport holds the outcome of indexing a single conference
+type ConferenceReport struct {
+	ConferenceID   string `json:"conferenceId"`
+	ConferenceName string `json:"conferenceName,omitempty"`
+	Slug           string `json:"slug,omitempty"`
+	PrivateCount   int    `json:"privateCount"`
+	PublicCount    int    `json:"publicCount"`
+	PrivateDeleted int    `json:"privateDeleted"`
+	PublicDeleted  int    `json:"publicDeleted"`
+}
+
+// SkippedConference is a conference that could not be indexed, together with the reason
+type SkippedConference struct {
+	ConferenceID   string `json:"conferenceId"`
+	ConferenceName string `json:"conferenceName,omitempty"`
+	Error          string `json:"error"`
+}
+
+// ReindexReport describes the outcome of an indexing operation.
+// An operation that completed but skipped conferences, had rejected documents
+// or recorded warnings is reported as partial rather than failed.
+// Warnings describe steps that failed after the talk indexes were already published.
+// Stale counts documents not written because a newer version was already indexed;
+// they do not make the report partial.
+// ShrinkViolations lists indexes and conferences whose document count would drop
+// beyond the safety threshold; they abort the operation unless it is forced.
+type ReindexReport struct {
+	Operation        string              `json:"operation"`
+	Target           string              `json:"target,omitempty"`
+	Conferences      []ConferenceReport  `json:"conferences"`
+	Skipped          []SkippedConference `json:"skipped"`
+	BulkFailures     []BulkFailure       `json:"bulkFailures"`
+	Warnings         []string            `json:"warnings,omitempty"`
+	Stale            int                 `json:"stale,omitempty"`
+	PrivateCount     int                 `json:"privateCount"`
+	PublicCount      int                 `json:"publicCount"`
+	PrivateDeleted   int                 `json:"privateDeleted"`
+	PublicDeleted    int                 `json:"publicDeleted"`
+	Sync             *SyncResult         `json:"sync,omitempty"`
+	DryRun           bool                `json:"dryRun,omitempty"`
+	Diffs            []IndexDiff         `json:"diffs,omitempty"`
+	ShrinkViolations []ShrinkViolation   `json:"shrinkViolations,omitempty"`
+	MappingDrift     []MappingDrift      `json:"mappingDrift,omitempty"`
+	MappingRebuild   bool                `json:"mappingRebuild,omitempty"`
+	StartedAt        time.Time           `json:"startedAt"`
+	DurationMs       int64               `json:"durationMs"`
+}
+
+// NewReindexReport creates an empty report for an operation started at the given time
+func NewReindexReport(operation string, target string, startedAt time.Time) *ReindexReport {
+	return &ReindexReport{
+		Operation:    operation,
+		Target:       target,
+		Conferences:  []ConferenceReport{},
+		Skipped:      []SkippedConference{},
+		BulkFailures: []BulkFailure{},
+		StartedAt:    startedAt,
+	}
+}
+
+// AddConference records a processed conference and adds its counts to the totals
+func (r *ReindexReport) AddConference(conf ConferenceReport) {
+	r.Conferences = append(r.Conferences, conf)
+	r.PrivateCount += conf.PrivateCount
+	r.PublicCount += conf.PublicCount
+	r.PrivateDeleted += conf.PrivateDeleted
+	r.PublicDeleted += conf.PublicDeleted
+}
+
+// Skip records a conference that could not be indexed
+func (r *ReindexReport) Skip(conf Conference, err error) {
+	r.Skipped = append(r.Skipped, SkippedConference{
+		ConferenceID:   conf.ID,
+		ConferenceName: conf.Name,
+		Error:          err.Error(),
+	})
+}
+
+// AddBulkResult records the stale and rejected documents of a bulk request
+func (r *ReindexReport) AddBulkResult(result *BulkResult) {
+	if result != nil {
+		r.Stale += result.Stale
+		r.BulkFailures = append(r.BulkFailures, result.Failures...)
+	}
+}
+
+// Warn records a step that failed without failing the operation
+func (r *ReindexReport) Warn(message string) {
+	r.Warnings = append(r.Warnings, message)
+}
+
+// Finish records the duration of the operation
+func (r *ReindexReport) Finish(finishedAt time.Time) {
+	r.DurationMs = finishedAt.Sub(r.StartedAt).Milliseconds()
+}
+
+// IsPartial returns true if the operation completed but skipped conferences or
+// documents, or recorded warnings
+func (r *ReindexReport) IsPartial() bool {
+	return len(r.Skipped) > 0 || len(r.BulkFailures) > 0 || len(r.Warnings) > 0
+}
+
+// MarshalJSON encodes the report with a "partial" field holding IsPartial, so clients
+// can tell a partial run from a clean one without inspecting the lists themselves
+func (r ReindexReport) MarshalJSON() ([]byte, error) {
+	type report ReindexReport
+	return json.Marshal(struct {
+		report
+		Partial bool `json:"partial"`
+	}{report(r), r.IsPartial()})
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// ScheduleStatus describes a recurring job schedule and when it last and next runs
+type ScheduleStatus struct {
+	Name       string     `json:"name"`
+	Expression string     `json:"expression"`
+	JobType    JobType    `json:"jobType"`
+	NextRun    *time.Time `json:"nextRun,omitempty"`
+	LastRun    *time.Time `json:"lastRun,omitempty"`
+	LastJobID  string     `json:"lastJobId,omitempty"`
+	LastError  string     `json:"lastError,omitempty"`
+
+	// SkippedRuns counts runs that were skipped because the previous job was still going
+	SkippedRuns int `json:"skippedRuns"`
+}
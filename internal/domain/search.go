@@ -0,0 +1,224 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultSearchSize is the page size used when a search does not specify one
+	DefaultSearchSize = 20
+
+	// MaxSearchSize is the largest page size a search may request
+	MaxSearchSize = 100
+
+	// MaxSearchWindow is the deepest result a search can page to, matching
+	// Elasticsearch's default index.max_result_window
+	MaxSearchWindow = 10000
+)
+
+// SearchFilters restricts a search to talks matching every non-empty filter.
+// Within a filter a talk matches if it has any of the given values.
+type SearchFilters struct {
+	ConferenceSlugs []string `json:"conferenceSlugs,omitempty"`
+	Statuses        []string `json:"statuses,omitempty"`
+	Formats         []string `json:"formats,omitempty"`
+	Languages       []string `json:"languages,omitempty"`
+	Lengths         []string `json:"lengths,omitempty"`
+	Levels          []string `json:"levels,omitempty"`
+	Keywords        []string `json:"keywords,omitempty"`
+}
+
+// SearchQuery is a full-text search over talk titles, abstracts and speaker names.
+// An empty Text matches every talk, so the filters alone can be used to browse.
+// Page is one-based.
+type SearchQuery struct {
+	Text    string        `json:"text,omitempty"`
+	Filters SearchFilters `json:"filters"`
+	Page    int           `json:"page"`
+	Size    int           `json:"size"`
+}
+
+// From returns the offset of the first result of the query's page
+func (q SearchQuery) From() int {
+	return (q.Page - 1) * q.Size
+}
+
+// Normalize fills in the default page and size and checks the query's bounds
+func (q *SearchQuery) Normalize() error {
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.Size == 0 {
+		q.Size = DefaultSearchSize
+	}
+
+	switch {
+	case q.Page < 1:
+		return fmt.Errorf("%w: page must be at least 1", ErrInvalidSearch)
+	case q.Size < 1 || q.Size > MaxSearchSize:
+		return fmt.Errorf("%w: size must be between 1 and %d", ErrInvalidSearch, MaxSearchSize)
+	case q.From()+q.Size > MaxSearchWindow:
+		return fmt.Errorf("%w: cannot page beyond the first %d results", ErrInvalidSearch, MaxSearchWindow)
+	}
+	return nil
+}
+
+// SearchHit is a single talk matching a search
+type SearchHit struct {
+	ID    string  `json:"id"`
+	Score float64 `json:"score"`
+	Talk  Talk    `json:"talk"`
+}
+
+// SearchResult is one page of talks matching a search
+type SearchResult struct {
+	Total int         `json:"total"`
+	Page  int         `json:"page"`
+	Size  int         `json:"size"`
+	Hits  []SearchHit `json:"hits"`
+}
+
+// Facet names, which are also the query parameters filtering on the facet's field
+const (
+	FacetConference = "conference"
+	FacetStatus     = "status"
+	FacetFormat     = "format"
+	FacetLanguage   = "language"
+	FacetLength     = "length"
+	FacetLevel      = "level"
+	FacetKeyword    = "keyword"
+)
+
+// FacetBucket is one value of a facet and the number of matching talks having it
+type FacetBucket struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facet counts the matching talks per value of one field, most common value first.
+// The counts apply every filter except the facet's own, so they show how many
+// talks selecting another value of the facet would add.
+type Facet struct {
+	Name    string        `json:"name"`
+	Buckets []FacetBucket `json:"buckets"`
+}
+
+// FacetResult holds the facets of the talks matching a search.
+// Total is the number of talks matching the search with every filter applied.
+type FacetResult struct {
+	Total  int     `json:"total"`
+	Facets []Facet `json:"facets"`
+}
+
+// ParseSearchQuery builds a search query from URL query parameters:
+// q for the text, the repeatable filters conference, status, format, language,
+// length, level and keyword, and page and size for pagination.
+// Filter values may also be given comma separated.
+func ParseSearchQuery(params url.Values) (SearchQuery, error) {
+	query := SearchQuery{
+		Text: strings.TrimSpace(params.Get("q")),
+		Filters: SearchFilters{
+			ConferenceSlugs: searchParamValues(params, FacetConference),
+			Statuses:        searchParamValues(params, FacetStatus),
+			Formats:         searchParamValues(params, FacetFormat),
+			Languages:       searchParamValues(params, FacetLanguage),
+			Lengths:         searchParamValues(params, FacetLength),
+			Levels:          searchParamValues(params, FacetLevel),
+			Keywords:        searchParamValues(params, FacetKeyword),
+		},
+	}
+
+	for name, target := range map[string]*int{"page": &query.Page, "size": &query.Size} {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return query, fmt.Errorf("%w: invalid %s %q", ErrInvalidSearch, name, value)
+		}
+		*target = n
+	}
+
+	return query, nil
+}
+
+// searchParamValues returns the non-empty values of a repeatable, comma separated parameter
+func searchParamValues(params url.Values, name string) []string {
+	var values []string
+	for _, param := range params[name] {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+const (
+	// DefaultSuggestSize is the number of suggestions per group used when a request does not specify one
+	DefaultSuggestSize = 5
+
+	// MaxSuggestSize is the largest number of suggestions per group a request may ask for
+	MaxSuggestSize = 20
+)
+
+// SuggestQuery asks for type-ahead suggestions completing Text, optionally limited
+// to talks in the given conferences
+type SuggestQuery struct {
+	Text            string   `json:"text"`
+	ConferenceSlugs []string `json:"conferenceSlugs,omitempty"`
+	Size            int      `json:"size"`
+}
+
+// Normalize fills in the default size and checks the query
+func (q *SuggestQuery) Normalize() error {
+	if q.Size == 0 {
+		q.Size = DefaultSuggestSize
+	}
+
+	switch {
+	case q.Text == "":
+		return fmt.Errorf("%w: q is required", ErrInvalidSearch)
+	case q.Size < 1 || q.Size > MaxSuggestSize:
+		return fmt.Errorf("%w: size must be between 1 and %d", ErrInvalidSearch, MaxSuggestSize)
+	}
+	return nil
+}
+
+// Suggestion is a single completion of a suggest query.
+// Title suggestions carry the ID of their talk.
+type Suggestion struct {
+	Text   string `json:"text"`
+	TalkID string `json:"talkId,omitempty"`
+}
+
+// Suggestions are the completions of a suggest query grouped by what they complete
+type Suggestions struct {
+	Titles   []Suggestion `json:"titles"`
+	Speakers []Suggestion `json:"speakers"`
+	Keywords []Suggestion `json:"keywords"`
+}
+
+// ParseSuggestQuery builds a suggest query from URL query parameters:
+// q for the text to complete, the repeatable conference filter and size
+func ParseSuggestQuery(params url.Values) (SuggestQuery, error) {
+	query := SuggestQuery{
+		Text:            strings.TrimSpace(params.Get("q")),
+		ConferenceSlugs: searchParamValues(params, FacetConference),
+	}
+
+	if value := params.Get("size"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return query, fmt.Errorf("%w: invalid size %q", ErrInvalidSearch, value)
+		}
+		query.Size = n
+	}
+
+	return query, nil
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SnapshotFormat identifies the NDJSON index snapshots written by the indexer
+const SnapshotFormat = "talks-indexer-snapshot"
+
+// SnapshotVersion is the version of the snapshot format written by the indexer
+const SnapshotVersion = 1
+
+// SnapshotHeader is the first line of a snapshot. It is followed by one line per
+// document holding its ID and source.
+type SnapshotHeader struct {
+	Format      string    `json:"format"`
+	Version     int       `json:"version"`
+	Index       string    `json:"index"`
+	SourceIndex string    `json:"sourceIndex"`
+	ExportedAt  time.Time `json:"exportedAt"`
+	Documents   int       `json:"documents"`
+
+	// Definition holds the settings and mappings the exported index was created with
+	Definition json.RawMessage `json:"definition"`
+}
+
+// SnapshotDocument is a document line of a snapshot
+type SnapshotDocument struct {
+	ID     string          `json:"_id"`
+	Source json.RawMessage `json:"_source"`
+}
+
+// SnapshotResult describes a snapshot restored into a fresh index
+type SnapshotResult struct {
+	Alias       string        `json:"alias,omitempty"`
+	Index       string        `json:"index"`
+	SourceIndex string        `json:"sourceIndex"`
+	ExportedAt  time.Time     `json:"exportedAt"`
+	Documents   int           `json:"documents"`
+	Indexed     int           `json:"indexed"`
+	Failures    []BulkFailure `json:"failures,omitempty"`
+}
@@ -0,0 +1,134 @@
+package domain
+
+import "sort"
+
+// SpeakerTalk is a talk listed on a speaker's profile
+type SpeakerTalk struct {
+	ID             string `json:"id"`
+	Title          string `json:"title,omitempty"`
+	Status         string `json:"status"`
+	ConferenceID   string `json:"conferenceId"`
+	ConferenceSlug string `json:"conferenceSlug"`
+}
+
+// SpeakerConference is a conference a speaker has talks in
+type SpeakerConference struct {
+	ID   string `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name,omitempty"`
+}
+
+// SpeakerProfile is a speaker aggregated across all their talks and conferences,
+// indexed as one document per speaker ID
+type SpeakerProfile struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+	Talks       []SpeakerTalk          `json:"talks"`
+	Conferences []SpeakerConference    `json:"conferences"`
+}
+
+// BuildSpeakerProfiles aggregates the speakers of the given talks into one profile per
+// speaker ID, sorted by ID. The talks must already be projected for their index with
+// Talk.ToPrivate or Talk.ToPublic, so each profile carries the matching projection of
+// its speaker. The name and data are taken from the most recently updated talk.
+// Speakers without an ID are left out.
+func BuildSpeakerProfiles(talks []Talk) []SpeakerProfile {
+	profiles := make(map[string]*SpeakerProfile)
+	latest := make(map[string]*Talk)
+
+	for i := range talks {
+		talk := &talks[i]
+		for _, speaker := range talk.Speakers {
+			if speaker.ID == "" {
+				continue
+			}
+
+			profile, ok := profiles[speaker.ID]
+			if !ok {
+				profile = &SpeakerProfile{ID: speaker.ID}
+				profiles[speaker.ID] = profile
+			}
+			if previous := latest[speaker.ID]; previous == nil || updatedAfter(talk, previous) {
+				latest[speaker.ID] = talk
+				profile.Name = speaker.Name
+				profile.Data = speaker.Data
+			}
+
+			profile.Talks = append(profile.Talks, newSpeakerTalk(*talk))
+			profile.addConference(*talk)
+		}
+	}
+
+	result := make([]SpeakerProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		sort.Slice(profile.Talks, func(i, j int) bool {
+			a, b := profile.Talks[i], profile.Talks[j]
+			if a.ConferenceSlug != b.ConferenceSlug {
+				return a.ConferenceSlug < b.ConferenceSlug
+			}
+			return a.ID < b.ID
+		})
+		sort.Slice(profile.Conferences, func(i, j int) bool {
+			return profile.Conferences[i].Slug < profile.Conferences[j].Slug
+		})
+		result = append(result, *profile)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	return result
+}
+
+// SpeakerIDs returns the distinct IDs of the speakers of the given talks
+func SpeakerIDs(talks []Talk) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, talk := range talks {
+		for _, speaker := range talk.Speakers {
+			if speaker.ID != "" && !seen[speaker.ID] {
+				seen[speaker.ID] = true
+				ids = append(ids, speaker.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// addConference lists the talk's conference on the profile unless it is already there
+func (p *SpeakerProfile) addConference(talk Talk) {
+	for _, conf := range p.Conferences {
+		if conf.ID == talk.ConferenceID {
+			return
+		}
+	}
+	p.Conferences = append(p.Conferences, SpeakerConference{
+		ID:   talk.ConferenceID,
+		Slug: talk.ConferenceSlug,
+		Name: talk.ConferenceName,
+	})
+}
+
+// newSpeakerTalk returns the summary of a talk listed on a speaker's profile
+func newSpeakerTalk(talk Talk) SpeakerTalk {
+	title, _ := talk.Data["title"].(string)
+	return SpeakerTalk{
+		ID:             talk.ID,
+		Title:          title,
+		Status:         talk.Status,
+		ConferenceID:   talk.ConferenceID,
+		ConferenceSlug: talk.ConferenceSlug,
+	}
+}
+
+// updatedAfter reports whether talk a was updated after talk b; a talk without a
+// timestamp counts as older than any talk with one
+func updatedAfter(a, b *Talk) bool {
+	switch {
+	case a.LastUpdated == nil:
+		return false
+	case b.LastUpdated == nil:
+		return true
+	default:
+		return a.LastUpdated.After(*b.LastUpdated)
+	}
+}
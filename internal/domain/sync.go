@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// SyncWatermark records how far a conference has been synchronized.
+// It is stored per conference so that unchanged conferences can be skipped
+// without looking at the index.
+type SyncWatermark struct {
+	ConferenceID string    `json:"conferenceId"`
+	LastUpdated  time.Time `json:"lastUpdated"`
+	TalkCount    int       `json:"talkCount"`
+	SyncedAt     time.Time `json:"syncedAt"`
+}
+
+// SyncResult summarizes the outcome of an incremental sync
+type SyncResult struct {
+	Conferences        int `json:"conferences"`
+	SkippedConferences int `json:"skippedConferences"`
+	Created            int `json:"created"`
+	Updated            int `json:"updated"`
+	Unchanged          int `json:"unchanged"`
+	Deleted            int `json:"deleted"`
+}
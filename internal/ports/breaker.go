@@ -0,0 +1,10 @@
+package ports
+
+import "github.com/javaBin/talks-indexer/internal/domain"
+
+// CircuitBreaker defines the interface for inspecting the circuit breaker of an external dependency.
+// This is implemented by adapters that guard their requests with a breaker, such as the moresleep client.
+type CircuitBreaker interface {
+	// BreakerStatus returns the current state of the breaker
+	BreakerStatus() domain.BreakerStatus
+}
@@ -13,3 +13,12 @@ type ConferenceProvider interface {
 	// GetConferences retrieves all available conferences
 	GetConferences(ctx context.Context) ([]domain.Conference, error)
 }
+
+// ConferenceCatalog is a ConferenceProvider that caches the conference list and
+// can be told to drop it, so the next call fetches a fresh one
+type ConferenceCatalog interface {
+	ConferenceProvider
+
+	// Invalidate drops the cached conferences
+	Invalidate()
+}
@@ -2,14 +2,22 @@ package ports
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/javaBin/talks-indexer/internal/domain"
 )
 
 // SearchIndex defines the interface for Elasticsearch operations
 type SearchIndex interface {
-	// BulkIndex indexes multiple talks into the specified index
-	BulkIndex(ctx context.Context, indexName string, talks []domain.Talk) error
+	// BulkIndex indexes multiple talks into the specified index.
+	// Documents rejected by Elasticsearch are returned as failures in the result
+	// rather than as an error; the error is reserved for failed requests.
+	// Written documents are not visible to search until the index is refreshed.
+	BulkIndex(ctx context.Context, indexName string, talks []domain.Talk) (*domain.BulkResult, error)
+
+	// Refresh makes all writes to the indexes visible to search
+	Refresh(ctx context.Context, indexNames ...string) error
 
 	// DeleteIndex removes an index from Elasticsearch
 	DeleteIndex(ctx context.Context, indexName string) error
@@ -17,6 +25,75 @@ type SearchIndex interface {
 	// CreateIndex creates a new index with the specified mapping
 	CreateIndex(ctx context.Context, indexName string, mapping string) error
 
+	// CheckMapping compares the mapping an index, or every index behind an alias, was created
+	// with against the given mapping. It returns nil if they match or the index does not exist.
+	CheckMapping(ctx context.Context, indexName string, mapping string) (*domain.MappingDrift, error)
+
 	// IndexExists checks if an index exists in Elasticsearch
 	IndexExists(ctx context.Context, indexName string) (bool, error)
+
+	// GetAliasTargets returns the names of the indexes an alias points to
+	GetAliasTargets(ctx context.Context, alias string) ([]string, error)
+
+	// SwapAlias atomically points an alias at the given index, detaching it from any other index
+	SwapAlias(ctx context.Context, alias string, indexName string) error
+
+	// ListIndices returns the names of all indexes matching the given pattern
+	ListIndices(ctx context.Context, pattern string) ([]string, error)
+
+	// GetLastUpdated returns the lastUpdated timestamp of each indexed talk in a conference, keyed by talk ID
+	GetLastUpdated(ctx context.Context, indexName string, conferenceID string) (map[string]time.Time, error)
+
+	// ScanDocuments returns the source of every document in an index keyed by ID.
+	// If conferenceID is not empty only talks in that conference are returned.
+	ScanDocuments(ctx context.Context, indexName string, conferenceID string) (map[string]map[string]interface{}, error)
+
+	// BulkIndexSpeakers indexes speaker profiles with the speaker ID as document ID.
+	// Like BulkIndex, writes are not visible to search until the index is refreshed.
+	BulkIndexSpeakers(ctx context.Context, indexName string, speakers []domain.SpeakerProfile) (*domain.BulkResult, error)
+
+	// FindTalksBySpeakers returns every talk in an index with at least one of the given speakers
+	FindTalksBySpeakers(ctx context.Context, indexName string, speakerIDs []string) ([]domain.Talk, error)
+
+	// CountByConference returns the number of documents in an index per conference ID
+	CountByConference(ctx context.Context, indexName string) (map[string]int, error)
+
+	// Search runs a full-text search with filters against an index and returns one page of talks
+	Search(ctx context.Context, indexName string, query domain.SearchQuery) (*domain.SearchResult, error)
+
+	// Facets counts the talks matching a search per value of each facet field.
+	// The query's pagination is ignored.
+	Facets(ctx context.Context, indexName string, query domain.SearchQuery) (*domain.FacetResult, error)
+
+	// Suggest returns type-ahead completions for talk titles, speaker names and keywords
+	Suggest(ctx context.Context, indexName string, query domain.SuggestQuery) (*domain.Suggestions, error)
+
+	// GetDocument decodes a single document into v, returning false if it does not exist
+	GetDocument(ctx context.Context, indexName string, id string, v interface{}) (bool, error)
+
+	// PutDocument creates or replaces a single document
+	PutDocument(ctx context.Context, indexName string, id string, doc interface{}) error
+
+	// DeleteDocuments deletes documents by ID, ignoring IDs that do not exist, and returns
+	// the number deleted
+	DeleteDocuments(ctx context.Context, indexName string, ids []string) (int, error)
+
+	// DeleteConferenceTalksExcept deletes every talk of a conference except those with the
+	// given IDs and returns the number deleted
+	DeleteConferenceTalksExcept(ctx context.Context, indexName string, conferenceID string, keepIDs []string) (int, error)
+
+	// ExportIndex writes a snapshot of an index, or the single index behind an alias, to w
+	// as NDJSON: a header line with the index definition followed by one line per document
+	ExportIndex(ctx context.Context, indexName string, w io.Writer) (*domain.SnapshotHeader, error)
+
+	// ImportIndex creates a new index from a snapshot read from r and indexes its documents.
+	// Documents rejected by Elasticsearch are returned as failures in the result.
+	ImportIndex(ctx context.Context, indexName string, r io.Reader) (*domain.SnapshotResult, error)
+
+	// AcquireLock atomically takes or renews a lock document for owner until ttl expires.
+	// It returns false if the lock is held by another owner and has not expired.
+	AcquireLock(ctx context.Context, indexName string, lockID string, owner string, ttl time.Duration) (bool, error)
+
+	// ReleaseLock removes a lock document if it is still held by owner
+	ReleaseLock(ctx context.Context, indexName string, lockID string, owner string) error
 }
@@ -1,16 +1,31 @@
 package ports
 
-import "context"
+import (
+	"context"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
 
 // Indexer defines the interface for indexing operations.
 // This is implemented by the app layer IndexerService.
+// Every operation returns a report describing what was indexed and what was skipped.
+// With opts.DryRun the reindex operations write nothing and instead return the
+// changes they would make as diffs in the report.
 type Indexer interface {
 	// ReindexAll triggers a full reindex of all conferences
-	ReindexAll(ctx context.Context) error
+	ReindexAll(ctx context.Context, opts domain.ReindexOptions) (*domain.ReindexReport, error)
 
 	// ReindexConference reindexes a specific conference by its slug
-	ReindexConference(ctx context.Context, slug string) error
+	ReindexConference(ctx context.Context, slug string, opts domain.ReindexOptions) (*domain.ReindexReport, error)
 
 	// ReindexTalk reindexes a specific talk by its ID
-	ReindexTalk(ctx context.Context, talkID string) error
+	ReindexTalk(ctx context.Context, talkID string, opts domain.ReindexOptions) (*domain.ReindexReport, error)
+
+	// SyncChanges incrementally indexes talks that changed since the previous sync.
+	// The report's Sync field holds the created/updated/unchanged/deleted counts.
+	// opts.Force overrides the shrink guard; dry runs are not supported and are rejected.
+	SyncChanges(ctx context.Context, opts domain.ReindexOptions) (*domain.ReindexReport, error)
+
+	// UnmappedFields lists the fields of the source data that have no explicit mapping in the talk indexes
+	UnmappedFields(ctx context.Context) (*domain.UnmappedFieldReport, error)
 }
@@ -0,0 +1,19 @@
+package ports
+
+import "github.com/javaBin/talks-indexer/internal/domain"
+
+// JobManager defines the interface for running indexing operations as background jobs.
+// This is implemented by the app layer JobManager.
+type JobManager interface {
+	// Submit queues a new job and returns it, or returns an identical job that is already queued or running
+	Submit(req domain.JobRequest) (*domain.Job, error)
+
+	// Get returns a snapshot of the job with the given ID
+	Get(id string) (*domain.Job, error)
+
+	// List returns snapshots of all known jobs, newest first
+	List() []domain.Job
+
+	// Cancel stops a queued or running job
+	Cancel(id string) (*domain.Job, error)
+}
@@ -0,0 +1,10 @@
+package ports
+
+import "github.com/javaBin/talks-indexer/internal/domain"
+
+// Scheduler defines the interface for inspecting recurring indexing jobs.
+// This is implemented by the app layer Scheduler.
+type Scheduler interface {
+	// Status returns every configured schedule with its last and next run times
+	Status() []domain.ScheduleStatus
+}
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// Searcher defines the interface for searching indexed talks.
+// This is implemented by the app layer SearchService.
+type Searcher interface {
+	// SearchPublic searches the public index, which only holds approved talks without private data
+	SearchPublic(ctx context.Context, query domain.SearchQuery) (*domain.SearchResult, error)
+
+	// SearchPrivate searches the private index, which holds every talk including private data
+	SearchPrivate(ctx context.Context, query domain.SearchQuery) (*domain.SearchResult, error)
+
+	// FacetsPublic counts the talks in the public index matching a search per facet value
+	FacetsPublic(ctx context.Context, query domain.SearchQuery) (*domain.FacetResult, error)
+
+	// FacetsPrivate counts the talks in the private index matching a search per facet value
+	FacetsPrivate(ctx context.Context, query domain.SearchQuery) (*domain.FacetResult, error)
+
+	// SuggestPublic returns type-ahead completions from the talks in the public index
+	SuggestPublic(ctx context.Context, query domain.SuggestQuery) (*domain.Suggestions, error)
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/javaBin/talks-indexer/internal/domain"
+)
+
+// Snapshotter defines the interface for exporting and restoring index snapshots.
+// This is implemented by the app layer IndexerService.
+type Snapshotter interface {
+	// ExportSnapshot writes a snapshot of the index behind an alias to w
+	ExportSnapshot(ctx context.Context, alias string, w io.Writer) (*domain.SnapshotHeader, error)
+
+	// RestoreSnapshot restores a snapshot read from r into a fresh generation of an alias
+	// and points the alias at it. Moresleep is not contacted.
+	RestoreSnapshot(ctx context.Context, alias string, r io.Reader) (*domain.SnapshotResult, error)
+}